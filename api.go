@@ -9,24 +9,44 @@ type (
 	System          = core.System
 	ParticleManager = core.ParticleManager
 	ConfigLoader    = core.ConfigLoader
+	TextureRegistry = core.TextureRegistry
+	FontRegistry    = core.FontRegistry
+	Camera          = core.Camera
 )
 
 // Configuration types.
 type (
-	ParticleConfig   = core.ParticleConfig
-	ImageConfig      = core.ImageConfig
-	RenderConfig     = core.RenderConfig
-	BloomConfig      = core.BloomConfig
-	AfterimageConfig = core.AfterimageConfig
-	EmitterConfig    = core.EmitterConfig
-	AnimationConfig  = core.AnimationConfig
-	DurationConfig   = core.DurationConfig
-	RangeFloat       = core.RangeFloat
-	PositionConfig   = core.PositionConfig
-	PropertyConfig   = core.PropertyConfig
-	StepConfig       = core.StepConfig
-	ColorConfig      = core.ColorConfig
-	SpawnConfig      = core.SpawnConfig
+	ParticleConfig     = core.ParticleConfig
+	ImageConfig        = core.ImageConfig
+	RenderConfig       = core.RenderConfig
+	BloomConfig        = core.BloomConfig
+	AfterimageConfig   = core.AfterimageConfig
+	EmitterConfig      = core.EmitterConfig
+	AnimationConfig    = core.AnimationConfig
+	DurationConfig     = core.DurationConfig
+	RangeFloat         = core.RangeFloat
+	PositionConfig     = core.PositionConfig
+	PropertyConfig     = core.PropertyConfig
+	StepConfig         = core.StepConfig
+	CurveKeyConfig     = core.CurveKeyConfig
+	ColorConfig        = core.ColorConfig
+	ColorStopConfig    = core.ColorStopConfig
+	PaletteColorConfig = core.PaletteColorConfig
+	SpawnConfig        = core.SpawnConfig
+	BurstConfig        = core.BurstConfig
+	RangeInt           = core.RangeInt
+	SubEmitterConfig   = core.SubEmitterConfig
+	CollisionConfig    = core.CollisionConfig
+	ColliderConfig     = core.ColliderConfig
+)
+
+// Vector emitter types.
+type (
+	EmitterVectorConfig         = core.EmitterVectorConfig
+	EmitterVectorPolylineConfig = core.EmitterVectorPolylineConfig
+	EmitterVectorPoint          = core.EmitterVectorPoint
+	SVGPathOptions              = core.SVGPathOptions
+	EmitterVectorTextConfig     = core.EmitterVectorTextConfig
 )
 
 // Component/data types for ECS integration.
@@ -38,6 +58,38 @@ type (
 	ParticleStorage   = core.ParticleStorage
 	BloomEffect       = core.BloomEffect
 	PersistenceEffect = core.PersistenceEffect
+	Collider          = core.Collider
+	CollisionParams   = core.CollisionParams
+	ColorGradient     = core.ColorGradient
+	ColorStop         = core.ColorStop
+	ForceFieldData    = core.ForceFieldData
+	ForceFalloff      = core.ForceFalloff
+	AttachmentData    = core.AttachmentData
+
+	ParentRemovedPolicy = core.ParentRemovedPolicy
+	DrawSort            = core.DrawSort
+)
+
+// Force-field falloff modes.
+const (
+	ForceFalloffLinear    = core.ForceFalloffLinear
+	ForceFalloffNone      = core.ForceFalloffNone
+	ForceFalloffQuadratic = core.ForceFalloffQuadratic
+)
+
+// Attachment policies when the parent entity is removed.
+const (
+	ParentRemovedStop    = core.ParentRemovedStop
+	ParentRemovedDespawn = core.ParentRemovedDespawn
+)
+
+// Particle draw-order modes.
+const (
+	DrawSortNone          = core.DrawSortNone
+	DrawSortOldestFirst   = core.DrawSortOldestFirst
+	DrawSortYoungestFirst = core.DrawSortYoungestFirst
+	DrawSortByY           = core.DrawSortByY
+	DrawSortByScale       = core.DrawSortByScale
 )
 
 // Easing and sequence helpers.
@@ -46,16 +98,22 @@ type (
 	SequenceConfig   = core.SequenceConfig
 	SequenceStep     = core.SequenceStep
 	SequenceSnapshot = core.SequenceSnapshot
+	Curve            = core.Curve
+	CurveKey         = core.CurveKey
 )
 
 var (
 	// Component ECS component registration.
-	Component = core.Component
+	Component  = core.Component
+	ForceField = core.ForceField
+	Attachment = core.Attachment
 
 	// NewSystem Runtime constructors.
 	NewSystem            = core.NewSystem
 	NewParticleManager   = core.NewParticleManager
 	NewConfigLoader      = core.NewConfigLoader
+	NewTextureRegistry   = core.NewTextureRegistry
+	NewFontRegistry      = core.NewFontRegistry
 	NewBloomEffect       = core.NewBloomEffect
 	NewPersistenceEffect = core.NewPersistenceEffect
 
@@ -66,6 +124,29 @@ var (
 	// Runtime particle controls.
 	SetAttractor     = core.SetAttractor
 	SetEmissionScale = core.SetEmissionScale
+	SetTimeScale     = core.SetTimeScale
+	AddCollider      = core.AddCollider
+	ClearColliders   = core.ClearColliders
+	SetEmitterSprite = core.SetEmitterSprite
+	Attach           = core.Attach
+	Detach           = core.Detach
+
+	SetEmitterRotation = core.SetEmitterRotation
+	SetEmitterScale    = core.SetEmitterScale
+	SetRenderLayer     = core.SetRenderLayer
+
+	// NewPlaneCollider Collider constructors.
+	NewPlaneCollider  = core.NewPlaneCollider
+	NewRectCollider   = core.NewRectCollider
+	NewCircleCollider = core.NewCircleCollider
+	NewGridCollider   = core.NewGridCollider
+
+	// AddForceField Global force fields.
+	AddForceField           = core.AddForceField
+	NewPointForceField      = core.NewPointForceField
+	NewWindForceField       = core.NewWindForceField
+	NewVortexForceField     = core.NewVortexForceField
+	NewTurbulenceForceField = core.NewTurbulenceForceField
 
 	// ParseEasing Easing and sequence helpers.
 	ParseEasing       = core.ParseEasing
@@ -73,4 +154,12 @@ var (
 	NewSequenceConfig = core.NewSequenceConfig
 	GenerateSnapshot  = core.GenerateSnapshot
 	EvaluateSequence  = core.EvaluateSequence
+	NewCurve          = core.NewCurve
+
+	// ParseSVGPath SVG path import for vector emitters.
+	ParseSVGPath     = core.ParseSVGPath
+	NewSVGPathVector = core.NewSVGPathVector
+
+	// NewTextVector Font glyph outlines for vector emitters.
+	NewTextVector = core.NewTextVector
 )
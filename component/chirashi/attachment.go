@@ -0,0 +1,92 @@
+package chirashi
+
+import (
+	"math"
+
+	"github.com/yohamta/donburi"
+
+	"github.com/mogeta/chirashi/component"
+)
+
+// ParentRemovedPolicy decides what an attached effect does once its parent
+// entity is removed or loses its component.Position.
+type ParentRemovedPolicy int
+
+const (
+	// ParentRemovedStop stops emitting where the parent was last seen and
+	// removes the effect once its particles and trail have faded.
+	ParentRemovedStop ParentRemovedPolicy = iota
+	// ParentRemovedDespawn removes the effect immediately.
+	ParentRemovedDespawn
+)
+
+// AttachmentData binds a particle entity's emitter to the component.Position
+// of a parent entity. System.Update moves the emitter to the parent position
+// plus Offset, rotated by Rotation, before spawning, the same way
+// SetEmitterPosition does, and turns the emitter by Rotation on top of its own
+// spawn.rotation or SetEmitterRotation, so spawn offsets and directions turn
+// with the parent. Fields can be changed in place each frame.
+type AttachmentData struct {
+	Parent           donburi.Entity
+	OffsetX, OffsetY float32
+	Rotation         float32 // radians; rotates the offset and the emitter
+	OnParentRemoved  ParentRemovedPolicy
+
+	detached bool
+}
+
+// Attachment is the Donburi component type for emitter attachments.
+var Attachment = donburi.NewComponentType[AttachmentData]()
+
+// Attach binds the emitter of a particle entity to parent and moves it there
+// right away. Attaching again replaces the previous attachment.
+func Attach(world donburi.World, entity donburi.Entity, attachment AttachmentData) {
+	if !world.Valid(entity) {
+		return
+	}
+	entry := world.Entry(entity)
+	if !entry.HasComponent(Component) {
+		return
+	}
+	if !entry.HasComponent(Attachment) {
+		entry.AddComponent(Attachment)
+	}
+	Attachment.SetValue(entry, attachment)
+	followAttachment(world, Attachment.Get(entry), Component.Get(entry))
+}
+
+// Detach unbinds a particle entity from its parent. The emitter stays where
+// it is.
+func Detach(world donburi.World, entity donburi.Entity) {
+	if !world.Valid(entity) {
+		return
+	}
+	entry := world.Entry(entity)
+	if entry.HasComponent(Attachment) {
+		entry.RemoveComponent(Attachment)
+	}
+}
+
+// followAttachment moves and orients the emitter to its parent. It reports
+// false when the parent is gone and the effect should be removed now.
+func followAttachment(world donburi.World, attachment *AttachmentData, data *SystemData) bool {
+	if attachment.detached {
+		return true
+	}
+	if !world.Valid(attachment.Parent) || !world.Entry(attachment.Parent).HasComponent(component.Position) {
+		if attachment.OnParentRemoved == ParentRemovedDespawn {
+			return false
+		}
+		attachment.detached = true
+		data.IsLoop = false
+		data.LifeTime = 0
+		return true
+	}
+	parent := component.Position.Get(world.Entry(attachment.Parent))
+	sin, cos := math.Sincos(float64(attachment.Rotation))
+	offsetX := float64(attachment.OffsetX)*cos - float64(attachment.OffsetY)*sin
+	offsetY := float64(attachment.OffsetX)*sin + float64(attachment.OffsetY)*cos
+	moveEmitterTo(data, float32(parent.X+offsetX)+data.motionOffsetX, float32(parent.Y+offsetY)+data.motionOffsetY)
+	data.attachmentRotation = attachment.Rotation
+	return true
+}
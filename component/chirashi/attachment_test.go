@@ -0,0 +1,145 @@
+package chirashi
+
+import (
+	"math"
+	"testing"
+
+	"github.com/yohamta/donburi"
+	"github.com/yohamta/donburi/ecs"
+
+	"github.com/mogeta/chirashi/component"
+)
+
+func newAttachmentTestWorld(t *testing.T, policy ParentRemovedPolicy) (donburi.World, *ecs.ECS, donburi.Entity, donburi.Entity) {
+	t.Helper()
+	world := donburi.NewWorld()
+	parent := world.Create(component.Position, component.Velocity)
+	component.Position.SetValue(world.Entry(parent), component.PositionData{X: 100, Y: 50})
+
+	effect := world.Create(Component)
+	donburi.SetValue(world.Entry(effect), Component, SystemData{
+		ParticlePool: []Instance{
+			{Active: true, Duration: 10, StartX: 0, EndX: 0, PositionEasing: EasingLinear},
+		},
+		ActiveCount:       1,
+		MaxParticles:      1,
+		IsLoop:            true,
+		TimeScale:         1,
+		EmitterLocalSpace: true,
+	})
+	Attach(world, effect, AttachmentData{Parent: parent, OffsetX: 10, Rotation: 3.14159265 / 2, OnParentRemoved: policy})
+	return world, ecs.NewECS(world), parent, effect
+}
+
+func TestAttachedEmitterFollowsParentPosition(t *testing.T) {
+	world, gameECS, parent, effect := newAttachmentTestWorld(t, ParentRemovedStop)
+	data := Component.Get(world.Entry(effect))
+	if !nearFloat(data.EmitterX, 100) || !nearFloat(data.EmitterY, 60) {
+		t.Fatalf("expected Attach to snap to the rotated offset (100, 60), got (%v, %v)", data.EmitterX, data.EmitterY)
+	}
+
+	component.Position.Get(world.Entry(parent)).X = 130
+	NewSystem().Update(gameECS)
+	if !nearFloat(data.EmitterX, 130) || !nearFloat(data.EmitterY, 60) {
+		t.Fatalf("expected the emitter to follow the parent to (130, 60), got (%v, %v)", data.EmitterX, data.EmitterY)
+	}
+	if p := data.ParticlePool[0]; !nearFloat(p.StartX, 130) {
+		t.Fatalf("expected local-space particles to move with the emitter, got StartX %v", p.StartX)
+	}
+
+	Detach(world, effect)
+	component.Position.Get(world.Entry(parent)).X = 0
+	NewSystem().Update(gameECS)
+	if !nearFloat(data.EmitterX, 130) {
+		t.Fatalf("expected a detached emitter to stay put, got x %v", data.EmitterX)
+	}
+}
+
+func TestAttachedEmitterParentRemovedPolicies(t *testing.T) {
+	world, gameECS, parent, effect := newAttachmentTestWorld(t, ParentRemovedStop)
+	world.Remove(parent)
+	sys := NewSystem()
+	sys.Update(gameECS)
+	if !world.Valid(effect) {
+		t.Fatal("expected the stop policy to keep the effect while its particles live")
+	}
+	data := Component.Get(world.Entry(effect))
+	if data.IsLoop || data.LifeTime > 0 || !nearFloat(data.EmitterX, 100) {
+		t.Fatalf("expected emission to stop at the last parent position, got loop %v lifetime %v x %v", data.IsLoop, data.LifeTime, data.EmitterX)
+	}
+	data.ActiveCount = 0
+	sys.Update(gameECS)
+	if world.Valid(effect) {
+		t.Fatal("expected the effect to be removed once its particles expired")
+	}
+
+	world, gameECS, parent, effect = newAttachmentTestWorld(t, ParentRemovedDespawn)
+	world.Entry(parent).RemoveComponent(component.Position)
+	NewSystem().Update(gameECS)
+	if world.Valid(effect) {
+		t.Fatal("expected the despawn policy to remove the effect right away")
+	}
+}
+
+func TestAttachedEmitterRotationOrientsSpawns(t *testing.T) {
+	world := donburi.NewWorld()
+	parent := world.Create(component.Position)
+	component.Position.SetValue(world.Entry(parent), component.PositionData{X: 100, Y: 50})
+	effect := world.Create(Component)
+	donburi.SetValue(world.Entry(effect), Component, SystemData{
+		ParticlePool:      make([]Instance, 1),
+		SpawnInterval:     1,
+		ParticlesPerSpawn: 1,
+		MaxParticles:      1,
+		EmissionScale:     1,
+		IsLoop:            true,
+		TimeScale:         1,
+		AnimParams: AnimationParams{
+			Duration: DurationParams{Base: 10},
+			Position: PositionParams{EndXMin: 10, EndXMax: 10},
+		},
+	})
+
+	Attach(world, effect, AttachmentData{Parent: parent, Rotation: math.Pi / 2})
+	NewSystem().Update(ecs.NewECS(world))
+	data := Component.Get(world.Entry(effect))
+	if data.ActiveCount != 1 {
+		t.Fatalf("expected one spawned particle, got %d", data.ActiveCount)
+	}
+	// A quarter turn sends the +x end offset down +y.
+	if p := &data.ParticlePool[0]; !nearPoint(p.EndX, p.EndY, 100, 60) {
+		t.Fatalf("expected the particle to end at the rotated (100, 60), got (%v, %v)", p.EndX, p.EndY)
+	}
+}
+
+func TestAttachmentRotationAddsToEmitterRotation(t *testing.T) {
+	world := donburi.NewWorld()
+	parent := world.Create(component.Position)
+	component.Position.SetValue(world.Entry(parent), component.PositionData{X: 100, Y: 50})
+	effect := world.Create(Component)
+	donburi.SetValue(world.Entry(effect), Component, SystemData{
+		ParticlePool:      make([]Instance, 1),
+		SpawnInterval:     1,
+		ParticlesPerSpawn: 1,
+		MaxParticles:      1,
+		EmissionScale:     1,
+		IsLoop:            true,
+		TimeScale:         1,
+		AnimParams: AnimationParams{
+			Duration: DurationParams{Base: 10},
+			Position: PositionParams{EndXMin: 10, EndXMax: 10},
+		},
+	})
+
+	SetEmitterRotation(world, effect, math.Pi/4)
+	Attach(world, effect, AttachmentData{Parent: parent, Rotation: math.Pi / 4})
+	NewSystem().Update(ecs.NewECS(world))
+	data := Component.Get(world.Entry(effect))
+	if data.EmitterRotation != math.Pi/4 {
+		t.Fatalf("expected the attachment to keep the runtime rotation, got %v", data.EmitterRotation)
+	}
+	// Two eighth turns add up to a quarter turn.
+	if p := &data.ParticlePool[0]; !nearPoint(p.EndX, p.EndY, 100, 60) {
+		t.Fatalf("expected the particle to end at the rotated (100, 60), got (%v, %v)", p.EndX, p.EndY)
+	}
+}
@@ -86,8 +86,7 @@ func BenchmarkSpawnWithSequences(b *testing.B) {
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		data.ActiveCount = 0
-		sys.cnt = 0
-		sys.spawn(data)
+		sys.spawn(data, defaultDeltaTime)
 	}
 }
 
@@ -140,18 +139,59 @@ func BenchmarkChurnFlow4000(b *testing.B) {
 	}
 	// Reach steady state (spawn/expire equilibrium) before measuring.
 	for i := 0; i < 240; i++ {
-		sys.cnt++
 		data.CurrentTime += defaultDeltaTime
-		sys.spawn(data)
+		sys.spawn(data, defaultDeltaTime)
 		sys.updateParticles(data, defaultDeltaTime)
 	}
 
 	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		sys.cnt++
 		data.CurrentTime += defaultDeltaTime
-		sys.spawn(data)
+		sys.spawn(data, defaultDeltaTime)
 		sys.updateParticles(data, defaultDeltaTime)
 	}
 }
+
+// BenchmarkParticleDrawOrderByY10000 measures the per-draw sort of a large
+// effect, with pool order unrelated to the sort key.
+func BenchmarkParticleDrawOrderByY10000(b *testing.B) {
+	sys, data := newFlowBenchData(10000)
+	sys.updateParticles(data, defaultDeltaTime)
+	for i := range data.ParticlePool {
+		data.ParticlePool[i].SpawnSeq = uint64(len(data.ParticlePool) - i)
+	}
+	data.Sort = DrawSortByY
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		particleDrawOrder(data)
+	}
+}
+
+// BenchmarkParticleTintOKLab10000 measures the per-draw color evaluation of
+// a start/end pair interpolated in OKLab.
+func BenchmarkParticleTintOKLab10000(b *testing.B) {
+	clr := ColorParams{
+		Enabled: true,
+		StartR:  1,
+		StartG:  0.8,
+		StartB:  0.2,
+		EndR:    0.2,
+		EndG:    0.1,
+		EndB:    0.6,
+		Space:   ColorSpaceOKLab,
+		Easing:  EasingLinear,
+	}
+	pool := make([]Instance, 10000)
+	for i := range pool {
+		applyParticleColor(&pool[i], &clr)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j := range pool {
+			particleTint(&pool[j], float32(j)/float32(len(pool)))
+		}
+	}
+}
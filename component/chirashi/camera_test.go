@@ -0,0 +1,69 @@
+package chirashi
+
+import (
+	"math"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/yohamta/donburi"
+	"github.com/yohamta/donburi/ecs"
+)
+
+func TestViewTransformMapsWorldToScreen(t *testing.T) {
+	if view := newViewTransform(Camera{X: 50, Y: 20, OriginX: 50, OriginY: 20}); view.enabled {
+		t.Fatal("expected a camera that maps every point onto itself to be the identity")
+	}
+
+	view := newViewTransform(Camera{X: 100, Y: 100, OriginX: 320, OriginY: 240, Zoom: 2, Rotation: math.Pi / 2})
+	// 10 units right of the camera is 20 pixels up once the camera turns
+	// a quarter turn clockwise.
+	if x, y := view.apply(110, 100); !nearPoint(x, y, 320, 220) {
+		t.Fatalf("got (%v, %v), want (320, 220)", x, y)
+	}
+	if x, y := view.apply(100, 100); !nearPoint(x, y, 320, 240) {
+		t.Fatalf("camera point got (%v, %v), want the origin (320, 240)", x, y)
+	}
+}
+
+func TestCameraTransformsTrailMesh(t *testing.T) {
+	data := &SystemData{
+		CurrentTime: 1,
+		Trail: TrailData{
+			Params: TrailParams{Enabled: true, MaxPointAge: 10, WidthStart: 4, WidthEnd: 4, AlphaStart: 1, AlphaEnd: 1},
+			Runtime: TrailRuntime{
+				Points: []TrailPoint{{X: 0, CapturedAt: 1}, {X: 10, CapturedAt: 1}},
+			},
+		},
+	}
+	view := newViewTransform(Camera{X: 0, Y: 0, OriginX: 100, OriginY: 50, Zoom: 3})
+	buildEmitterTrailMesh(data, &view)
+	v := data.Trail.Runtime.Vertices
+	if !nearPoint(v[0].DstX, v[0].DstY, 100, 44) || !nearPoint(v[3].DstX, v[3].DstY, 130, 56) {
+		t.Fatalf("trail vertices got (%v, %v) and (%v, %v), want (100, 44) and (130, 56)", v[0].DstX, v[0].DstY, v[3].DstX, v[3].DstY)
+	}
+}
+
+func TestDrawWithCameraTransformsParticleQuads(t *testing.T) {
+	world := donburi.NewWorld()
+	entity := world.Create(Component)
+	donburi.SetValue(world.Entry(entity), Component, SystemData{
+		SourceImage: ebiten.NewImage(4, 4),
+		ImageWidth:  4,
+		ImageHeight: 4,
+		ParticlePool: []Instance{
+			{Active: true, Duration: 1, CurrentX: 10, CurrentY: 0, CurrentPosValid: true, StartScale: 1, EndScale: 1, StartAlpha: 1, EndAlpha: 1},
+		},
+		ActiveCount: 1,
+	})
+
+	sys := NewSystem()
+	sys.SetCamera(Camera{OriginX: 100, OriginY: 100, Zoom: 2})
+	sys.Draw(ecs.NewECS(world), ebiten.NewImage(8, 8))
+
+	// The batch is flushed after drawing; its vertices stay in the buffer.
+	data := Component.Get(world.Entry(entity))
+	v := data.Vertices[:4]
+	if !nearPoint(v[0].DstX, v[0].DstY, 116, 96) || !nearPoint(v[3].DstX, v[3].DstY, 124, 104) {
+		t.Fatalf("quad corners got (%v, %v) and (%v, %v), want (116, 96) and (124, 104)", v[0].DstX, v[0].DstY, v[3].DstX, v[3].DstY)
+	}
+}
@@ -0,0 +1,476 @@
+package chirashi
+
+import (
+	"math"
+
+	"github.com/yohamta/donburi"
+)
+
+// collisionSkin pushes resolved particles slightly off the contact surface so
+// the next update does not report the same contact again.
+const collisionSkin = float32(0.01)
+
+// collisionRestSpeed is the bounce speed (units/sec) below which a bouncing
+// particle comes to rest on the surface.
+const collisionRestSpeed = float32(20)
+
+// ColliderType identifies the shape of a Collider.
+type ColliderType int
+
+const (
+	ColliderPlane ColliderType = iota
+	ColliderRect
+	ColliderCircle
+	ColliderGrid
+)
+
+// Collider is a solid shape in world space. Build one with NewPlaneCollider,
+// NewRectCollider, NewCircleCollider or NewGridCollider.
+type Collider struct {
+	Type ColliderType
+	// X/Y is a point on the plane, the rect or circle center, or the grid
+	// origin (the top-left corner of cell 0,0).
+	X, Y                  float32
+	NormalX, NormalY      float32 // plane: unit normal pointing to the open side
+	HalfWidth, HalfHeight float32 // rect
+	Radius                float32 // circle
+	CellSize              float32 // grid
+	// Solid reports whether a grid cell blocks particles. Entities with a
+	// grid collider are simulated on the calling goroutine only, so Solid
+	// can read game state such as a tilemap without locking.
+	Solid func(cellX, cellY int) bool
+}
+
+// CollisionResponse selects what happens to a particle that hits a collider.
+type CollisionResponse int
+
+const (
+	CollisionBounce CollisionResponse = iota
+	CollisionStick
+	CollisionKill
+)
+
+// CollisionParams stores the normalized collision response.
+type CollisionParams struct {
+	Response    CollisionResponse
+	Restitution float32 // share of the normal speed kept on bounce
+	Friction    float32 // share of the tangential speed lost on bounce, 0-1
+}
+
+// NewPlaneCollider returns a half-plane through (x, y). Everything behind the
+// normal (nx, ny) is solid; the normal does not need to be unit length.
+func NewPlaneCollider(x, y, nx, ny float32) Collider {
+	length := float32(math.Hypot(float64(nx), float64(ny)))
+	if length == 0 {
+		nx, ny, length = 0, -1, 1
+	}
+	return Collider{Type: ColliderPlane, X: x, Y: y, NormalX: nx / length, NormalY: ny / length}
+}
+
+// NewRectCollider returns a solid axis-aligned rectangle centered on (x, y).
+func NewRectCollider(x, y, width, height float32) Collider {
+	return Collider{Type: ColliderRect, X: x, Y: y, HalfWidth: width / 2, HalfHeight: height / 2}
+}
+
+// NewCircleCollider returns a solid circle centered on (x, y).
+func NewCircleCollider(x, y, radius float32) Collider {
+	return Collider{Type: ColliderCircle, X: x, Y: y, Radius: radius}
+}
+
+// NewGridCollider returns a tile grid whose top-left corner is (originX,
+// originY). solid is queried with cell coordinates, e.g. a tilemap lookup.
+func NewGridCollider(originX, originY, cellSize float32, solid func(cellX, cellY int) bool) Collider {
+	return Collider{Type: ColliderGrid, X: originX, Y: originY, CellSize: cellSize, Solid: solid}
+}
+
+// AddCollider registers a world-space collider on a particle entity.
+func AddCollider(world donburi.World, entity donburi.Entity, collider Collider) {
+	if !world.Valid(entity) {
+		return
+	}
+	entry := world.Entry(entity)
+	if !entry.HasComponent(Component) {
+		return
+	}
+	data := Component.Get(entry)
+	data.Colliders = append(data.Colliders, collider)
+}
+
+// ClearColliders removes every collider from a particle entity, including
+// the ones defined in YAML.
+func ClearColliders(world donburi.World, entity donburi.Entity) {
+	if !world.Valid(entity) {
+		return
+	}
+	entry := world.Entry(entity)
+	if !entry.HasComponent(Component) {
+		return
+	}
+	data := Component.Get(entry)
+	data.Colliders = data.Colliders[:0]
+	data.configColliders = 0
+}
+
+func buildCollisionParams(config *CollisionConfig) CollisionParams {
+	if config == nil {
+		return CollisionParams{}
+	}
+	params := CollisionParams{
+		Restitution: config.Restitution,
+		Friction:    config.Friction,
+	}
+	switch config.Response {
+	case "stick":
+		params.Response = CollisionStick
+	case "kill":
+		params.Response = CollisionKill
+	default:
+		params.Response = CollisionBounce
+	}
+	return params
+}
+
+// buildConfigColliders converts YAML colliders, which are relative to the
+// emitter origin, into world space.
+func buildConfigColliders(config *CollisionConfig, originX, originY float32) []Collider {
+	if config == nil || len(config.Colliders) == 0 {
+		return nil
+	}
+	colliders := make([]Collider, 0, len(config.Colliders))
+	for _, c := range config.Colliders {
+		x := originX + c.X
+		y := originY + c.Y
+		switch c.Type {
+		case "plane":
+			colliders = append(colliders, NewPlaneCollider(x, y, c.NormalX, c.NormalY))
+		case "rect":
+			colliders = append(colliders, NewRectCollider(x, y, c.Width, c.Height))
+		case "circle":
+			colliders = append(colliders, NewCircleCollider(x, y, c.Radius))
+		}
+	}
+	return colliders
+}
+
+// applyCollisionConfig replaces the YAML-defined colliders and response while
+// keeping colliders registered at runtime with AddCollider.
+func applyCollisionConfig(data *SystemData, config *CollisionConfig, originX, originY float32) {
+	configColliders := buildConfigColliders(config, originX, originY)
+	runtime := data.Colliders[minInt(data.configColliders, len(data.Colliders)):]
+	data.Colliders = append(configColliders, runtime...)
+	data.configColliders = len(configColliders)
+	data.Collision = buildCollisionParams(config)
+}
+
+// hasGridCollider reports whether any collider calls back into game code.
+func hasGridCollider(colliders []Collider) bool {
+	for i := range colliders {
+		if colliders[i].Type == ColliderGrid {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveParticleCollision tests the particle's movement since the previous
+// update against every collider and applies the configured response to the
+// earliest contact along it, so fast particles cannot tunnel through thin
+// shapes. A colliding particle switches to integrated motion so it can
+// leave its closed-form path. It only touches p, so it is safe to call from
+// the parallel simulation phase as long as no collider is a grid.
+func resolveParticleCollision(data *SystemData, p *Instance, elapsed float32) {
+	hit := false
+	var hitT, contactX, contactY, nx, ny float32
+	for i := range data.Colliders {
+		t, cx, cy, cnx, cny, ok := data.Colliders[i].sweep(p.PrevX, p.PrevY, p.CurrentX, p.CurrentY)
+		if ok && (!hit || t < hitT) {
+			hit = true
+			hitT, contactX, contactY, nx, ny = t, cx, cy, cnx, cny
+		}
+	}
+	if !hit {
+		p.InContact = false
+		return
+	}
+	// Only a new contact fires collision sub-emitters; a particle resting on
+	// a surface touches it on every update.
+	p.Collided = !p.InContact
+	p.InContact = true
+	if data.Collision.Response == CollisionKill {
+		// Expire the particle in this update's sweep.
+		p.Duration = elapsed
+		return
+	}
+
+	vx, vy := particleVelocity(data, p)
+	if data.Collision.Response == CollisionStick {
+		vx, vy = 0, 0
+		p.Stuck = true
+	} else if vn := vx*nx + vy*ny; vn < 0 {
+		keep := 1 - clamp01(data.Collision.Friction)
+		tx := vx - vn*nx
+		ty := vy - vn*ny
+		bounce := -vn * data.Collision.Restitution
+		if bounce < collisionRestSpeed {
+			// Too slow to visibly bounce: rest on the surface instead of
+			// hopping off it every update.
+			bounce = 0
+		}
+		vx = tx*keep + bounce*nx
+		vy = ty*keep + bounce*ny
+	}
+
+	contactX += nx * collisionSkin
+	contactY += ny * collisionSkin
+	p.Integrated = true
+	p.PosX = contactX
+	p.PosY = contactY
+	if p.HasFlow {
+		p.PosX -= p.FlowOffsetX
+		p.PosY -= p.FlowOffsetY
+	}
+	p.VelX = vx
+	p.VelY = vy
+	p.CurrentX = contactX
+	p.CurrentY = contactY
+}
+
+// sweep reports where the segment from (prevX, prevY) to (x, y) first
+// enters the collider: the fraction t of the segment, the contact point and
+// the outward surface normal there. A segment that already starts inside is
+// pushed out of the nearest face of its end point at t = 0.
+func (c *Collider) sweep(prevX, prevY, x, y float32) (t, contactX, contactY, nx, ny float32, hit bool) {
+	switch c.Type {
+	case ColliderPlane:
+		d0 := (prevX-c.X)*c.NormalX + (prevY-c.Y)*c.NormalY
+		d1 := (x-c.X)*c.NormalX + (y-c.Y)*c.NormalY
+		if d1 >= 0 {
+			return 0, 0, 0, 0, 0, false
+		}
+		if d0 <= 0 {
+			return 0, x - d1*c.NormalX, y - d1*c.NormalY, c.NormalX, c.NormalY, true
+		}
+		t = d0 / (d0 - d1)
+		return t, lerp(prevX, x, t), lerp(prevY, y, t), c.NormalX, c.NormalY, true
+	case ColliderCircle:
+		return c.sweepCircle(prevX, prevY, x, y)
+	case ColliderRect:
+		return c.sweepRect(prevX, prevY, x, y)
+	case ColliderGrid:
+		return c.sweepGrid(prevX, prevY, x, y)
+	default:
+		return 0, 0, 0, 0, 0, false
+	}
+}
+
+func (c *Collider) sweepCircle(prevX, prevY, x, y float32) (t, contactX, contactY, nx, ny float32, hit bool) {
+	r2 := c.Radius * c.Radius
+	ox, oy := prevX-c.X, prevY-c.Y
+	if ox*ox+oy*oy < r2 {
+		// Started inside: push the end point out, unless it already left.
+		dx, dy := x-c.X, y-c.Y
+		distSq := dx*dx + dy*dy
+		if distSq >= r2 {
+			return 0, 0, 0, 0, 0, false
+		}
+		if distSq == 0 {
+			dx, dy, distSq = ox, oy, ox*ox+oy*oy
+			if distSq == 0 {
+				dx, dy, distSq = 0, -1, 1
+			}
+		}
+		dist := float32(math.Sqrt(float64(distSq)))
+		nx, ny = dx/dist, dy/dist
+		return 0, c.X + nx*c.Radius, c.Y + ny*c.Radius, nx, ny, true
+	}
+	// Solve |o + t*d|^2 = r^2 for the entry point.
+	dx, dy := x-prevX, y-prevY
+	a := dx*dx + dy*dy
+	if a == 0 {
+		return 0, 0, 0, 0, 0, false
+	}
+	b := ox*dx + oy*dy
+	disc := b*b - a*(ox*ox+oy*oy-r2)
+	if b >= 0 || disc < 0 {
+		return 0, 0, 0, 0, 0, false
+	}
+	t = (-b - float32(math.Sqrt(float64(disc)))) / a
+	if t < 0 || t > 1 {
+		return 0, 0, 0, 0, 0, false
+	}
+	nx, ny = (ox+dx*t)/c.Radius, (oy+dy*t)/c.Radius
+	return t, c.X + nx*c.Radius, c.Y + ny*c.Radius, nx, ny, true
+}
+
+func (c *Collider) sweepRect(prevX, prevY, x, y float32) (t, contactX, contactY, nx, ny float32, hit bool) {
+	if absFloat32(prevX-c.X) < c.HalfWidth && absFloat32(prevY-c.Y) < c.HalfHeight {
+		// Started inside: push the end point out through the nearest face.
+		dx, dy := x-c.X, y-c.Y
+		if absFloat32(dx) >= c.HalfWidth || absFloat32(dy) >= c.HalfHeight {
+			return 0, 0, 0, 0, 0, false
+		}
+		if c.HalfWidth-absFloat32(dx) < c.HalfHeight-absFloat32(dy) {
+			nx = signFloat32(dx)
+			return 0, c.X + nx*c.HalfWidth, y, nx, 0, true
+		}
+		ny = signFloat32(dy)
+		return 0, x, c.Y + ny*c.HalfHeight, 0, ny, true
+	}
+	// Slab test: the segment is inside the rect where both axis intervals
+	// overlap; it enters on the axis whose interval starts last.
+	tEnter, tExit := float32(-1), float32(1)
+	enterX := false
+	slab := func(p, d, center, half float32, isX bool) bool {
+		if d == 0 {
+			return absFloat32(p-center) < half
+		}
+		t0 := (center - half - p) / d
+		t1 := (center + half - p) / d
+		if t0 > t1 {
+			t0, t1 = t1, t0
+		}
+		if t0 > tEnter {
+			tEnter = t0
+			enterX = isX
+		}
+		tExit = min(tExit, t1)
+		return true
+	}
+	dx, dy := x-prevX, y-prevY
+	if !slab(prevX, dx, c.X, c.HalfWidth, true) || !slab(prevY, dy, c.Y, c.HalfHeight, false) || tEnter < 0 || tEnter >= tExit {
+		return 0, 0, 0, 0, 0, false
+	}
+	contactX, contactY = prevX+dx*tEnter, prevY+dy*tEnter
+	if enterX {
+		nx = -signFloat32(dx)
+		return tEnter, c.X + nx*c.HalfWidth, contactY, nx, 0, true
+	}
+	ny = -signFloat32(dy)
+	return tEnter, contactX, c.Y + ny*c.HalfHeight, 0, ny, true
+}
+
+// maxGridSweepCells bounds the cells one sweep walks through, so a particle
+// teleported across a huge grid costs a fixed amount.
+const maxGridSweepCells = 256
+
+func (c *Collider) sweepGrid(prevX, prevY, x, y float32) (t, contactX, contactY, nx, ny float32, hit bool) {
+	if c.Solid == nil || c.CellSize <= 0 {
+		return 0, 0, 0, 0, 0, false
+	}
+	cellX, cellY := c.cellAt(prevX, prevY)
+	if c.Solid(cellX, cellY) {
+		endX, endY := c.cellAt(x, y)
+		if !c.Solid(endX, endY) {
+			return 0, 0, 0, 0, 0, false
+		}
+		contactX, contactY, nx, ny = c.gridPushOut(endX, endY, x, y)
+		return 0, contactX, contactY, nx, ny, true
+	}
+
+	// Walk the cells the segment crosses (Amanatides-Woo) until one is solid.
+	dx, dy := x-prevX, y-prevY
+	endX, endY := c.cellAt(x, y)
+	stepX, stepY := 0, 0
+	tMaxX, tMaxY := float32(math.Inf(1)), float32(math.Inf(1))
+	tDeltaX, tDeltaY := float32(math.Inf(1)), float32(math.Inf(1))
+	if dx != 0 {
+		stepX = int(signFloat32(dx))
+		boundary := c.X + float32(cellX)*c.CellSize
+		if stepX > 0 {
+			boundary += c.CellSize
+		}
+		tMaxX = (boundary - prevX) / dx
+		tDeltaX = c.CellSize / absFloat32(dx)
+	}
+	if dy != 0 {
+		stepY = int(signFloat32(dy))
+		boundary := c.Y + float32(cellY)*c.CellSize
+		if stepY > 0 {
+			boundary += c.CellSize
+		}
+		tMaxY = (boundary - prevY) / dy
+		tDeltaY = c.CellSize / absFloat32(dy)
+	}
+	for range maxGridSweepCells {
+		if cellX == endX && cellY == endY {
+			return 0, 0, 0, 0, 0, false
+		}
+		if tMaxX < tMaxY {
+			t = tMaxX
+			tMaxX += tDeltaX
+			cellX += stepX
+			nx, ny = float32(-stepX), 0
+		} else {
+			t = tMaxY
+			tMaxY += tDeltaY
+			cellY += stepY
+			nx, ny = 0, float32(-stepY)
+		}
+		if t > 1 {
+			return 0, 0, 0, 0, 0, false
+		}
+		if c.Solid(cellX, cellY) {
+			return t, prevX + dx*t, prevY + dy*t, nx, ny, true
+		}
+	}
+	return 0, 0, 0, 0, 0, false
+}
+
+// gridPushOut returns the nearest face of the solid cell (cellX, cellY) that
+// borders an open cell, for a point (x, y) inside it.
+func (c *Collider) gridPushOut(cellX, cellY int, x, y float32) (float32, float32, float32, float32) {
+	left := c.X + float32(cellX)*c.CellSize
+	top := c.Y + float32(cellY)*c.CellSize
+	right := left + c.CellSize
+	bottom := top + c.CellSize
+
+	type face struct {
+		dist         float32
+		x, y, nx, ny float32
+		open         bool
+	}
+	faces := [4]face{
+		{x - left, left, y, -1, 0, !c.Solid(cellX-1, cellY)},
+		{right - x, right, y, 1, 0, !c.Solid(cellX+1, cellY)},
+		{y - top, x, top, 0, -1, !c.Solid(cellX, cellY-1)},
+		{bottom - y, x, bottom, 0, 1, !c.Solid(cellX, cellY+1)},
+	}
+	best := -1
+	for i, f := range faces {
+		if !f.open {
+			continue
+		}
+		if best < 0 || f.dist < faces[best].dist {
+			best = i
+		}
+	}
+	if best < 0 {
+		// Buried in solid cells: use the nearest face anyway.
+		best = 0
+		for i, f := range faces {
+			if f.dist < faces[best].dist {
+				best = i
+			}
+		}
+	}
+	f := faces[best]
+	return f.x, f.y, f.nx, f.ny
+}
+
+func (c *Collider) cellAt(x, y float32) (int, int) {
+	return int(math.Floor(float64((x - c.X) / c.CellSize))), int(math.Floor(float64((y - c.Y) / c.CellSize)))
+}
+
+func absFloat32(v float32) float32 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func signFloat32(v float32) float32 {
+	if v < 0 {
+		return -1
+	}
+	return 1
+}
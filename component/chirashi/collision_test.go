@@ -0,0 +1,209 @@
+package chirashi
+
+import (
+	"math"
+	"runtime"
+	"sync/atomic"
+	"testing"
+)
+
+// fallingParticleSystemForTest returns a system with one particle moving
+// straight down from y=0 to y=100 over one second.
+func fallingParticleSystemForTest(collision CollisionParams, colliders ...Collider) *SystemData {
+	return &SystemData{
+		ParticlePool: []Instance{{
+			Active:          true,
+			Duration:        1,
+			EndY:            100,
+			CurrentPosValid: true,
+		}},
+		ActiveCount: 1,
+		Colliders:   colliders,
+		Collision:   collision,
+	}
+}
+
+func stepParticlesForTest(sys *System, data *SystemData, steps int) {
+	for i := 0; i < steps; i++ {
+		data.CurrentTime += defaultDeltaTime
+		sys.updateParticles(data, defaultDeltaTime)
+	}
+}
+
+func TestCollisionBouncesParticleOffPlane(t *testing.T) {
+	sys := &System{}
+	data := fallingParticleSystemForTest(
+		CollisionParams{Response: CollisionBounce, Restitution: 0.5},
+		NewPlaneCollider(0, 50, 0, -1),
+	)
+
+	stepParticlesForTest(sys, data, 32)
+
+	p := &data.ParticlePool[0]
+	if !p.Integrated {
+		t.Fatal("expected particle to switch to integrated motion after hitting the plane")
+	}
+	if p.VelY >= 0 {
+		t.Fatalf("expected bounce to reverse vertical velocity, got %v", p.VelY)
+	}
+	if got, want := p.VelY, float32(-50); got < want-1 || got > want+1 {
+		t.Fatalf("bounce speed got %v, want about %v (restitution 0.5 of 100/s)", got, want)
+	}
+	if p.CurrentY > 50 {
+		t.Fatalf("particle is below the plane: y=%v", p.CurrentY)
+	}
+}
+
+func TestRestingParticleFiresCollisionTriggerOnce(t *testing.T) {
+	sys := &System{}
+	data := physicsSystemForTest(PositionParams{AccelY: 600})
+	data.Colliders = []Collider{NewPlaneCollider(0, 50, 0, -1)}
+	data.Collision = CollisionParams{Response: CollisionBounce, Restitution: 0.3}
+	data.SubEmitters = []SubEmitterParams{{Preset: "impact", Trigger: SubEmitterOnCollision, Probability: 1}}
+	data.subEmitterHost = NewParticleManager(nil, nil)
+	sys.spawn(data, defaultDeltaTime)
+
+	// Falling 50 units takes about 0.4s; then the particle settles.
+	stepParticlesForTest(sys, data, 300)
+	p := &data.ParticlePool[0]
+	if p.CurrentY > 50 || p.CurrentY < 49.5 || math.Abs(float64(p.VelY)) > 20 {
+		t.Fatalf("expected the particle to rest on the plane, got y=%v vy=%v", p.CurrentY, p.VelY)
+	}
+	if n := len(sys.pendingSubEmitters); n == 0 || n > 3 {
+		t.Fatalf("expected one trigger per real bounce, got %d over 300 updates", n)
+	}
+}
+
+func TestCollisionStickKeepsParticleAtContact(t *testing.T) {
+	sys := &System{}
+	data := fallingParticleSystemForTest(
+		CollisionParams{Response: CollisionStick},
+		NewRectCollider(0, 60, 40, 20),
+	)
+
+	stepParticlesForTest(sys, data, 40)
+	p := &data.ParticlePool[0]
+	if !p.Stuck {
+		t.Fatal("expected particle to stick to the rect")
+	}
+	y := p.CurrentY
+	stepParticlesForTest(sys, data, 10)
+	if p.CurrentY != y || y > 50 || y < 49.9 {
+		t.Fatalf("stuck particle moved or missed the top face: before=%v after=%v", y, p.CurrentY)
+	}
+}
+
+func TestCollisionKillRemovesParticle(t *testing.T) {
+	sys := &System{}
+	data := fallingParticleSystemForTest(
+		CollisionParams{Response: CollisionKill},
+		NewCircleCollider(0, 60, 10),
+	)
+
+	stepParticlesForTest(sys, data, 40)
+
+	if data.ActiveCount != 0 {
+		t.Fatalf("expected particle to be killed by the circle, active=%d", data.ActiveCount)
+	}
+}
+
+func TestGridColliderUsesCrossedFace(t *testing.T) {
+	solid := func(cellX, cellY int) bool { return cellY >= 3 }
+	grid := NewGridCollider(0, 0, 16, solid)
+
+	tHit, x, y, nx, ny, hit := grid.sweep(5, 40, 5, 50)
+	if !hit {
+		t.Fatal("expected a hit entering a solid cell")
+	}
+	if !nearFloat(tHit, 0.8) || x != 5 || y != 48 || nx != 0 || ny != -1 {
+		t.Fatalf("got t %v contact (%v, %v) normal (%v, %v), want t 0.8 at (5, 48) normal (0, -1)", tHit, x, y, nx, ny)
+	}
+	if _, _, _, _, _, hit := grid.sweep(5, 30, 5, 40); hit {
+		t.Fatal("expected no hit in an open cell")
+	}
+}
+
+func TestCollisionSweepCatchesFastParticles(t *testing.T) {
+	colliders := map[string]Collider{
+		"rect":   NewRectCollider(0, 50, 40, 4),
+		"circle": NewCircleCollider(0, 50, 2),
+		"grid":   NewGridCollider(-8, 0, 4, func(cellX, cellY int) bool { return cellY == 12 }),
+	}
+	for name, collider := range colliders {
+		sys := &System{}
+		data := fallingParticleSystemForTest(CollisionParams{Response: CollisionStick}, collider)
+		// 6000 units over one second crosses the thin shape in a single tick.
+		data.ParticlePool[0].EndY = 6000
+
+		stepParticlesForTest(sys, data, 1)
+		p := &data.ParticlePool[0]
+		if !p.Stuck || p.CurrentY > 48 || p.CurrentY < 47.9 {
+			t.Fatalf("%s: expected the particle to stop on the near face at y=48, got stuck=%v y=%v", name, p.Stuck, p.CurrentY)
+		}
+	}
+}
+
+func TestCollisionUsesEarliestContact(t *testing.T) {
+	sys := &System{}
+	data := fallingParticleSystemForTest(
+		CollisionParams{Response: CollisionStick},
+		NewPlaneCollider(0, 90, 0, -1),
+		NewRectCollider(0, 30, 40, 2),
+	)
+	data.ParticlePool[0].EndY = 6000
+
+	stepParticlesForTest(sys, data, 1)
+	if y := data.ParticlePool[0].CurrentY; y > 29 || y < 28.9 {
+		t.Fatalf("expected the rect in front of the plane to stop the particle at y=29, got %v", y)
+	}
+}
+
+func TestApplyCollisionConfigKeepsRuntimeColliders(t *testing.T) {
+	data := &SystemData{}
+	config := &CollisionConfig{Colliders: []ColliderConfig{{Type: "plane", Y: 10, NormalY: -1}}}
+	applyCollisionConfig(data, config, 100, 200)
+	data.Colliders = append(data.Colliders, NewCircleCollider(0, 0, 5))
+
+	config.Colliders = append(config.Colliders, ColliderConfig{Type: "rect", Width: 4, Height: 4})
+	applyCollisionConfig(data, config, 100, 200)
+
+	if len(data.Colliders) != 3 {
+		t.Fatalf("expected 2 config colliders and 1 runtime collider, got %d", len(data.Colliders))
+	}
+	if c := data.Colliders[0]; c.Type != ColliderPlane || c.X != 100 || c.Y != 210 {
+		t.Fatalf("config plane not converted to world space: %+v", c)
+	}
+	if data.Colliders[2].Type != ColliderCircle {
+		t.Fatalf("runtime collider was not kept last: %+v", data.Colliders[2])
+	}
+}
+
+func TestGridColliderIsNotQueriedConcurrently(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+	var inFlight, maxInFlight atomic.Int32
+	solid := func(cellX, cellY int) bool {
+		n := inFlight.Add(1)
+		if n > maxInFlight.Load() {
+			maxInFlight.Store(n)
+		}
+		// Give any other simulation goroutine the chance to overlap.
+		runtime.Gosched()
+		inFlight.Add(-1)
+		return false
+	}
+	n := parallelSimulateThresholdPlain * 2
+	data := &SystemData{
+		ParticlePool: make([]Instance, n),
+		ActiveCount:  n,
+		Colliders:    []Collider{NewGridCollider(0, 0, 16, solid)},
+		CurrentTime:  defaultDeltaTime,
+	}
+	for i := range data.ParticlePool {
+		data.ParticlePool[i] = Instance{Active: true, Duration: 1, EndY: 100, CurrentPosValid: true}
+	}
+
+	simulateActiveParticles(data, defaultDeltaTime)
+	if got := maxInFlight.Load(); got != 1 {
+		t.Fatalf("expected Solid to run on one goroutine at a time, saw %d at once", got)
+	}
+}
@@ -0,0 +1,259 @@
+package chirashi
+
+import (
+	"math"
+	"sort"
+)
+
+// ColorSpace selects the space colors are interpolated in.
+type ColorSpace int
+
+const (
+	ColorSpaceRGB ColorSpace = iota
+	ColorSpaceHSV
+	ColorSpaceOKLab
+)
+
+// ColorStop is one gradient key. R/G/B are sRGB values in 0-1; A multiplies
+// the particle (or trail) alpha.
+type ColorStop struct {
+	T, R, G, B, A float32
+}
+
+// ColorGradient is a multi-stop color gradient over normalized time.
+type ColorGradient struct {
+	Stops []ColorStop // sorted by T
+	Space ColorSpace
+	// coords holds each stop converted to Space, so evaluation only converts
+	// the interpolated result back to RGB.
+	coords [][3]float32
+}
+
+// NewColorGradient returns a gradient through stops (sorted by T) that
+// interpolates in space.
+func NewColorGradient(space ColorSpace, stops []ColorStop) *ColorGradient {
+	g := &ColorGradient{
+		Stops: append([]ColorStop(nil), stops...),
+		Space: space,
+	}
+	sort.SliceStable(g.Stops, func(i, j int) bool { return g.Stops[i].T < g.Stops[j].T })
+	g.coords = make([][3]float32, len(g.Stops))
+	for i, s := range g.Stops {
+		g.coords[i] = toColorSpace(space, s.R, s.G, s.B)
+	}
+	return g
+}
+
+// Evaluate returns the gradient color and alpha at t.
+func (g *ColorGradient) Evaluate(t float32) (float32, float32, float32, float32) {
+	n := len(g.Stops)
+	if n == 0 {
+		return 1, 1, 1, 1
+	}
+	if t <= g.Stops[0].T {
+		s := g.Stops[0]
+		return s.R, s.G, s.B, s.A
+	}
+	if t >= g.Stops[n-1].T {
+		s := g.Stops[n-1]
+		return s.R, s.G, s.B, s.A
+	}
+	i := 1
+	for i < n-1 && t > g.Stops[i].T {
+		i++
+	}
+	s0, s1 := &g.Stops[i-1], &g.Stops[i]
+	u := float32(0)
+	if span := s1.T - s0.T; span > 0 {
+		u = (t - s0.T) / span
+	}
+	r, gr, b := fromColorSpace(g.Space, mixColorCoords(g.Space, g.coords[i-1], g.coords[i], u))
+	return r, gr, b, lerp(s0.A, s1.A, u)
+}
+
+// tinted returns a copy of g with every stop multiplied by (r, g, b).
+func (g *ColorGradient) tinted(r, gr, b float32) *ColorGradient {
+	stops := make([]ColorStop, len(g.Stops))
+	for i, s := range g.Stops {
+		stops[i] = ColorStop{T: s.T, R: s.R * r, G: s.G * gr, B: s.B * b, A: s.A}
+	}
+	return NewColorGradient(g.Space, stops)
+}
+
+func parseColorSpace(name string) ColorSpace {
+	switch name {
+	case "hsv":
+		return ColorSpaceHSV
+	case "oklab":
+		return ColorSpaceOKLab
+	default:
+		return ColorSpaceRGB
+	}
+}
+
+// buildColorGradient returns nil when config has no gradient stops.
+func buildColorGradient(config *ColorConfig) *ColorGradient {
+	if config == nil || len(config.Gradient) == 0 {
+		return nil
+	}
+	stops := make([]ColorStop, len(config.Gradient))
+	for i, s := range config.Gradient {
+		stops[i] = ColorStop{T: s.T, R: s.R, G: s.G, B: s.B, A: 1}
+		if s.A != nil {
+			stops[i].A = *s.A
+		}
+	}
+	return NewColorGradient(parseColorSpace(config.Space), stops)
+}
+
+func buildColorPalette(config *ColorConfig) [][3]float32 {
+	if config == nil || len(config.Palette) == 0 {
+		return nil
+	}
+	palette := make([][3]float32, len(config.Palette))
+	for i, c := range config.Palette {
+		palette[i] = [3]float32{c.R, c.G, c.B}
+	}
+	return palette
+}
+
+// mixColor interpolates between two sRGB colors in space.
+func mixColor(space ColorSpace, r0, g0, b0, r1, g1, b1, t float32) (float32, float32, float32) {
+	if space == ColorSpaceRGB {
+		return lerp(r0, r1, t), lerp(g0, g1, t), lerp(b0, b1, t)
+	}
+	return fromColorSpace(space, mixColorCoords(space, toColorSpace(space, r0, g0, b0), toColorSpace(space, r1, g1, b1), t))
+}
+
+func mixColorCoords(space ColorSpace, c0, c1 [3]float32, t float32) [3]float32 {
+	if space == ColorSpaceHSV {
+		h0, h1 := c0[0], c1[0]
+		// Grays have no meaningful hue; borrow the other end's.
+		if c0[1] == 0 {
+			h0 = h1
+		} else if c1[1] == 0 {
+			h1 = h0
+		}
+		// Take the shorter way around the hue circle.
+		dh := h1 - h0
+		if dh > 0.5 {
+			dh--
+		} else if dh < -0.5 {
+			dh++
+		}
+		h := h0 + dh*t
+		h -= float32(math.Floor(float64(h)))
+		return [3]float32{h, lerp(c0[1], c1[1], t), lerp(c0[2], c1[2], t)}
+	}
+	return [3]float32{lerp(c0[0], c1[0], t), lerp(c0[1], c1[1], t), lerp(c0[2], c1[2], t)}
+}
+
+func toColorSpace(space ColorSpace, r, g, b float32) [3]float32 {
+	switch space {
+	case ColorSpaceHSV:
+		h, s, v := rgbToHSV(r, g, b)
+		return [3]float32{h, s, v}
+	case ColorSpaceOKLab:
+		l, a, bb := srgbToOKLab(r, g, b)
+		return [3]float32{l, a, bb}
+	default:
+		return [3]float32{r, g, b}
+	}
+}
+
+func fromColorSpace(space ColorSpace, c [3]float32) (float32, float32, float32) {
+	switch space {
+	case ColorSpaceHSV:
+		return hsvToRGB(c[0], c[1], c[2])
+	case ColorSpaceOKLab:
+		r, g, b := okLabToSRGB(c[0], c[1], c[2])
+		return clamp01(r), clamp01(g), clamp01(b)
+	default:
+		return c[0], c[1], c[2]
+	}
+}
+
+// rgbToHSV returns hue in [0, 1), saturation and value.
+func rgbToHSV(r, g, b float32) (float32, float32, float32) {
+	maxC := max(r, g, b)
+	minC := min(r, g, b)
+	delta := maxC - minC
+	if maxC <= 0 || delta <= 0 {
+		return 0, 0, maxC
+	}
+	var h float32
+	switch maxC {
+	case r:
+		h = (g - b) / delta
+		if h < 0 {
+			h += 6
+		}
+	case g:
+		h = (b-r)/delta + 2
+	default:
+		h = (r-g)/delta + 4
+	}
+	return h / 6, delta / maxC, maxC
+}
+
+func hsvToRGB(h, s, v float32) (float32, float32, float32) {
+	if s <= 0 {
+		return v, v, v
+	}
+	h6 := h * 6
+	sector := int(h6) % 6
+	f := h6 - float32(math.Floor(float64(h6)))
+	p := v * (1 - s)
+	q := v * (1 - s*f)
+	t := v * (1 - s*(1-f))
+	switch sector {
+	case 0:
+		return v, t, p
+	case 1:
+		return q, v, p
+	case 2:
+		return p, v, t
+	case 3:
+		return p, q, v
+	case 4:
+		return t, p, v
+	default:
+		return v, p, q
+	}
+}
+
+func srgbToLinear(c float32) float64 {
+	if c <= 0.04045 {
+		return float64(c) / 12.92
+	}
+	return math.Pow((float64(c)+0.055)/1.055, 2.4)
+}
+
+func linearToSRGB(c float64) float32 {
+	if c <= 0.0031308 {
+		return float32(c * 12.92)
+	}
+	return float32(1.055*math.Pow(c, 1/2.4) - 0.055)
+}
+
+// srgbToOKLab converts with the matrices from Björn Ottosson's OKLab
+// reference implementation.
+func srgbToOKLab(r, g, b float32) (float32, float32, float32) {
+	lr, lg, lb := srgbToLinear(r), srgbToLinear(g), srgbToLinear(b)
+	l := math.Cbrt(0.4122214708*lr + 0.5363325363*lg + 0.0514459929*lb)
+	m := math.Cbrt(0.2119034982*lr + 0.6806995451*lg + 0.1073969566*lb)
+	s := math.Cbrt(0.0883024619*lr + 0.2817188376*lg + 0.6299787005*lb)
+	return float32(0.2104542553*l + 0.7936177850*m - 0.0040720468*s),
+		float32(1.9779984951*l - 2.4285922050*m + 0.4505937099*s),
+		float32(0.0259040371*l + 0.7827717662*m - 0.8086757660*s)
+}
+
+func okLabToSRGB(lab, a, b float32) (float32, float32, float32) {
+	l := float64(lab) + 0.3963377774*float64(a) + 0.2158037573*float64(b)
+	m := float64(lab) - 0.1055613458*float64(a) - 0.0638541728*float64(b)
+	s := float64(lab) - 0.0894841775*float64(a) - 1.2914855480*float64(b)
+	l, m, s = l*l*l, m*m*m, s*s*s
+	return linearToSRGB(4.0767416621*l - 3.3077115913*m + 0.2309699292*s),
+		linearToSRGB(-1.2684380046*l + 2.6097574011*m - 0.3413193965*s),
+		linearToSRGB(-0.0041960863*l - 0.7034186147*m + 1.7076147010*s)
+}
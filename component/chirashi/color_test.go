@@ -0,0 +1,105 @@
+package chirashi
+
+import (
+	"math"
+	"testing"
+)
+
+func nearColor(r, g, b, wr, wg, wb float32) bool {
+	const eps = 1e-3
+	return math.Abs(float64(r-wr)) < eps && math.Abs(float64(g-wg)) < eps && math.Abs(float64(b-wb)) < eps
+}
+
+func TestColorGradientEvaluatesStopsAndAlpha(t *testing.T) {
+	g := NewColorGradient(ColorSpaceRGB, []ColorStop{
+		{T: 1, R: 0, G: 0, B: 1, A: 0},
+		{T: 0, R: 1, G: 1, B: 1, A: 1},
+		{T: 0.5, R: 1, G: 0, B: 0, A: 1},
+	})
+
+	if r, gr, b, a := g.Evaluate(0.25); !nearColor(r, gr, b, 1, 0.5, 0.5) || a != 1 {
+		t.Fatalf("t=0.25 got (%v, %v, %v, %v)", r, gr, b, a)
+	}
+	if r, gr, b, a := g.Evaluate(0.75); !nearColor(r, gr, b, 0.5, 0, 0.5) || a != 0.5 {
+		t.Fatalf("t=0.75 got (%v, %v, %v, %v)", r, gr, b, a)
+	}
+	if r, gr, b, a := g.Evaluate(2); !nearColor(r, gr, b, 0, 0, 1) || a != 0 {
+		t.Fatalf("t past the last stop got (%v, %v, %v, %v)", r, gr, b, a)
+	}
+}
+
+func TestMixColorHSVTakesShortestHuePath(t *testing.T) {
+	r, g, b := mixColor(ColorSpaceHSV, 1, 0, 0, 0, 0, 1, 0.5)
+	if !nearColor(r, g, b, 1, 0, 1) {
+		t.Fatalf("red to blue midpoint got (%v, %v, %v), want magenta", r, g, b)
+	}
+}
+
+func TestParticleTintMatchesMixColorInHSV(t *testing.T) {
+	clr := ColorParams{Enabled: true, StartR: 1, EndB: 1, Space: ColorSpaceHSV}
+	var p Instance
+	applyParticleColor(&p, &clr)
+	r, g, b, _ := particleTint(&p, 0.5)
+	if wr, wg, wb := mixColor(ColorSpaceHSV, 1, 0, 0, 0, 0, 1, 0.5); !nearColor(r, g, b, wr, wg, wb) {
+		t.Fatalf("particle midpoint got (%v, %v, %v), want (%v, %v, %v)", r, g, b, wr, wg, wb)
+	}
+}
+
+func TestTrailColorMatchesMixColorInOKLab(t *testing.T) {
+	trail := buildTrailData(&TrailConfig{Enabled: true, Color: &ColorConfig{StartR: 1, EndB: 1, Space: "oklab"}})
+	r, g, b, _ := trailColor(&trail.Params, 0.5)
+	if wr, wg, wb := mixColor(ColorSpaceOKLab, 1, 0, 0, 0, 0, 1, 0.5); !nearColor(r, g, b, wr, wg, wb) {
+		t.Fatalf("trail midpoint got (%v, %v, %v), want (%v, %v, %v)", r, g, b, wr, wg, wb)
+	}
+}
+
+func TestOKLabRoundTrip(t *testing.T) {
+	for _, c := range [][3]float32{{1, 0.5, 0.25}, {0, 0, 0}, {1, 1, 1}, {0.2, 0.8, 0.4}} {
+		r, g, b := fromColorSpace(ColorSpaceOKLab, toColorSpace(ColorSpaceOKLab, c[0], c[1], c[2]))
+		if !nearColor(r, g, b, c[0], c[1], c[2]) {
+			t.Fatalf("round trip of %v got (%v, %v, %v)", c, r, g, b)
+		}
+	}
+}
+
+func TestPaletteAssignsOneConstantColorPerParticle(t *testing.T) {
+	clr := ColorParams{
+		Enabled: true,
+		Palette: [][3]float32{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}},
+	}
+	rng := newSystemRand(7)
+	seen := map[[3]float32]bool{}
+	for range 64 {
+		var p Instance
+		assignParticleColor(&p, &clr, rng)
+		r0, g0, b0, _ := particleTint(&p, 0)
+		r1, g1, b1, _ := particleTint(&p, 1)
+		if r0 != r1 || g0 != g1 || b0 != b1 {
+			t.Fatalf("palette color changed over lifetime: (%v,%v,%v) -> (%v,%v,%v)", r0, g0, b0, r1, g1, b1)
+		}
+		seen[[3]float32{r0, g0, b0}] = true
+	}
+	if len(seen) != 3 {
+		t.Fatalf("expected all 3 palette colors to be picked, got %v", seen)
+	}
+}
+
+func TestBuildColorGradientDefaultsAlpha(t *testing.T) {
+	half := float32(0.5)
+	g := buildColorGradient(&ColorConfig{
+		Space: "oklab",
+		Gradient: []ColorStopConfig{
+			{T: 0, R: 1, G: 1, B: 1},
+			{T: 1, R: 1, G: 1, B: 1, A: &half},
+		},
+	})
+	if g == nil || g.Space != ColorSpaceOKLab {
+		t.Fatalf("expected an OKLab gradient, got %+v", g)
+	}
+	if _, _, _, a := g.Evaluate(0); a != 1 {
+		t.Fatalf("omitted stop alpha got %v, want 1", a)
+	}
+	if _, _, _, a := g.Evaluate(1); a != 0.5 {
+		t.Fatalf("stop alpha got %v, want 0.5", a)
+	}
+}
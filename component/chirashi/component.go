@@ -1,8 +1,11 @@
 package chirashi
 
 import (
+	"math/rand/v2"
+
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/yohamta/donburi"
+	"github.com/yohamta/donburi/ecs"
 )
 
 // Instance represents a single GPU-based particle
@@ -10,6 +13,7 @@ type Instance struct {
 	// Timing
 	SpawnTime float32 // Time when particle was spawned
 	Duration  float32 // Total animation duration in seconds
+	SpawnSeq  uint64  // Spawn order within the entity (draw sorting)
 
 	// Position animation
 	StartX, EndX float32
@@ -38,21 +42,61 @@ type Instance struct {
 	CurrentY         float32
 	CurrentPosValid  bool
 	CurrentPosTime   float32
+	// Position cached on the previous update, used to estimate velocity.
+	PrevX, PrevY float32
+	PrevPosTime  float32
+
+	// Integrated motion (physics mode, or any particle after a collision):
+	// the base position is PosX/PosY, advanced by VelX/VelY (units/sec)
+	// every update instead of the closed-form path. Stuck particles stay in
+	// place.
+	Integrated bool
+	Stuck      bool
+	Collided   bool // new contact this update, consumed by the expiry sweep
+	InContact  bool // touched a collider on the previous update
+	PosX, PosY float32
+	VelX, VelY float32
+	// Inherited velocity of particles on a closed-form path, added as a
+	// drift that decays over inheritedVelocityDamping.
+	HasInheritedVel              bool
+	InheritedVelX, InheritedVelY float32
 
 	// Appearance animation
 	StartAlpha, EndAlpha       float32
 	StartScale, EndScale       float32
+	StartScaleX, EndScaleX     float32
+	StartScaleY, EndScaleY     float32
 	StartRotation, EndRotation float32
 
 	// Color animation (RGB 0-1)
 	StartR, StartG, StartB float32
 	EndR, EndG, EndB       float32
-	ColorVariationMix      float32 // spawn-time mix toward the variation gradient
+	ColorVariationMix      float32 // spawn-time mix toward the variation gradient, or palette pick
+	ColorSpace             ColorSpace
+	StartColor, EndColor   [3]float32     // start/end pair converted to ColorSpace at spawn (HSV, OKLab)
+	ColorGradient          *ColorGradient // nil = start/end pair
+	// Tint multiplies the animated color and alpha when HasTint is set
+	// (the source pixel color of sprite emitters).
+	HasTint                    bool
+	TintR, TintG, TintB, TintA float32
+	// Emitter transform captured at spawn when HasTransform is set: added
+	// to the particle rotation and multiplied into its size and trail width.
+	HasTransform                      bool
+	TransformRotation, TransformScale float32
+
+	// Flipbook frame offset rolled at spawn (random_start)
+	StartFrame int
+
+	// Last heading with measurable motion, kept while the particle is at
+	// rest (velocity alignment).
+	Heading float32
 
 	// Easing types for each property
 	PositionEasing EasingType
 	AlphaEasing    EasingType
 	ScaleEasing    EasingType
+	ScaleXEasing   EasingType
+	ScaleYEasing   EasingType
 	RotationEasing EasingType
 	ColorEasing    EasingType
 
@@ -64,14 +108,23 @@ type Instance struct {
 	PosYSnap    SequenceSnapshot
 	HasScaleSeq bool
 	ScaleSnap   SequenceSnapshot
-	HasRotSeq   bool
-	RotSnap     SequenceSnapshot
-	HasAlphaSeq bool
-	AlphaSnap   SequenceSnapshot
+	// Per-axis scale sequences (HasScaleX/HasScaleY in AppearanceParams).
+	HasScaleXSeq bool
+	ScaleXSnap   SequenceSnapshot
+	HasScaleYSeq bool
+	ScaleYSnap   SequenceSnapshot
+	HasRotSeq    bool
+	RotSnap      SequenceSnapshot
+	HasAlphaSeq  bool
+	AlphaSnap    SequenceSnapshot
 
 	// Particle trail history (used only when trail.mode == "particle")
 	TrailPoints []TrailPoint
 
+	// RandState drives per-particle draws made during simulation (seeded
+	// from the entity stream at spawn).
+	RandState uint64
+
 	// State
 	Active bool
 }
@@ -94,35 +147,99 @@ type SystemData struct {
 	// Timing
 	CurrentTime float32
 
+	// Seed is the seed of this entity's random stream. Every random draw
+	// for the entity comes from that stream, so the same seed and inputs
+	// reproduce the same particle state.
+	Seed uint64
+	rng  *rand.Rand
+
 	// Emitter configuration
 	EmitterX, EmitterY float32
 	EmitterShape       EmitterShapeParams
 	EmitterVector      EmitterVectorParams
 	EmitterLocalSpace  bool
-
-	// Spawn configuration
+	// EmitterMotion moves the emitter around its origin. EmitterX/Y include
+	// the current motion offset, which is kept in motionOffsetX/Y.
+	EmitterMotion                EmitterMotionParams
+	motionOffsetX, motionOffsetY float32
+	motionLap                    int
+	// InheritedVelX/Y (units/sec) is added to the initial velocity of every
+	// particle spawned. Sub-emitters that inherit velocity set it from the
+	// parent particle.
+	InheritedVelX, InheritedVelY float32
+	// Sprite replaces the shape and vector placement with one particle per
+	// pixel block of a sprite.
+	Sprite SpriteEmitterParams
+
+	// Spawn configuration. SpawnInterval is measured in 60 TPS reference
+	// frames; SpawnRate (particles per second) replaces it when > 0.
 	SpawnInterval     int
 	ParticlesPerSpawn int
+	SpawnRate         float32
 	MaxParticles      int
+	// SpawnRateOverDistance emits this many particles per unit the emitter
+	// moves, spaced along its path (0 = off).
+	SpawnRateOverDistance float32
+	// Bursts are scheduled one-off emissions on the entity clock
+	// (CurrentTime); burstCycles counts the cycles each has fired.
+	Bursts      []BurstParams
+	burstCycles []int
 	// EmissionScale scales the configured emission rate and active-particle cap
 	// without overwriting the YAML-derived spawn values. Values are clamped to
 	// [0, 1]; 0 pauses emission and 1 uses the configured values unchanged.
 	// Factory-created systems initialize this field to 1.
 	EmissionScale float32
+	// TimeScale multiplies this entity's time step (spawning, particle
+	// ageing, flow integration and trail ageing). 0 freezes the effect, 1 is
+	// real time, and values above 1 fast-forward. Negative values are
+	// treated as 0. Factory-created systems initialize this field to 1.
+	TimeScale float32
+	// EmitterRotation (radians) and EmitterScale transform particles as they
+	// spawn around the emitter origin: shape, vector and sprite offsets,
+	// position ranges, directions and launch velocities, particle size and
+	// rotation, and trail widths. Position x/y tweens, flow and gravity stay
+	// in world axes, and live particles keep the transform they spawned
+	// with. EmitterScale <= 0 is treated as 1.
+	EmitterRotation float32
+	EmitterScale    float32
+	// rotationOverridden and scaleOverridden keep values set at runtime
+	// across ApplyConfigLive.
+	rotationOverridden, scaleOverridden bool
+	// attachmentRotation is the Attachment rotation, added on top of
+	// EmitterRotation.
+	attachmentRotation float32
 
 	// Rendering
 	SourceImage    *ebiten.Image
+	ImageX, ImageY float32      // Cached image bounds origin (non-zero for atlas sub-images)
 	ImageWidth     float32      // Cached image width
 	ImageHeight    float32      // Cached image height
 	Blend          ebiten.Blend // Zero value = source-over (alpha blending)
 	ShaderUniforms map[string]interface{}
 	Trail          TrailData
+	// Sort orders particles within this entity's draw (render.sort).
+	Sort      DrawSort
+	drawOrder []drawOrderEntry
+	// Layer is the render layer drawn by System.DrawLayer (render.layer);
+	// drawSeq orders entities within a layer by creation. layerOverridden
+	// keeps a runtime layer across ApplyConfigLive.
+	Layer           ecs.LayerID
+	drawSeq         uint64
+	layerOverridden bool
 
 	// Internal state
 	ActiveCount       int
 	emissionRemainder float32
-	IsLoop            bool
-	LifeTime          int // Remaining lifetime in frames (if not looping)
+	spawnSeq          uint64
+	// Emitter position at the previous spawn step and the distance travelled
+	// since the last distance-based particle (rate_over_distance).
+	lastEmitterX, lastEmitterY float32
+	emitterPathValid           bool
+	distanceCarry              float32
+	spawnClock                 float32 // reference frames accumulated toward the next interval tick
+	lifeTimeClock              float32 // fractional reference frames not yet taken from LifeTime
+	IsLoop                     bool
+	LifeTime                   int // Remaining lifetime in 60 TPS reference frames (if not looping)
 
 	// Animation parameters (from config, used for spawning)
 	AnimParams AnimationParams
@@ -132,11 +249,29 @@ type SystemData struct {
 	AttractorX, AttractorY float32
 
 	// Multi-step sequence configurations (nil = simple mode)
-	PosXSeq  *SequenceConfig
-	PosYSeq  *SequenceConfig
-	ScaleSeq *SequenceConfig
-	RotSeq   *SequenceConfig
-	AlphaSeq *SequenceConfig
+	PosXSeq   *SequenceConfig
+	PosYSeq   *SequenceConfig
+	ScaleSeq  *SequenceConfig
+	ScaleXSeq *SequenceConfig
+	ScaleYSeq *SequenceConfig
+	RotSeq    *SequenceConfig
+	AlphaSeq  *SequenceConfig
+
+	// Sub-emitters spawn child presets through the ParticleManager that
+	// created this entity (nil host = sub-emitters are ignored).
+	SubEmitters     []SubEmitterParams
+	subEmitterHost  *ParticleManager
+	subEmitterDepth int
+
+	// Colliders are world-space solids tested against every active particle
+	// (see AddCollider). The first configColliders entries come from YAML.
+	Colliders       []Collider
+	Collision       CollisionParams
+	configColliders int
+
+	// forceFields is the System's snapshot of ForceField entities for the
+	// current update.
+	forceFields []ForceFieldData
 
 	// Performance metrics
 	Metrics Metrics
@@ -173,6 +308,9 @@ type EmitterVectorParams struct {
 	Placement EmitterVectorPlacement
 	Rect      EmitterVectorRectParams
 	Polyline  EmitterVectorPolylineParams
+	// Polylines are sampled together with Polyline, weighted by length for
+	// surface placement and by area for fill.
+	Polylines []EmitterVectorPolylineParams
 }
 
 type EmitterVectorType int
@@ -203,6 +341,11 @@ type EmitterVectorPolylineParams struct {
 	Points         []EmitterVectorPointParams
 	SegmentLengths []float32
 	TotalLength    float32
+	// Triangles cover the interior of a closed polyline with fill
+	// placement; TriangleAreas holds their cumulative areas.
+	Triangles     []EmitterVectorTriangleParams
+	TriangleAreas []float32
+	TotalArea     float32
 }
 
 type EmitterVectorPointParams struct {
@@ -210,12 +353,33 @@ type EmitterVectorPointParams struct {
 	Y float32
 }
 
+// SubEmitterParams stores one normalized sub-emitter entry.
+type SubEmitterParams struct {
+	Preset          string
+	Trigger         SubEmitterTrigger
+	Probability     float32
+	InheritPosition bool
+	InheritColor    bool
+	InheritVelocity bool
+	LifeTime        int
+}
+
+// SubEmitterTrigger identifies the particle event that fires a sub-emitter.
+type SubEmitterTrigger int
+
+const (
+	SubEmitterOnBirth SubEmitterTrigger = iota
+	SubEmitterOnDeath
+	SubEmitterOnCollision
+)
+
 // AnimationParams holds the configuration for particle animations, grouped by concern.
 type AnimationParams struct {
 	Duration   DurationParams
 	Position   PositionParams
 	Appearance AppearanceParams
 	Color      ColorParams
+	Frames     FrameParams
 }
 
 // DurationParams holds lifetime randomization for particles.
@@ -228,6 +392,7 @@ type DurationParams struct {
 type PositionParams struct {
 	UsePolar     bool // true = polar
 	UseAttractor bool // true = quadratic bezier toward AttractorX/Y
+	UsePhysics   bool // true = integrated velocity (launch cone uses the polar ranges)
 
 	// Cartesian
 	StartXMin, StartXMax float32
@@ -246,6 +411,16 @@ type PositionParams struct {
 	ControlXMin, ControlXMax float32
 	ControlYMin, ControlYMax float32
 
+	// Physics: launch velocity added to the cone, plus forces applied every
+	// update. Other modes leave the forces at zero, so their particles keep
+	// a constant velocity after bouncing off a collider.
+	VelXMin, VelXMax float32
+	VelYMin, VelYMax float32
+	AccelX, AccelY   float32
+	LinearDrag       float32
+	QuadraticDrag    float32
+	TerminalSpeed    float32
+
 	HasFlow             bool
 	FlowStrengthMin     float32
 	FlowStrengthMax     float32
@@ -258,6 +433,10 @@ type PositionParams struct {
 	FlowBoundRadius     float32
 	FlowRespawnOnEscape bool
 
+	// Cartesian curves (nil = unused): offsets from the particle's start
+	// position over normalized lifetime, overriding the start/end lerp.
+	XCurve, YCurve *Curve
+
 	Easing EasingType
 }
 
@@ -269,6 +448,24 @@ type AppearanceParams struct {
 	ScaleEasing                EasingType
 	StartRotation, EndRotation float32
 	RotationEasing             EasingType
+
+	// Keyframe curves over normalized lifetime (nil = unused). A curve
+	// overrides the start/end lerp; a sequence overrides both.
+	AlphaCurve, ScaleCurve, RotationCurve *Curve
+
+	// Per-axis scale overriding the uniform scale on that axis, when
+	// HasScaleX/HasScaleY are set.
+	HasScaleX, HasScaleY     bool
+	StartScaleX, EndScaleX   float32
+	ScaleXEasing             EasingType
+	StartScaleY, EndScaleY   float32
+	ScaleYEasing             EasingType
+	ScaleXCurve, ScaleYCurve *Curve
+
+	// AlignVelocity adds the particle's heading to its rotation; Stretch
+	// scales the quad length by 1 + Stretch*speed.
+	AlignVelocity bool
+	Stretch       float32
 }
 
 // ColorParams holds color animation configuration.
@@ -283,6 +480,41 @@ type ColorParams struct {
 	HasVariation              bool
 	Start2R, Start2G, Start2B float32
 	End2R, End2G, End2B       float32
+
+	// Space is the interpolation space. Gradient (when non-nil) replaces the
+	// start/end pair; Palette (when non-empty) gives each particle one
+	// constant color picked at spawn.
+	Space    ColorSpace
+	Gradient *ColorGradient
+	Palette  [][3]float32
+}
+
+// rollsPerParticle reports whether particles draw a random color mix at
+// spawn (variation or palette).
+func (c *ColorParams) rollsPerParticle() bool {
+	return c.HasVariation || len(c.Palette) > 0
+}
+
+// FrameLoop selects what a flipbook does after its last frame.
+type FrameLoop int
+
+const (
+	FrameLoopRepeat FrameLoop = iota
+	FrameLoopOnce
+	FrameLoopPingPong
+)
+
+// FrameParams holds normalized flipbook configuration.
+type FrameParams struct {
+	Enabled      bool
+	Columns      int
+	Rows         int
+	Count        int
+	OverLifetime bool
+	FPS          float32
+	Cycles       float32
+	Loop         FrameLoop
+	RandomStart  bool
 }
 
 // Metrics tracks performance data for a particle system
@@ -302,7 +534,8 @@ type TrailPoint struct {
 
 // TrailGhost stores a detached particle trail after the source particle expires.
 type TrailGhost struct {
-	Points []TrailPoint
+	Points     []TrailPoint
+	WidthScale float32 // emitter scale of the source particle
 }
 
 // TrailParams stores normalized trail configuration values.
@@ -327,6 +560,10 @@ type TrailParams struct {
 	ColorEndG          float32
 	ColorEndB          float32
 	ColorEasing        EasingType
+	ColorSpace         ColorSpace
+	ColorStart         [3]float32 // start/end pair converted to ColorSpace once (HSV, OKLab)
+	ColorEnd           [3]float32
+	ColorGradient      *ColorGradient // nil = start/end pair
 }
 
 // TrailRuntime stores mutable trail history and draw buffers.
@@ -11,6 +11,22 @@ type ParticleConfig struct {
 	Animation   AnimationConfig `yaml:"animation"`
 	Trail       *TrailConfig    `yaml:"trail,omitempty"`
 	Spawn       SpawnConfig     `yaml:"spawn"`
+	Seed        *uint64         `yaml:"seed,omitempty"` // fixed random seed; omitted = fresh seed per entity
+
+	SubEmitters []SubEmitterConfig `yaml:"sub_emitters,omitempty"`
+	Collision   *CollisionConfig   `yaml:"collision,omitempty"`
+}
+
+// SubEmitterConfig spawns another preset when a particle of this effect is
+// born, dies or collides. Preset names resolve against the ParticleManager
+// that spawned the effect; entities created without a manager ignore
+// sub-emitters.
+type SubEmitterConfig struct {
+	Preset      string   `yaml:"preset"`
+	Trigger     string   `yaml:"trigger"`               // birth, death, or collision
+	Probability *float32 `yaml:"probability,omitempty"` // chance per trigger in [0,1]; omitted = 1
+	Inherit     []string `yaml:"inherit,omitempty"`     // position, color, velocity; omitted = position
+	LifeTime    int      `yaml:"life_time,omitempty"`   // child lifetime in reference frames; omitted = preset's spawn.life_time
 }
 
 // RenderConfig defines optional rendering and scene-level post effects.
@@ -22,6 +38,11 @@ type RenderConfig struct {
 	GlitchIntensity float32           `yaml:"glitch_intensity,omitempty"`
 	Bloom           *BloomConfig      `yaml:"bloom,omitempty"`
 	Afterimage      *AfterimageConfig `yaml:"afterimage,omitempty"`
+	// Sort is the particle draw order: none (default), oldest_first,
+	// youngest_first, by_y or by_scale.
+	Sort string `yaml:"sort,omitempty"`
+	// Layer is the render layer drawn by System.DrawLayer; 0 by default.
+	Layer int `yaml:"layer,omitempty"`
 }
 
 // BloomConfig defines the multi-pass bloom parameters stored in YAML.
@@ -36,6 +57,30 @@ type AfterimageConfig struct {
 	Decay float32 `yaml:"decay"`
 }
 
+// CollisionConfig defines the colliders particles are tested against and how
+// they respond on contact. Collider positions are offsets from the emitter
+// origin when the effect is created; the colliders then stay fixed in world
+// space.
+type CollisionConfig struct {
+	Response    string           `yaml:"response,omitempty"`    // bounce (default), stick, or kill
+	Restitution float32          `yaml:"restitution,omitempty"` // bounce: share of normal speed kept
+	Friction    float32          `yaml:"friction,omitempty"`    // bounce: share of tangential speed lost, 0-1
+	Colliders   []ColliderConfig `yaml:"colliders"`
+}
+
+// ColliderConfig defines one collider primitive. X/Y is the center of a rect
+// or circle, or any point on a plane.
+type ColliderConfig struct {
+	Type    string  `yaml:"type"` // plane, rect, or circle
+	X       float32 `yaml:"x"`
+	Y       float32 `yaml:"y"`
+	NormalX float32 `yaml:"normal_x,omitempty"` // plane: points to the open side
+	NormalY float32 `yaml:"normal_y,omitempty"`
+	Width   float32 `yaml:"width,omitempty"`  // rect
+	Height  float32 `yaml:"height,omitempty"` // rect
+	Radius  float32 `yaml:"radius,omitempty"` // circle
+}
+
 // ImageConfig defines image source parameters
 type ImageConfig struct {
 	ImageFrom string `yaml:"image_from"`
@@ -57,6 +102,43 @@ type EmitterConfig struct {
 	Space  EmitterSpaceMode     `yaml:"space,omitempty"` // local (default) or world
 	Shape  EmitterShapeConfig   `yaml:"shape,omitempty"`
 	Vector *EmitterVectorConfig `yaml:"vector,omitempty"`
+	Motion *EmitterMotionConfig `yaml:"motion,omitempty"`
+	Sprite *EmitterSpriteConfig `yaml:"sprite,omitempty"`
+}
+
+// EmitterSpriteConfig emits one particle per non-transparent pixel block of a
+// sprite, tinted with the block's color. The sprite is looked up in the
+// TextureRegistry like ImageConfig, or set at runtime with SetEmitterSprite.
+type EmitterSpriteConfig struct {
+	ImageFrom      string  `yaml:"image_from,omitempty"`
+	ImageID        int     `yaml:"image_id,omitempty"`
+	BlockSize      int     `yaml:"block_size,omitempty"`      // block side in pixels, default 1
+	AlphaThreshold float32 `yaml:"alpha_threshold,omitempty"` // blocks at or below this mean alpha are skipped
+	Scale          float32 `yaml:"scale,omitempty"`           // world units per pixel, default 1
+	Mode           string  `yaml:"mode,omitempty"`            // disintegrate (default) or assemble
+}
+
+// EmitterMotionConfig moves the emitter around its origin over time. Local-space
+// particles stay relative to the origin, so an orbiting emitter leaves a ring.
+type EmitterMotionConfig struct {
+	Type string `yaml:"type"` // orbit, sway, path
+
+	// Orbit
+	Radius       float32 `yaml:"radius,omitempty"`
+	AngularSpeed float32 `yaml:"angular_speed,omitempty"` // Radians per second; negative is clockwise on screen
+
+	// Sway
+	AmplitudeX float32 `yaml:"amplitude_x,omitempty"`
+	AmplitudeY float32 `yaml:"amplitude_y,omitempty"`
+	Frequency  float32 `yaml:"frequency,omitempty"` // Cycles per second
+
+	// Orbit and sway
+	Phase float32 `yaml:"phase,omitempty"` // Radians
+
+	// Path
+	Path  *EmitterVectorPolylineConfig `yaml:"path,omitempty"`
+	Speed float32                      `yaml:"speed,omitempty"` // Units per second along the path
+	Mode  string                       `yaml:"mode,omitempty"`  // loop (default) or ping_pong
 }
 
 // EmitterShapeConfig defines where particles are spawned relative to the emitter origin.
@@ -74,10 +156,14 @@ type EmitterShapeConfig struct {
 
 // EmitterVectorConfig defines a vector-based placement source for one-shot style bursts.
 type EmitterVectorConfig struct {
-	Type      string                       `yaml:"type,omitempty"`      // rect, polyline
+	Type      string                       `yaml:"type,omitempty"`      // rect, polyline, text
 	Placement string                       `yaml:"placement,omitempty"` // fill or surface
 	Rect      *EmitterVectorRectConfig     `yaml:"rect,omitempty"`
 	Polyline  *EmitterVectorPolylineConfig `yaml:"polyline,omitempty"`
+	// Polylines are extra outlines sampled together with Polyline as one
+	// shape, e.g. the separate subpaths of an imported SVG.
+	Polylines []EmitterVectorPolylineConfig `yaml:"polylines,omitempty"`
+	Text      *EmitterVectorTextConfig      `yaml:"text,omitempty"`
 }
 
 // EmitterVectorRectConfig defines a rectangle placement source centered on the emitter.
@@ -93,6 +179,18 @@ type EmitterVectorPolylineConfig struct {
 	Interpolation string               `yaml:"interpolation,omitempty"` // linear (default) or quadratic
 	CurveSteps    int                  `yaml:"curve_steps,omitempty"`   // Samples per quadratic segment
 	Points        []EmitterVectorPoint `yaml:"points"`
+	// Holes are closed outlines cut out of the interior of a closed polyline.
+	// Surface placement samples their outlines too.
+	Holes [][]EmitterVectorPoint `yaml:"holes,omitempty"`
+}
+
+// EmitterVectorTextConfig lays out a string as glyph outlines centered on the
+// emitter. Font names a source registered in the manager's FontRegistry.
+type EmitterVectorTextConfig struct {
+	Value string  `yaml:"value"`
+	Font  string  `yaml:"font,omitempty"`
+	Size  float32 `yaml:"size"`
+	Align string  `yaml:"align,omitempty"` // left, center (default) or right
 }
 
 // EmitterVectorPoint defines a 2D point for vector placement.
@@ -109,6 +207,36 @@ type AnimationConfig struct {
 	Scale    PropertyConfig `yaml:"scale"`
 	Rotation PropertyConfig `yaml:"rotation"`
 	Color    *ColorConfig   `yaml:"color,omitempty"`
+	Frames   *FramesConfig  `yaml:"frames,omitempty"`
+
+	// ScaleX/ScaleY animate one axis independently; an omitted axis follows
+	// Scale.
+	ScaleX *PropertyConfig `yaml:"scale_x,omitempty"`
+	ScaleY *PropertyConfig `yaml:"scale_y,omitempty"`
+
+	// Align "velocity" points each quad's +X axis along the particle's
+	// motion; Rotation is then an offset from that heading. Stretch
+	// lengthens aligned quads by Stretch * speed (units/sec) along the
+	// motion.
+	Align   string  `yaml:"align,omitempty"`
+	Stretch float32 `yaml:"stretch,omitempty"`
+}
+
+// FramesConfig plays a sprite sheet (flipbook) on every particle. The
+// particle image is split into Columns x Rows equal cells, numbered left to
+// right, top to bottom.
+type FramesConfig struct {
+	Columns int `yaml:"columns"`
+	Rows    int `yaml:"rows"`
+	Count   int `yaml:"count,omitempty"` // frames used from the sheet (0 = columns * rows)
+	// Mode is "fps" (default, FPS frames per second) or "lifetime" (the
+	// sequence plays Cycles times over each particle's lifetime).
+	Mode   string  `yaml:"mode,omitempty"`
+	FPS    float32 `yaml:"fps,omitempty"`
+	Cycles float32 `yaml:"cycles,omitempty"` // lifetime mode, default 1
+	// Loop is "loop" (default), "once" (hold the last frame) or "ping_pong".
+	Loop        string `yaml:"loop,omitempty"`
+	RandomStart bool   `yaml:"random_start,omitempty"`
 }
 
 // DurationConfig defines particle lifetime with optional randomization
@@ -130,6 +258,8 @@ type PositionConfig struct {
 	//   "polar"               - radial burst from emitter
 	//   "attractor"           - quadratic bezier from emitter through a random
 	//                           control point toward AttractorX/Y on SystemData
+	//   "physics"             - velocity integrated every update with
+	//                           acceleration, drag and a terminal speed
 	Type string `yaml:"type,omitempty"`
 
 	// Cartesian mode (simple)
@@ -138,9 +268,10 @@ type PositionConfig struct {
 	StartY *RangeFloat `yaml:"start_y,omitempty"`
 	EndY   *RangeFloat `yaml:"end_y,omitempty"`
 
-	// Cartesian mode (sequence) - multi-step position tweens
-	X *PropertyConfig `yaml:"x,omitempty"` // X axis sequence
-	Y *PropertyConfig `yaml:"y,omitempty"` // Y axis sequence
+	// Cartesian mode (sequence or curve) - per-axis position tweens. Curve
+	// values are offsets from the particle's start position.
+	X *PropertyConfig `yaml:"x,omitempty"` // X axis sequence or curve
+	Y *PropertyConfig `yaml:"y,omitempty"` // Y axis sequence or curve
 
 	// Polar mode
 	Angle        *RangeFloat `yaml:"angle,omitempty"`         // Radians (0 to 2π for full circle)
@@ -152,6 +283,16 @@ type PositionConfig struct {
 	ControlX *RangeFloat `yaml:"control_x,omitempty"` // X offset range for bezier control point
 	ControlY *RangeFloat `yaml:"control_y,omitempty"` // Y offset range for bezier control point
 
+	// Physics mode - Angle/Speed define a launch cone and Distance a spawn
+	// offset along it; VelocityX/Y ranges are added to the cone velocity.
+	VelocityX     *RangeFloat `yaml:"velocity_x,omitempty"`     // units/sec
+	VelocityY     *RangeFloat `yaml:"velocity_y,omitempty"`     // units/sec
+	AccelerationX float32     `yaml:"acceleration_x,omitempty"` // units/sec^2, e.g. wind
+	AccelerationY float32     `yaml:"acceleration_y,omitempty"` // units/sec^2, e.g. gravity
+	LinearDrag    float32     `yaml:"linear_drag,omitempty"`    // 1/sec
+	QuadraticDrag float32     `yaml:"quadratic_drag,omitempty"` // 1/unit
+	TerminalSpeed float32     `yaml:"terminal_speed,omitempty"` // units/sec; 0 = unlimited
+
 	// Flow mode - continuous field offset layered on top of the base path
 	Flow *FlowConfig `yaml:"flow,omitempty"`
 
@@ -173,9 +314,10 @@ type FlowConfig struct {
 }
 
 // PropertyConfig defines an animation with easing.
-// Supports two modes:
+// Supports three modes:
 //   - Simple mode: Start/End/Easing (existing, backward compatible)
 //   - Sequence mode: Type="sequence" with Steps (multi-step tween chains)
+//   - Curve mode: Type="curve" with Keys over normalized lifetime
 type PropertyConfig struct {
 	// Simple mode (default)
 	Start  float32 `yaml:"start"`
@@ -183,8 +325,21 @@ type PropertyConfig struct {
 	Easing string  `yaml:"easing"`
 
 	// Multi-step mode
-	Type  string       `yaml:"type,omitempty"`  // "sequence" enables multi-step
+	Type  string       `yaml:"type,omitempty"`  // "sequence" enables multi-step, "curve" keyframes
 	Steps []StepConfig `yaml:"steps,omitempty"` // Steps for sequence mode
+
+	// Curve mode
+	Keys []CurveKeyConfig `yaml:"keys,omitempty"`
+}
+
+// CurveKeyConfig defines one keyframe of a curve. T is normalized lifetime
+// (0-1). In/Out are optional bezier tangents as slopes (value change per unit
+// of normalized time); omitted tangents make the adjacent segment linear.
+type CurveKeyConfig struct {
+	T     float32  `yaml:"t"`
+	Value float32  `yaml:"value"`
+	In    *float32 `yaml:"in,omitempty"`
+	Out   *float32 `yaml:"out,omitempty"`
 }
 
 // StepConfig defines one step in a multi-step animation sequence
@@ -202,20 +357,49 @@ func (c *PropertyConfig) IsSequence() bool {
 	return c.Type == "sequence" && len(c.Steps) > 0
 }
 
+// IsCurve returns true if this config uses keyframe curve mode
+func (c *PropertyConfig) IsCurve() bool {
+	return c.Type == "curve" && len(c.Keys) > 0
+}
+
 // ColorConfig defines color animation (RGB values 0-1).
 // When Variation is set, each particle picks a random mix between this color
 // pair and the variation color pair at spawn time (Unity-style "random
 // between two gradients"). Variation's Easing and nested Variation are
 // ignored.
+//
+// Gradient replaces the start/end pair with any number of stops, and Palette
+// gives each particle one constant color picked at spawn. Space selects the
+// interpolation space for start/end and gradient colors.
 type ColorConfig struct {
-	StartR    float32      `yaml:"start_r"`
-	StartG    float32      `yaml:"start_g"`
-	StartB    float32      `yaml:"start_b"`
-	EndR      float32      `yaml:"end_r"`
-	EndG      float32      `yaml:"end_g"`
-	EndB      float32      `yaml:"end_b"`
-	Easing    string       `yaml:"easing"`
-	Variation *ColorConfig `yaml:"variation,omitempty"`
+	StartR    float32              `yaml:"start_r"`
+	StartG    float32              `yaml:"start_g"`
+	StartB    float32              `yaml:"start_b"`
+	EndR      float32              `yaml:"end_r"`
+	EndG      float32              `yaml:"end_g"`
+	EndB      float32              `yaml:"end_b"`
+	Easing    string               `yaml:"easing"`
+	Variation *ColorConfig         `yaml:"variation,omitempty"`
+	Space     string               `yaml:"space,omitempty"` // rgb (default), hsv or oklab
+	Gradient  []ColorStopConfig    `yaml:"gradient,omitempty"`
+	Palette   []PaletteColorConfig `yaml:"palette,omitempty"`
+}
+
+// ColorStopConfig is one gradient stop at normalized time T. A is optional
+// (default 1) and multiplies the particle alpha.
+type ColorStopConfig struct {
+	T float32  `yaml:"t"`
+	R float32  `yaml:"r"`
+	G float32  `yaml:"g"`
+	B float32  `yaml:"b"`
+	A *float32 `yaml:"a,omitempty"`
+}
+
+// PaletteColorConfig is one palette entry.
+type PaletteColorConfig struct {
+	R float32 `yaml:"r"`
+	G float32 `yaml:"g"`
+	B float32 `yaml:"b"`
 }
 
 // TrailConfig defines an optional ribbon trail emitted from the emitter position.
@@ -238,11 +422,38 @@ type TrailScalarConfig struct {
 	Easing string  `yaml:"easing"`
 }
 
-// SpawnConfig defines particle spawning parameters
+// SpawnConfig defines particle spawning parameters.
+// Interval and LifeTime are measured in 60 TPS reference frames and are
+// advanced by delta time, so presets behave the same at any TPS. When Rate is
+// set, it replaces Interval/ParticlesPerSpawn with continuous emission.
+// Bursts are emitted on top of the steady emission. Rotation and Scale set
+// the initial emitter transform (see SystemData.EmitterRotation).
 type SpawnConfig struct {
-	Interval          int  `yaml:"interval"`
-	ParticlesPerSpawn int  `yaml:"particles_per_spawn"`
-	MaxParticles      int  `yaml:"max_particles"`
-	IsLoop            bool `yaml:"is_loop"`
-	LifeTime          int  `yaml:"life_time,omitempty"`
+	Interval          int           `yaml:"interval"`
+	ParticlesPerSpawn int           `yaml:"particles_per_spawn"`
+	Rate              float32       `yaml:"rate,omitempty"`               // particles per second
+	RateOverDistance  float32       `yaml:"rate_over_distance,omitempty"` // particles per unit of emitter movement
+	MaxParticles      int           `yaml:"max_particles"`
+	IsLoop            bool          `yaml:"is_loop"`
+	LifeTime          int           `yaml:"life_time,omitempty"`
+	Bursts            []BurstConfig `yaml:"bursts,omitempty"`
+	Rotation          float32       `yaml:"rotation,omitempty"` // radians
+	Scale             float32       `yaml:"scale,omitempty"`    // 0 = 1
+}
+
+// BurstConfig emits Count particles (or a random count in CountRange) at
+// Time seconds after the effect starts, repeated Cycles times (default 1)
+// every Interval seconds.
+type BurstConfig struct {
+	Time       float32   `yaml:"time"`
+	Count      int       `yaml:"count,omitempty"`
+	CountRange *RangeInt `yaml:"count_range,omitempty"`
+	Cycles     int       `yaml:"cycles,omitempty"`
+	Interval   float32   `yaml:"interval,omitempty"` // seconds between cycles
+}
+
+// RangeInt defines an inclusive min/max integer range.
+type RangeInt struct {
+	Min int `yaml:"min"`
+	Max int `yaml:"max"`
 }
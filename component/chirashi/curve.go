@@ -0,0 +1,84 @@
+package chirashi
+
+import "sort"
+
+// CurveKey is one keyframe of a Curve. InTangent and OutTangent are slopes in
+// value per unit of normalized time on either side of the key.
+type CurveKey struct {
+	T, Value              float32
+	InTangent, OutTangent float32
+}
+
+// Curve is a keyframed value over normalized time, interpolated with cubic
+// Hermite segments. Outside the key range it holds the first or last value.
+type Curve struct {
+	Keys []CurveKey // sorted by T
+}
+
+// NewCurve returns a curve through keys (sorted by T).
+func NewCurve(keys []CurveKey) *Curve {
+	c := &Curve{Keys: append([]CurveKey(nil), keys...)}
+	sort.SliceStable(c.Keys, func(i, j int) bool { return c.Keys[i].T < c.Keys[j].T })
+	return c
+}
+
+// Evaluate returns the curve value at t.
+func (c *Curve) Evaluate(t float32) float32 {
+	n := len(c.Keys)
+	if n == 0 {
+		return 0
+	}
+	if t <= c.Keys[0].T {
+		return c.Keys[0].Value
+	}
+	if t >= c.Keys[n-1].T {
+		return c.Keys[n-1].Value
+	}
+	i := 1
+	for i < n-1 && t > c.Keys[i].T {
+		i++
+	}
+	k0, k1 := &c.Keys[i-1], &c.Keys[i]
+	span := k1.T - k0.T
+	if span <= 0 {
+		return k1.Value
+	}
+	u := (t - k0.T) / span
+	u2 := u * u
+	u3 := u2 * u
+	return (2*u3-3*u2+1)*k0.Value +
+		(u3-2*u2+u)*span*k0.OutTangent +
+		(-2*u3+3*u2)*k1.Value +
+		(u3-u2)*span*k1.InTangent
+}
+
+// buildCurve returns nil unless config is a curve. Omitted tangents take the
+// slope of the adjacent segment, so a curve without tangents is piecewise
+// linear.
+func buildCurve(config *PropertyConfig) *Curve {
+	if config == nil || !config.IsCurve() {
+		return nil
+	}
+	keys := make([]CurveKeyConfig, len(config.Keys))
+	copy(keys, config.Keys)
+	sort.SliceStable(keys, func(i, j int) bool { return keys[i].T < keys[j].T })
+
+	slope := func(i int) float32 {
+		if i < 0 || i+1 >= len(keys) || keys[i+1].T <= keys[i].T {
+			return 0
+		}
+		return (keys[i+1].Value - keys[i].Value) / (keys[i+1].T - keys[i].T)
+	}
+	c := &Curve{Keys: make([]CurveKey, len(keys))}
+	for i, k := range keys {
+		key := CurveKey{T: k.T, Value: k.Value, InTangent: slope(i - 1), OutTangent: slope(i)}
+		if k.In != nil {
+			key.InTangent = *k.In
+		}
+		if k.Out != nil {
+			key.OutTangent = *k.Out
+		}
+		c.Keys[i] = key
+	}
+	return c
+}
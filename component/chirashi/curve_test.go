@@ -0,0 +1,72 @@
+package chirashi
+
+import (
+	"math"
+	"testing"
+)
+
+func nearFloat(got, want float32) bool {
+	return math.Abs(float64(got-want)) < 1e-4
+}
+
+func TestCurveWithoutTangentsIsPiecewiseLinear(t *testing.T) {
+	c := buildCurve(&PropertyConfig{Type: "curve", Keys: []CurveKeyConfig{
+		{T: 1, Value: 0},
+		{T: 0, Value: 0},
+		{T: 0.25, Value: 2},
+	}})
+	cases := []struct{ t, want float32 }{
+		{-1, 0}, {0.125, 1}, {0.25, 2}, {0.625, 1}, {2, 0},
+	}
+	for _, tc := range cases {
+		if got := c.Evaluate(tc.t); !nearFloat(got, tc.want) {
+			t.Fatalf("Evaluate(%v) = %v, want %v", tc.t, got, tc.want)
+		}
+	}
+}
+
+func TestCurveFlatTangentsEaseBetweenKeys(t *testing.T) {
+	c := NewCurve([]CurveKey{{T: 0, Value: 0}, {T: 1, Value: 1}})
+	if got := c.Evaluate(0.5); !nearFloat(got, 0.5) {
+		t.Fatalf("midpoint got %v, want 0.5", got)
+	}
+	// Zero tangents make a smoothstep: slower than linear near the ends.
+	if got := c.Evaluate(0.1); got >= 0.1 || got <= 0 {
+		t.Fatalf("Evaluate(0.1) = %v, want eased value in (0, 0.1)", got)
+	}
+}
+
+func TestCurveExplicitTangentOvershoots(t *testing.T) {
+	out := float32(8)
+	flat := float32(0)
+	c := buildCurve(&PropertyConfig{Type: "curve", Keys: []CurveKeyConfig{
+		{T: 0, Value: 0, Out: &out},
+		{T: 1, Value: 1, In: &flat},
+	}})
+	if got := c.Evaluate(0.5); got <= 1 {
+		t.Fatalf("expected a steep out tangent to overshoot the end value, got %v", got)
+	}
+}
+
+func TestBuildAnimationParamsBuildsCurves(t *testing.T) {
+	config := validParticleConfigForTest()
+	config.Animation.Alpha = PropertyConfig{Type: "curve", Keys: []CurveKeyConfig{{T: 0, Value: 0}, {T: 0.5, Value: 0.8}, {T: 1, Value: 0}}}
+	params := buildAnimationParams(config)
+	if params.Appearance.AlphaCurve == nil {
+		t.Fatal("expected an alpha curve in AnimationParams")
+	}
+	if got := params.Appearance.AlphaCurve.Evaluate(0.5); !nearFloat(got, 0.8) {
+		t.Fatalf("alpha curve at 0.5 got %v, want 0.8", got)
+	}
+}
+
+func TestPositionCurveOffsetsFromStart(t *testing.T) {
+	data := &SystemData{}
+	data.AnimParams.Position.YCurve = NewCurve([]CurveKey{{T: 0, Value: 0}, {T: 1, Value: -100}})
+	p := &Instance{StartX: 10, EndX: 30, StartY: 50, EndY: 0, Duration: 2}
+
+	x, y := evaluateParticleBasePosition(data, p, 1, 0.5)
+	if !nearFloat(x, 20) || !nearFloat(y, 0) {
+		t.Fatalf("got (%v, %v), want x from the lerp (20) and y from the curve (0)", x, y)
+	}
+}
@@ -0,0 +1,88 @@
+package chirashi
+
+import (
+	"reflect"
+	"testing"
+)
+
+// drawSortSystemForTest returns four live particles whose pool order matches
+// none of the sort keys.
+func drawSortSystemForTest(sort DrawSort) *SystemData {
+	particle := func(seq uint64, y, scale float32) Instance {
+		return Instance{
+			Active: true, Duration: 1, SpawnSeq: seq,
+			CurrentY: y, CurrentPosValid: true,
+			StartScale: scale, EndScale: scale,
+		}
+	}
+	return &SystemData{
+		Sort: sort,
+		ParticlePool: []Instance{
+			particle(2, 40, 1.0),
+			particle(0, 10, 0.5),
+			particle(3, 10, 2.0),
+			particle(1, 30, 0.25),
+		},
+		ActiveCount: 4,
+	}
+}
+
+func drawOrderIndices(order []drawOrderEntry) []int32 {
+	indices := make([]int32, len(order))
+	for i, entry := range order {
+		indices[i] = entry.index
+	}
+	return indices
+}
+
+func TestParticleDrawOrder(t *testing.T) {
+	tests := []struct {
+		sort DrawSort
+		want []int32
+	}{
+		{DrawSortOldestFirst, []int32{1, 3, 0, 2}},
+		{DrawSortYoungestFirst, []int32{2, 0, 3, 1}},
+		// Equal y falls back to spawn order.
+		{DrawSortByY, []int32{1, 2, 3, 0}},
+		{DrawSortByScale, []int32{3, 1, 0, 2}},
+	}
+	for _, tt := range tests {
+		data := drawSortSystemForTest(tt.sort)
+		if got := drawOrderIndices(particleDrawOrder(data)); !reflect.DeepEqual(got, tt.want) {
+			t.Fatalf("sort %d: got order %v, want %v", tt.sort, got, tt.want)
+		}
+	}
+
+	if order := particleDrawOrder(drawSortSystemForTest(DrawSortNone)); order != nil {
+		t.Fatalf("expected pool order without sorting, got %v", order)
+	}
+}
+
+func TestParticleDrawOrderReusesPermutation(t *testing.T) {
+	data := drawSortSystemForTest(DrawSortByY)
+	particleDrawOrder(data)
+	allocs := testing.AllocsPerRun(10, func() {
+		particleDrawOrder(data)
+	})
+	if allocs != 0 {
+		t.Fatalf("expected the permutation buffer to be reused, got %v allocations", allocs)
+	}
+}
+
+func TestParticleDrawOrderFollowsPoolChanges(t *testing.T) {
+	data := drawSortSystemForTest(DrawSortOldestFirst)
+	particleDrawOrder(data)
+
+	// Expire the particle in slot 1 by moving the last one into its place,
+	// then spawn a new one, as the pool does.
+	data.ParticlePool[1] = data.ParticlePool[3]
+	data.ParticlePool[3] = Instance{Active: true, Duration: 1, SpawnSeq: 4, StartScale: 1, EndScale: 1}
+	if got, want := drawOrderIndices(particleDrawOrder(data)), []int32{1, 0, 2, 3}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got order %v, want %v", got, want)
+	}
+
+	data.ActiveCount = 2
+	if got, want := drawOrderIndices(particleDrawOrder(data)), []int32{1, 0}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got order %v after shrinking, want %v", got, want)
+	}
+}
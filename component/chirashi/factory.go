@@ -3,11 +3,13 @@ package chirashi
 import (
 	"fmt"
 	"math"
+	"math/rand/v2"
 	"sync"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/mogeta/chirashi/assets"
 	"github.com/yohamta/donburi"
+	"github.com/yohamta/donburi/ecs"
 )
 
 const fullCircleEpsilon = float32(0.01)
@@ -30,12 +32,15 @@ var (
 	builtinBlurShaderErr  error
 )
 
-// NewParticlesFromConfig creates a GPU particle system from a configuration struct
+// NewParticlesFromConfig creates a GPU particle system from a configuration struct.
+// It has no font registry, so text vector emitters are rejected; spawn them
+// through a ParticleManager or replace the vector with NewTextVector.
 func NewParticlesFromConfig(w donburi.World, shader *ebiten.Shader, image *ebiten.Image, config *ParticleConfig, x, y float32) error {
 	return createParticlesFromConfig(w, shader, image, config, x, y)
 }
 
-// NewParticlesFromFile creates a GPU particle system from a configuration file path
+// NewParticlesFromFile creates a GPU particle system from a configuration file path.
+// Like NewParticlesFromConfig, it rejects text vector emitters.
 func NewParticlesFromFile(w donburi.World, shader *ebiten.Shader, image *ebiten.Image, configPath string, x, y float32) error {
 	config, err := configLoader.LoadConfig(configPath)
 	if err != nil {
@@ -47,20 +52,46 @@ func NewParticlesFromFile(w donburi.World, shader *ebiten.Shader, image *ebiten.
 
 // createParticlesFromConfig creates particles from a loaded configuration
 func createParticlesFromConfig(w donburi.World, shader *ebiten.Shader, image *ebiten.Image, config *ParticleConfig, x, y float32) error {
-	_, err := createParticleEntityFromConfig(w, shader, image, config, x, y)
+	if vector := config.Emitter.Vector; vector != nil && vector.Type == "text" {
+		return fmt.Errorf("emitter.vector.type text needs a font registry: spawn it through ParticleManager or lay it out with NewTextVector")
+	}
+	_, err := createParticleEntityFromConfig(w, shader, image, nil, nil, config, x, y)
 	return err
 }
 
-func createParticleEntityFromConfig(w donburi.World, shader *ebiten.Shader, image *ebiten.Image, config *ParticleConfig, x, y float32) (donburi.Entity, error) {
+// createParticleEntityFromConfig creates one particle entity. The particle
+// image is resolved from config.Image against textures, falling back to
+// image when the reference is not registered. Sprite emitters resolve their
+// sprite against textures too and fail on an unregistered one, and text
+// vector emitters look their font up in fonts.
+func createParticleEntityFromConfig(w donburi.World, shader *ebiten.Shader, image *ebiten.Image, textures *TextureRegistry, fonts *FontRegistry, config *ParticleConfig, x, y float32) (donburi.Entity, error) {
 	normalizeParticleConfig(config)
+	image = textures.Resolve(config.Image, image)
 	resolvedShader, err := resolveParticleShader(shader, config.Render.ParticleShader)
 	if err != nil {
 		return 0, err
 	}
+	var textVector *EmitterVectorConfig
+	if vector := config.Emitter.Vector; vector != nil && vector.Type == "text" {
+		textVector, err = resolveTextVector(vector, fonts)
+		if err != nil {
+			return 0, err
+		}
+	}
+	spriteImage, err := resolveSpriteImage(config.Emitter.Sprite, textures)
+	if err != nil {
+		return 0, err
+	}
 
 	entity := w.Create(Component)
 	entry := w.Entry(entity)
 	systemData := buildSystemDataFromConfig(resolvedShader, image, config, x, y)
+	if textVector != nil {
+		systemData.EmitterVector = buildEmitterVectorParams(textVector)
+	}
+	if spriteImage != nil {
+		systemData.Sprite.Image = spriteImage
+	}
 
 	// Apply sequence configurations if present
 	buildSequenceConfigs(config, &systemData)
@@ -99,38 +130,60 @@ func buildSystemDataFromConfig(shader *ebiten.Shader, image *ebiten.Image, confi
 	}
 	maxIndices := maxIndexQuads * 6
 
-	var imgWidth, imgHeight float32
+	var imgX, imgY, imgWidth, imgHeight float32
 	if image != nil {
 		bounds := image.Bounds()
+		imgX = float32(bounds.Min.X)
+		imgY = float32(bounds.Min.Y)
 		imgWidth = float32(bounds.Dx())
 		imgHeight = float32(bounds.Dy())
 	}
 
 	data := SystemData{
-		ParticlePool:      make([]Instance, config.Spawn.MaxParticles),
-		Vertices:          make([]ebiten.Vertex, 0, maxVertices),
-		Indices:           make([]uint16, 0, maxIndices),
-		Shader:            shader,
-		CurrentTime:       0,
-		EmitterX:          emitterX,
-		EmitterY:          emitterY,
-		EmitterShape:      buildEmitterShapeParams(config.Emitter.Shape),
-		EmitterVector:     buildEmitterVectorParams(config.Emitter.Vector),
-		EmitterLocalSpace: config.Emitter.Space != EmitterSpaceWorld,
-		SpawnInterval:     config.Spawn.Interval,
-		ParticlesPerSpawn: config.Spawn.ParticlesPerSpawn,
-		MaxParticles:      config.Spawn.MaxParticles,
-		EmissionScale:     1,
-		SourceImage:       image,
-		ImageWidth:        imgWidth,
-		ImageHeight:       imgHeight,
-		Blend:             ParseBlendMode(config.Blend),
-		ShaderUniforms:    make(map[string]interface{}, 4),
-		Trail:             buildTrailData(config.Trail),
-		ActiveCount:       0,
-		IsLoop:            config.Spawn.IsLoop,
-		LifeTime:          config.Spawn.LifeTime,
-		AnimParams:        animParams,
+		ParticlePool:          make([]Instance, config.Spawn.MaxParticles),
+		Vertices:              make([]ebiten.Vertex, 0, maxVertices),
+		Indices:               make([]uint16, 0, maxIndices),
+		Shader:                shader,
+		CurrentTime:           0,
+		EmitterX:              emitterX,
+		EmitterY:              emitterY,
+		EmitterShape:          buildEmitterShapeParams(config.Emitter.Shape),
+		EmitterVector:         buildEmitterVectorParams(config.Emitter.Vector),
+		EmitterLocalSpace:     config.Emitter.Space != EmitterSpaceWorld,
+		Sprite:                buildEmitterSpriteParams(config.Emitter.Sprite),
+		SpawnInterval:         config.Spawn.Interval,
+		ParticlesPerSpawn:     config.Spawn.ParticlesPerSpawn,
+		SpawnRate:             config.Spawn.Rate,
+		SpawnRateOverDistance: config.Spawn.RateOverDistance,
+		MaxParticles:          config.Spawn.MaxParticles,
+		EmissionScale:         1,
+		TimeScale:             1,
+		EmitterRotation:       config.Spawn.Rotation,
+		EmitterScale:          config.Spawn.Scale,
+		SourceImage:           image,
+		ImageX:                imgX,
+		ImageY:                imgY,
+		ImageWidth:            imgWidth,
+		ImageHeight:           imgHeight,
+		Blend:                 ParseBlendMode(config.Blend),
+		Sort:                  parseDrawSort(config.Render.Sort),
+		Layer:                 ecs.LayerID(config.Render.Layer),
+		drawSeq:               nextDrawSeq(),
+		ShaderUniforms:        make(map[string]interface{}, 4),
+		Trail:                 buildTrailData(config.Trail),
+		ActiveCount:           0,
+		IsLoop:                config.Spawn.IsLoop,
+		LifeTime:              config.Spawn.LifeTime,
+		AnimParams:            animParams,
+		SubEmitters:           buildSubEmitterParams(config.SubEmitters),
+	}
+	setBursts(&data, buildBurstParams(config.Spawn.Bursts))
+	setEmitterMotion(&data, buildEmitterMotionParams(config.Emitter.Motion))
+	applyCollisionConfig(&data, config.Collision, emitterX, emitterY)
+	if config.Seed != nil {
+		seedSystemRand(&data, *config.Seed)
+	} else {
+		seedSystemRand(&data, rand.Uint64())
 	}
 	if data.Trail.Params.Enabled && data.Trail.Params.Mode == "particle" {
 		maxTrailVertices := config.Spawn.MaxParticles * data.Trail.Params.MaxPoints * 2
@@ -215,12 +268,67 @@ func buildEmitterVectorParams(config *EmitterVectorConfig) EmitterVectorParams {
 			Rotation: config.Rect.Rotation,
 		}
 	}
+	if params.Type != EmitterVectorPolyline {
+		if config.Polyline != nil {
+			params.Polyline = buildEmitterVectorPolylineParams(config.Polyline)
+		}
+		return params
+	}
+
+	polylines := make([]*EmitterVectorPolylineConfig, 0, 1+len(config.Polylines))
 	if config.Polyline != nil {
-		params.Polyline = buildEmitterVectorPolylineParams(config.Polyline)
+		polylines = append(polylines, config.Polyline)
+	}
+	for i := range config.Polylines {
+		polylines = append(polylines, &config.Polylines[i])
+	}
+	parts := make([]EmitterVectorPolylineParams, 0, len(polylines))
+	for _, polyline := range polylines {
+		part := buildEmitterVectorPolylineParams(polyline)
+		if !polyline.Closed {
+			parts = append(parts, part)
+			continue
+		}
+		if params.Placement == EmitterVectorFill {
+			buildEmitterVectorPolylineFill(&part, polyline.Holes)
+			parts = append(parts, part)
+			continue
+		}
+		// Surface placement walks hole outlines like any other closed outline.
+		parts = append(parts, part)
+		for _, hole := range polyline.Holes {
+			parts = append(parts, buildEmitterVectorPolylineParams(&EmitterVectorPolylineConfig{Closed: true, Points: hole}))
+		}
+	}
+	if len(parts) > 0 {
+		params.Polyline = parts[0]
+		params.Polylines = parts[1:]
+	}
+	if len(params.Polylines) == 0 {
+		params.Polylines = nil
 	}
 	return params
 }
 
+// buildEmitterVectorPolylineFill triangulates the polyline interior minus
+// holes for area-uniform fill sampling.
+func buildEmitterVectorPolylineFill(params *EmitterVectorPolylineParams, holes [][]EmitterVectorPoint) {
+	holePoints := make([][]EmitterVectorPointParams, len(holes))
+	for i, hole := range holes {
+		holePoints[i] = make([]EmitterVectorPointParams, len(hole))
+		for j, point := range hole {
+			holePoints[i][j] = EmitterVectorPointParams(point)
+		}
+	}
+	params.Triangles = triangulatePolygon(params.Points, holePoints)
+	params.TriangleAreas = make([]float32, len(params.Triangles))
+	params.TotalArea = 0
+	for i, triangle := range params.Triangles {
+		params.TotalArea += triangleArea(triangle)
+		params.TriangleAreas[i] = params.TotalArea
+	}
+}
+
 func buildEmitterVectorPolylineParams(config *EmitterVectorPolylineConfig) EmitterVectorPolylineParams {
 	params := EmitterVectorPolylineParams{
 		Closed:         config.Closed,
@@ -362,6 +470,7 @@ func buildAnimationParams(config *ParticleConfig) AnimationParams {
 	pos := PositionParams{
 		UsePolar:     posType == "polar",
 		UseAttractor: posType == "attractor",
+		UsePhysics:   posType == "physics",
 		Easing:       ParseEasing(config.Animation.Position.Easing),
 	}
 	switch {
@@ -393,6 +502,33 @@ func buildAnimationParams(config *ParticleConfig) AnimationParams {
 			pos.AngularSpeedMax = config.Animation.Position.AngularSpeed.Max
 			pos.UsePolarVelocity = true
 		}
+	case pos.UsePhysics:
+		physics := &config.Animation.Position
+		if physics.Angle != nil {
+			pos.AngleMin = physics.Angle.Min
+			pos.AngleMax = physics.Angle.Max
+		}
+		if physics.Distance != nil {
+			pos.DistMin = physics.Distance.Min
+			pos.DistMax = physics.Distance.Max
+		}
+		if physics.Speed != nil {
+			pos.SpeedMin = physics.Speed.Min
+			pos.SpeedMax = physics.Speed.Max
+		}
+		if physics.VelocityX != nil {
+			pos.VelXMin = physics.VelocityX.Min
+			pos.VelXMax = physics.VelocityX.Max
+		}
+		if physics.VelocityY != nil {
+			pos.VelYMin = physics.VelocityY.Min
+			pos.VelYMax = physics.VelocityY.Max
+		}
+		pos.AccelX = physics.AccelerationX
+		pos.AccelY = physics.AccelerationY
+		pos.LinearDrag = physics.LinearDrag
+		pos.QuadraticDrag = physics.QuadraticDrag
+		pos.TerminalSpeed = physics.TerminalSpeed
 	default: // cartesian
 		if config.Animation.Position.StartX != nil {
 			pos.StartXMin = config.Animation.Position.StartX.Min
@@ -410,6 +546,8 @@ func buildAnimationParams(config *ParticleConfig) AnimationParams {
 			pos.EndYMin = config.Animation.Position.EndY.Min
 			pos.EndYMax = config.Animation.Position.EndY.Max
 		}
+		pos.XCurve = buildCurve(config.Animation.Position.X)
+		pos.YCurve = buildCurve(config.Animation.Position.Y)
 	}
 	if flow := config.Animation.Position.Flow; flow != nil {
 		pos.HasFlow = true
@@ -452,11 +590,27 @@ func buildAnimationParams(config *ParticleConfig) AnimationParams {
 		StartRotation:  config.Animation.Rotation.Start,
 		EndRotation:    config.Animation.Rotation.End,
 		RotationEasing: ParseEasing(config.Animation.Rotation.Easing),
+		AlphaCurve:     buildCurve(&config.Animation.Alpha),
+		ScaleCurve:     buildCurve(&config.Animation.Scale),
+		RotationCurve:  buildCurve(&config.Animation.Rotation),
+		AlignVelocity:  config.Animation.Align == "velocity",
+	}
+	if app.AlignVelocity {
+		app.Stretch = config.Animation.Stretch
 	}
-	if app.StartScale == 0 && app.EndScale == 0 {
-		app.StartScale = 1.0
-		app.EndScale = 1.0
+	if sx := config.Animation.ScaleX; sx != nil {
+		app.HasScaleX = true
+		app.StartScaleX, app.EndScaleX = defaultScalePair(sx.Start, sx.End)
+		app.ScaleXEasing = ParseEasing(sx.Easing)
+		app.ScaleXCurve = buildCurve(sx)
 	}
+	if sy := config.Animation.ScaleY; sy != nil {
+		app.HasScaleY = true
+		app.StartScaleY, app.EndScaleY = defaultScalePair(sy.Start, sy.End)
+		app.ScaleYEasing = ParseEasing(sy.Easing)
+		app.ScaleYCurve = buildCurve(sy)
+	}
+	app.StartScale, app.EndScale = defaultScalePair(app.StartScale, app.EndScale)
 
 	var clr ColorParams
 	if config.Animation.Color != nil {
@@ -479,6 +633,9 @@ func buildAnimationParams(config *ParticleConfig) AnimationParams {
 			clr.End2G = v.EndG
 			clr.End2B = v.EndB
 		}
+		clr.Space = parseColorSpace(config.Animation.Color.Space)
+		clr.Gradient = buildColorGradient(config.Animation.Color)
+		clr.Palette = buildColorPalette(config.Animation.Color)
 	} else {
 		clr = ColorParams{StartR: 1, StartG: 1, StartB: 1, EndR: 1, EndG: 1, EndB: 1}
 	}
@@ -488,6 +645,7 @@ func buildAnimationParams(config *ParticleConfig) AnimationParams {
 		Position:   pos,
 		Appearance: app,
 		Color:      clr,
+		Frames:     buildFrameParams(config.Animation.Frames),
 	}
 }
 
@@ -519,12 +677,22 @@ func buildSequenceConfig(prop *PropertyConfig) *SequenceConfig {
 	return NewSequenceConfig(steps)
 }
 
+// defaultScalePair treats an all-zero start/end scale as unset (1 -> 1).
+func defaultScalePair(start, end float32) (float32, float32) {
+	if start == 0 && end == 0 {
+		return 1, 1
+	}
+	return start, end
+}
+
 // buildSequenceConfigs extracts sequence configs from the particle config and sets them on SystemData
 func buildSequenceConfigs(config *ParticleConfig, data *SystemData) {
 	data.PosXSeq = nil
 	data.PosYSeq = nil
 	data.AlphaSeq = nil
 	data.ScaleSeq = nil
+	data.ScaleXSeq = nil
+	data.ScaleYSeq = nil
 	data.RotSeq = nil
 
 	// Position X/Y sequences
@@ -544,6 +712,12 @@ func buildSequenceConfigs(config *ParticleConfig, data *SystemData) {
 	if config.Animation.Scale.IsSequence() {
 		data.ScaleSeq = buildSequenceConfig(&config.Animation.Scale)
 	}
+	if config.Animation.ScaleX != nil && config.Animation.ScaleX.IsSequence() {
+		data.ScaleXSeq = buildSequenceConfig(config.Animation.ScaleX)
+	}
+	if config.Animation.ScaleY != nil && config.Animation.ScaleY.IsSequence() {
+		data.ScaleYSeq = buildSequenceConfig(config.Animation.ScaleY)
+	}
 
 	// Rotation sequence
 	if config.Animation.Rotation.IsSequence() {
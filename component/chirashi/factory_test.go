@@ -44,6 +44,9 @@ func TestBuildSystemDataFromConfigDefaultsEmissionScale(t *testing.T) {
 	if data.EmissionScale != 1 {
 		t.Errorf("EmissionScale = %v, want 1", data.EmissionScale)
 	}
+	if data.TimeScale != 1 {
+		t.Errorf("TimeScale = %v, want 1", data.TimeScale)
+	}
 }
 
 func TestResolveParticleShaderCompilesAndCachesBuiltinBlur(t *testing.T) {
@@ -72,7 +75,7 @@ func TestCreateParticleEntityUsesConfiguredBlurShader(t *testing.T) {
 		Render: RenderConfig{ParticleShader: "blur"},
 		Spawn:  SpawnConfig{MaxParticles: 1},
 	}
-	entity, err := createParticleEntityFromConfig(world, nil, nil, config, 0, 0)
+	entity, err := createParticleEntityFromConfig(world, nil, nil, nil, nil, config, 0, 0)
 	if err != nil {
 		t.Fatalf("createParticleEntityFromConfig: %v", err)
 	}
@@ -80,3 +83,36 @@ func TestCreateParticleEntityUsesConfiguredBlurShader(t *testing.T) {
 		t.Fatal("configured blur shader was not assigned to SystemData")
 	}
 }
+
+func TestScaleAxesOverrideUniformScale(t *testing.T) {
+	config := validParticleConfigForTest()
+	config.Animation.Scale = PropertyConfig{Start: 2, End: 2, Easing: "Linear"}
+	config.Animation.ScaleX = &PropertyConfig{Start: 1, End: 3, Easing: "Linear"}
+	config.Animation.ScaleY = &PropertyConfig{
+		Type: "sequence",
+		Steps: []StepConfig{
+			{From: 0.5, To: 0.5, ToRange: &RangeFloat{Min: 0.25, Max: 0.75}, Duration: 1, Easing: "Linear"},
+		},
+	}
+	data := buildSystemDataFromConfig(nil, nil, config, 0, 0)
+	buildSequenceConfigs(config, &data)
+	(&System{}).spawn(&data, defaultDeltaTime)
+	if data.ActiveCount == 0 {
+		t.Fatal("expected a particle to spawn")
+	}
+	p := &data.ParticlePool[0]
+
+	sx, sy := particleScaleAxes(&data, p, 2, 0.5, 0.5)
+	if sx != 2 {
+		t.Fatalf("scale_x at half life got %v, want 2", sx)
+	}
+	if sy < 0.375 || sy > 0.625 {
+		t.Fatalf("scale_y sequence got %v, want halfway from 0.5 to a target in [0.25,0.75]", sy)
+	}
+
+	config.Animation.ScaleX = nil
+	data.AnimParams = buildAnimationParams(config)
+	if sx, _ := particleScaleAxes(&data, p, 2, 0.5, 0.5); sx != 2 {
+		t.Fatalf("an axis without scale_x should follow the uniform scale, got %v", sx)
+	}
+}
@@ -0,0 +1,84 @@
+package chirashi
+
+import "math"
+
+func buildFrameParams(config *FramesConfig) FrameParams {
+	if config == nil || config.Columns <= 0 || config.Rows <= 0 {
+		return FrameParams{}
+	}
+	params := FrameParams{
+		Enabled:      true,
+		Columns:      config.Columns,
+		Rows:         config.Rows,
+		Count:        config.Count,
+		OverLifetime: config.Mode == "lifetime",
+		FPS:          config.FPS,
+		Cycles:       config.Cycles,
+		RandomStart:  config.RandomStart,
+	}
+	if cells := config.Columns * config.Rows; params.Count <= 0 || params.Count > cells {
+		params.Count = cells
+	}
+	if params.Cycles <= 0 {
+		params.Cycles = 1
+	}
+	switch config.Loop {
+	case "once":
+		params.Loop = FrameLoopOnce
+	case "ping_pong":
+		params.Loop = FrameLoopPingPong
+	default:
+		params.Loop = FrameLoopRepeat
+	}
+	return params
+}
+
+// particleFrame returns the sheet cell a particle shows at elapsed seconds
+// (normalizedT is its lifetime progress, used by lifetime mode).
+func particleFrame(frames *FrameParams, p *Instance, elapsed, normalizedT float32) int {
+	count := frames.Count
+	if count <= 1 {
+		return 0
+	}
+	var step int
+	if frames.OverLifetime {
+		step = int(normalizedT * frames.Cycles * float32(count))
+		if normalizedT >= 1 && step > 0 {
+			// Show the last frame of the final cycle at end of life.
+			step--
+		}
+	} else {
+		step = int(math.Floor(float64(elapsed * frames.FPS)))
+	}
+	if step < 0 {
+		step = 0
+	}
+	step += p.StartFrame
+
+	switch frames.Loop {
+	case FrameLoopOnce:
+		if step >= count {
+			return count - 1
+		}
+		return step
+	case FrameLoopPingPong:
+		period := 2*count - 2
+		step %= period
+		if step >= count {
+			step = period - step
+		}
+		return step
+	default:
+		return step % count
+	}
+}
+
+// frameSourceRect returns the top-left corner and size of a sheet cell in
+// source-image pixels.
+func frameSourceRect(frames *FrameParams, frame int, imgW, imgH float32) (float32, float32, float32, float32) {
+	cellW := imgW / float32(frames.Columns)
+	cellH := imgH / float32(frames.Rows)
+	col := frame % frames.Columns
+	row := frame / frames.Columns
+	return float32(col) * cellW, float32(row) * cellH, cellW, cellH
+}
@@ -0,0 +1,50 @@
+package chirashi
+
+import "testing"
+
+func TestParticleFrameLoopModes(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  FramesConfig
+		start   int
+		elapsed float32
+		want    int
+	}{
+		{"fps repeat", FramesConfig{Columns: 4, Rows: 1, FPS: 10}, 0, 0.55, 1},
+		{"fps repeat wraps", FramesConfig{Columns: 4, Rows: 1, FPS: 10}, 0, 0.95, 1},
+		{"random start offset", FramesConfig{Columns: 4, Rows: 1, FPS: 10}, 3, 0.15, 0},
+		{"once holds last frame", FramesConfig{Columns: 4, Rows: 1, FPS: 10, Loop: "once"}, 0, 2, 3},
+		{"ping pong returns", FramesConfig{Columns: 4, Rows: 1, FPS: 10, Loop: "ping_pong"}, 0, 0.45, 2},
+		{"count limits sheet", FramesConfig{Columns: 4, Rows: 2, Count: 5, FPS: 10}, 0, 0.65, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			frames := buildFrameParams(&tt.config)
+			p := &Instance{StartFrame: tt.start}
+			if got := particleFrame(&frames, p, tt.elapsed, 0); got != tt.want {
+				t.Fatalf("frame got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParticleFrameOverLifetime(t *testing.T) {
+	frames := buildFrameParams(&FramesConfig{Columns: 2, Rows: 2, Mode: "lifetime"})
+	p := &Instance{}
+	for _, tt := range []struct {
+		normalizedT float32
+		want        int
+	}{{0, 0}, {0.3, 1}, {0.99, 3}, {1, 3}} {
+		if got := particleFrame(&frames, p, 0, tt.normalizedT); got != tt.want {
+			t.Fatalf("t=%v: frame got %d, want %d", tt.normalizedT, got, tt.want)
+		}
+	}
+}
+
+func TestFrameSourceRectUsesRowMajorCells(t *testing.T) {
+	frames := buildFrameParams(&FramesConfig{Columns: 4, Rows: 2, FPS: 1})
+	x, y, w, h := frameSourceRect(&frames, 6, 128, 64)
+	if x != 64 || y != 32 || w != 32 || h != 32 {
+		t.Fatalf("got (%v, %v, %v, %v), want (64, 32, 32, 32)", x, y, w, h)
+	}
+}
@@ -0,0 +1,175 @@
+package chirashi
+
+import (
+	"math"
+
+	"github.com/yohamta/donburi"
+)
+
+// Turbulence samples the curl field with fixed detail so fields stay cheap.
+const (
+	forceTurbulenceOctaves     = 2
+	forceTurbulencePersistence = float32(0.5)
+	defaultForceTurbulenceSize = float32(120)
+)
+
+// ForceFieldType identifies how a force field pushes particles.
+type ForceFieldType int
+
+const (
+	ForceFieldPoint ForceFieldType = iota
+	ForceFieldWind
+	ForceFieldVortex
+	ForceFieldTurbulence
+)
+
+// ForceFalloff controls how a field weakens toward its radius.
+type ForceFalloff int
+
+const (
+	ForceFalloffLinear ForceFalloff = iota
+	ForceFalloffNone
+	ForceFalloffQuadratic
+)
+
+// ForceFieldData is a world-space force that acts on the integrated
+// particles (physics mode, or particles that bounced off a collider) of every
+// particle entity. Build one with NewPointForceField, NewWindForceField,
+// NewVortexForceField or NewTurbulenceForceField and add it with
+// AddForceField; the fields can be changed in place each frame.
+type ForceFieldData struct {
+	Type ForceFieldType
+	X, Y float32 // field center
+	// Strength is an acceleration in units/sec^2. A negative point field
+	// repels; a positive vortex turns clockwise on screen (y down).
+	Strength   float32
+	DirX, DirY float32 // wind: unit direction
+	Radius     float32 // 0 = unlimited range
+	Falloff    ForceFalloff
+	Scale      float32 // turbulence: feature size in units
+	// Duration removes the field after that many seconds of (scaled)
+	// simulation time; 0 keeps it until the caller removes the entity.
+	Duration float32
+	Age      float32
+}
+
+// ForceField is the Donburi component type for global force fields.
+var ForceField = donburi.NewComponentType[ForceFieldData]()
+
+// NewPointForceField returns an attractor (strength > 0) or repulsor
+// (strength < 0) centered on (x, y).
+func NewPointForceField(x, y, strength, radius float32) ForceFieldData {
+	return ForceFieldData{Type: ForceFieldPoint, X: x, Y: y, Strength: strength, Radius: radius}
+}
+
+// NewWindForceField returns a directional push. Give it a radius and center
+// to limit it to a zone.
+func NewWindForceField(dirX, dirY, strength float32) ForceFieldData {
+	length := float32(math.Hypot(float64(dirX), float64(dirY)))
+	if length > 0 {
+		dirX /= length
+		dirY /= length
+	}
+	return ForceFieldData{Type: ForceFieldWind, DirX: dirX, DirY: dirY, Strength: strength}
+}
+
+// NewVortexForceField returns a swirl around (x, y).
+func NewVortexForceField(x, y, strength, radius float32) ForceFieldData {
+	return ForceFieldData{Type: ForceFieldVortex, X: x, Y: y, Strength: strength, Radius: radius}
+}
+
+// NewTurbulenceForceField returns a curl-noise gust zone around (x, y).
+func NewTurbulenceForceField(x, y, strength, radius, scale float32) ForceFieldData {
+	return ForceFieldData{Type: ForceFieldTurbulence, X: x, Y: y, Strength: strength, Radius: radius, Scale: scale}
+}
+
+// AddForceField creates a force-field entity.
+func AddForceField(world donburi.World, field ForceFieldData) donburi.Entity {
+	entity := world.Create(ForceField)
+	donburi.SetValue(world.Entry(entity), ForceField, field)
+	return entity
+}
+
+// collectForceFields snapshots every force field for this update and removes
+// fields whose Duration has elapsed. The snapshot is shared read-only by the
+// simulation goroutines.
+func (sys *System) collectForceFields(world donburi.World, deltaTime float32) {
+	sys.forceFields = sys.forceFields[:0]
+	if sys.forceFieldQuery == nil {
+		return
+	}
+	sys.expiredForceFields = sys.expiredForceFields[:0]
+	for entry := range sys.forceFieldQuery.Iter(world) {
+		field := ForceField.Get(entry)
+		if field.Duration > 0 {
+			field.Age += deltaTime
+			if field.Age >= field.Duration {
+				sys.expiredForceFields = append(sys.expiredForceFields, entry.Entity())
+				continue
+			}
+		}
+		sys.forceFields = append(sys.forceFields, *field)
+	}
+	for _, entity := range sys.expiredForceFields {
+		world.Remove(entity)
+	}
+}
+
+// forceFieldAcceleration sums the acceleration every field applies at (x, y).
+func forceFieldAcceleration(fields []ForceFieldData, x, y, t float32) (float32, float32) {
+	ax, ay := float32(0), float32(0)
+	for i := range fields {
+		f := &fields[i]
+		dx := f.X - x
+		dy := f.Y - y
+		distSq := dx*dx + dy*dy
+		weight := float32(1)
+		if f.Radius > 0 {
+			if distSq >= f.Radius*f.Radius {
+				continue
+			}
+			weight = forceFalloffWeight(f.Falloff, float32(math.Sqrt(float64(distSq)))/f.Radius)
+		}
+		strength := f.Strength * weight
+		switch f.Type {
+		case ForceFieldWind:
+			ax += f.DirX * strength
+			ay += f.DirY * strength
+		case ForceFieldPoint, ForceFieldVortex:
+			if distSq == 0 {
+				continue
+			}
+			dist := float32(math.Sqrt(float64(distSq)))
+			nx := dx / dist
+			ny := dy / dist
+			if f.Type == ForceFieldPoint {
+				ax += nx * strength
+				ay += ny * strength
+			} else {
+				// Tangent of the offset from the center.
+				ax += ny * strength
+				ay -= nx * strength
+			}
+		case ForceFieldTurbulence:
+			scale := f.Scale
+			if scale <= 0 {
+				scale = defaultForceTurbulenceSize
+			}
+			fx, fy := sampleCurlNoiseField(x/scale, y/scale, t, forceTurbulenceOctaves, forceTurbulencePersistence)
+			ax += fx * strength
+			ay += fy * strength
+		}
+	}
+	return ax, ay
+}
+
+func forceFalloffWeight(falloff ForceFalloff, ratio float32) float32 {
+	switch falloff {
+	case ForceFalloffNone:
+		return 1
+	case ForceFalloffQuadratic:
+		return (1 - ratio) * (1 - ratio)
+	default:
+		return 1 - ratio
+	}
+}
@@ -0,0 +1,82 @@
+package chirashi
+
+import (
+	"math"
+	"testing"
+
+	"github.com/yohamta/donburi"
+	"github.com/yohamta/donburi/ecs"
+)
+
+func TestForceFieldAccelerationShapes(t *testing.T) {
+	tests := []struct {
+		name   string
+		field  ForceFieldData
+		x, y   float32
+		ax, ay float32
+	}{
+		{"attractor linear falloff", NewPointForceField(100, 0, 10, 200), 0, 0, 5, 0},
+		{"repulsor", NewPointForceField(100, 0, -10, 0), 0, 0, -10, 0},
+		{"outside radius", NewPointForceField(100, 0, 10, 50), 0, 0, 0, 0},
+		{"wind is normalized", NewWindForceField(3, 4, 10), 0, 0, 6, 8},
+		{"vortex turns clockwise", NewVortexForceField(0, 0, 10, 0), 10, 0, 0, 10},
+		{"quadratic falloff", ForceFieldData{Type: ForceFieldPoint, X: 100, Strength: 8, Radius: 200, Falloff: ForceFalloffQuadratic}, 0, 0, 2, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ax, ay := forceFieldAcceleration([]ForceFieldData{tt.field}, tt.x, tt.y, 0)
+			if math.Abs(float64(ax-tt.ax)) > 1e-4 || math.Abs(float64(ay-tt.ay)) > 1e-4 {
+				t.Fatalf("got (%v, %v), want (%v, %v)", ax, ay, tt.ax, tt.ay)
+			}
+		})
+	}
+}
+
+func TestUpdateAppliesForceFieldsToEveryIntegratedSystem(t *testing.T) {
+	world := donburi.NewWorld()
+	gameECS := ecs.NewECS(world)
+	sys := NewSystem()
+
+	newSystem := func(integrated bool) *SystemData {
+		entry := world.Entry(world.Create(Component))
+		donburi.SetValue(entry, Component, SystemData{
+			ParticlePool: []Instance{
+				{Active: true, Duration: 10, Integrated: integrated, PositionEasing: EasingLinear, CurrentPosValid: true},
+			},
+			ActiveCount:   1,
+			IsLoop:        true,
+			MaxParticles:  1,
+			EmissionScale: 1,
+			TimeScale:     1,
+		})
+		return Component.Get(entry)
+	}
+	smoke := newSystem(true)
+	debris := newSystem(true)
+	sparks := newSystem(false)
+
+	wind := NewWindForceField(1, 0, 60)
+	wind.Duration = defaultDeltaTime * 2.5
+	field := AddForceField(world, wind)
+
+	sys.Update(gameECS)
+	for i, data := range []*SystemData{smoke, debris} {
+		if p := data.ParticlePool[0]; p.VelX <= 0 || p.CurrentX <= 0 {
+			t.Fatalf("system %d: expected wind to push the integrated particle, vel=%v x=%v", i, p.VelX, p.CurrentX)
+		}
+	}
+	if x := sparks.ParticlePool[0].CurrentX; x != 0 {
+		t.Fatalf("closed-form particle should ignore force fields, x=%v", x)
+	}
+
+	sys.Update(gameECS)
+	sys.Update(gameECS)
+	if world.Valid(field) {
+		t.Fatal("expected force field to be removed after its duration")
+	}
+	vel := smoke.ParticlePool[0].VelX
+	sys.Update(gameECS)
+	if smoke.ParticlePool[0].VelX != vel {
+		t.Fatalf("removed field still accelerates particles: %v -> %v", vel, smoke.ParticlePool[0].VelX)
+	}
+}
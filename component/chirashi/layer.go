@@ -0,0 +1,94 @@
+package chirashi
+
+import (
+	"cmp"
+	"slices"
+	"sync/atomic"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/yohamta/donburi"
+	"github.com/yohamta/donburi/ecs"
+)
+
+// drawSeq numbers particle entities in creation order so effects within a
+// render layer draw in a stable order: later effects on top.
+var drawSeq atomic.Uint64
+
+func nextDrawSeq() uint64 {
+	return drawSeq.Add(1)
+}
+
+// layerDrawEntry is one particle entity queued for drawing.
+type layerDrawEntry struct {
+	seq    uint64
+	entity donburi.Entity
+	data   *SystemData
+}
+
+// collectDrawEntries returns the particle entities to draw in draw order:
+// by render layer, then creation order. With filter set, only entities on
+// layer are returned. The slice is reused by the next call.
+func (sys *System) collectDrawEntries(world donburi.World, filter bool, layer ecs.LayerID) []layerDrawEntry {
+	entries := sys.drawEntries[:0]
+	for entry := range sys.query.Iter(world) {
+		data := Component.Get(entry)
+		if filter && data.Layer != layer {
+			continue
+		}
+		entries = append(entries, layerDrawEntry{seq: data.drawSeq, entity: entry.Entity(), data: data})
+	}
+	slices.SortFunc(entries, func(a, b layerDrawEntry) int {
+		if c := cmp.Compare(a.data.Layer, b.data.Layer); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(a.seq, b.seq); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.entity.Id(), b.entity.Id())
+	})
+	sys.drawEntries = entries
+	return entries
+}
+
+// DrawLayer renders only the particle entities on layer (render.layer), so
+// effects can be drawn between other renderers, e.g. behind characters and
+// in front of them. Entities on one layer draw in creation order.
+func (sys *System) DrawLayer(ecs *ecs.ECS, screen *ebiten.Image, layer ecs.LayerID) {
+	sys.DrawLayerWithCamera(ecs, screen, layer, sys.camera)
+}
+
+// LayerRenderer returns a renderer for ecs.AddRenderer that draws the
+// particles on layer.
+func (sys *System) LayerRenderer(layer ecs.LayerID) func(*ecs.ECS, *ebiten.Image) {
+	return func(e *ecs.ECS, screen *ebiten.Image) {
+		sys.DrawLayer(e, screen, layer)
+	}
+}
+
+// AddLayerRenderers registers a LayerRenderer on each of layers, using the
+// same ECS layer IDs as render.layer. Renderers on one ECS layer run in the
+// order they were added, so add game renderers before or after accordingly.
+func (sys *System) AddLayerRenderers(e *ecs.ECS, layers ...ecs.LayerID) {
+	for _, layer := range layers {
+		e.AddRenderer(layer, sys.LayerRenderer(layer))
+	}
+}
+
+// SetRenderLayer moves a particle entity to layer, overriding its
+// render.layer, also across ApplyConfigLive. Negative layers are clamped
+// to 0.
+func SetRenderLayer(world donburi.World, entity donburi.Entity, layer ecs.LayerID) {
+	if !world.Valid(entity) {
+		return
+	}
+	entry := world.Entry(entity)
+	if !entry.HasComponent(Component) {
+		return
+	}
+	setRenderLayer(Component.Get(entry), layer)
+}
+
+func setRenderLayer(data *SystemData, layer ecs.LayerID) {
+	data.Layer = max(layer, 0)
+	data.layerOverridden = true
+}
@@ -0,0 +1,115 @@
+package chirashi
+
+import (
+	"testing"
+
+	"github.com/yohamta/donburi"
+	"github.com/yohamta/donburi/ecs"
+	"github.com/yohamta/donburi/filter"
+)
+
+func TestCollectDrawEntriesOrdersByLayerThenCreation(t *testing.T) {
+	world := donburi.NewWorld()
+	create := func(layer ecs.LayerID, seq uint64) donburi.Entity {
+		entity := world.Create(Component)
+		donburi.SetValue(world.Entry(entity), Component, SystemData{Layer: layer, drawSeq: seq})
+		return entity
+	}
+	front := create(2, 1)
+	newer := create(0, 5)
+	older := create(0, 3)
+	behind := create(1, 4)
+
+	sys := NewSystem()
+	entities := func(entries []layerDrawEntry) []donburi.Entity {
+		out := make([]donburi.Entity, len(entries))
+		for i, entry := range entries {
+			out[i] = entry.entity
+		}
+		return out
+	}
+	got := entities(sys.collectDrawEntries(world, false, 0))
+	want := []donburi.Entity{older, newer, behind, front}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("draw order got %v, want %v", got, want)
+		}
+	}
+
+	got = entities(sys.collectDrawEntries(world, true, 0))
+	if len(got) != 2 || got[0] != older || got[1] != newer {
+		t.Fatalf("layer 0 got %v, want [%v %v]", got, older, newer)
+	}
+
+	SetRenderLayer(world, older, 2)
+	got = entities(sys.collectDrawEntries(world, true, 2))
+	if len(got) != 2 || got[0] != front || got[1] != older {
+		t.Fatalf("layer 2 got %v, want [%v %v]", got, front, older)
+	}
+}
+
+func TestParticleManagerSpawnOnLayer(t *testing.T) {
+	m := NewParticleManager(nil, nil)
+	cfg := validParticleConfigForTest()
+	cfg.Render.Layer = 1
+	m.configs["layered"] = cfg
+	m.configs["parent"] = subEmitterParentConfigForTest(1)
+	m.configs["burst"] = validParticleConfigForTest()
+
+	world := donburi.NewWorld()
+	entity, err := m.SpawnLoop(world, "layered", 0, 0)
+	if err != nil {
+		t.Fatalf("SpawnLoop failed: %v", err)
+	}
+	if got := Component.Get(world.Entry(entity)).Layer; got != 1 {
+		t.Fatalf("preset layer got %d, want 1", got)
+	}
+	entity, err = m.SpawnLoopOnLayer(world, "layered", 0, 0, 3)
+	if err != nil {
+		t.Fatalf("SpawnLoopOnLayer failed: %v", err)
+	}
+	if got := Component.Get(world.Entry(entity)).Layer; got != 3 || cfg.Render.Layer != 1 {
+		t.Fatalf("spawn layer got %d (preset %d), want 3 with the preset unchanged", got, cfg.Render.Layer)
+	}
+	// Live edits follow render.layer only until the layer is overridden.
+	edited := copyConfig(cfg)
+	edited.Render.Layer = 4
+	ApplyConfigLive(world, entity, edited, 0, 0)
+	if got := Component.Get(world.Entry(entity)).Layer; got != 3 {
+		t.Fatalf("live edit moved the overridden layer to %d, want 3", got)
+	}
+	plain, err := m.SpawnLoop(world, "layered", 0, 0)
+	if err != nil {
+		t.Fatalf("SpawnLoop failed: %v", err)
+	}
+	ApplyConfigLive(world, plain, edited, 0, 0)
+	if got := Component.Get(world.Entry(plain)).Layer; got != 4 {
+		t.Fatalf("live edit layer got %d, want 4", got)
+	}
+	SetRenderLayer(world, plain, 0)
+	ApplyConfigLive(world, plain, edited, 0, 0)
+	if got := Component.Get(world.Entry(plain)).Layer; got != 0 {
+		t.Fatalf("live edit undid SetRenderLayer, got layer %d", got)
+	}
+	world.Remove(entity)
+	world.Remove(plain)
+
+	// Sub-emitter children draw on their parent's layer.
+	world = donburi.NewWorld()
+	if err := m.SpawnOneShotOnLayer(world, "parent", 100, 50, 1, 2); err != nil {
+		t.Fatalf("SpawnOneShotOnLayer failed: %v", err)
+	}
+	var parent donburi.Entity
+	donburi.NewQuery(filter.Contains(Component)).Each(world, func(e *donburi.Entry) { parent = e.Entity() })
+	runUntilRemoved(t, ecs.NewECS(world), NewSystem(), parent)
+	children := 0
+	donburi.NewQuery(filter.Contains(Component)).Each(world, func(e *donburi.Entry) {
+		children++
+		if got := Component.Get(e).Layer; got != 2 {
+			t.Fatalf("child layer got %d, want the parent's layer 2", got)
+		}
+	})
+	if children != 1 {
+		t.Fatalf("expected 1 child effect, got %d", children)
+	}
+}
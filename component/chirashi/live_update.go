@@ -1,6 +1,9 @@
 package chirashi
 
-import "github.com/yohamta/donburi"
+import (
+	"github.com/yohamta/donburi"
+	"github.com/yohamta/donburi/ecs"
+)
 
 // ApplyConfigLive updates an existing particle entity in place from config values.
 // This preserves active particles where possible and updates spawn parameters for future particles.
@@ -14,27 +17,50 @@ func ApplyConfigLive(world donburi.World, entity donburi.Entity, config *Particl
 	entry := world.Entry(entity)
 	data := Component.Get(entry)
 
-	prevEmitterX := data.EmitterX
-	prevEmitterY := data.EmitterY
-	data.EmitterX = x + config.Emitter.X
-	data.EmitterY = y + config.Emitter.Y
+	// Work on the emitter origin so motion offsets do not shift local-space
+	// particles.
+	prevEmitterX := data.EmitterX - data.motionOffsetX
+	prevEmitterY := data.EmitterY - data.motionOffsetY
+	data.EmitterX = x + config.Emitter.X + data.motionOffsetX
+	data.EmitterY = y + config.Emitter.Y + data.motionOffsetY
+	setEmitterMotion(data, buildEmitterMotionParams(config.Emitter.Motion))
+	originX := data.EmitterX - data.motionOffsetX
+	originY := data.EmitterY - data.motionOffsetY
 	data.EmitterLocalSpace = config.Emitter.Space != EmitterSpaceWorld
 	data.EmitterShape = buildEmitterShapeParams(config.Emitter.Shape)
+	setSpriteParams(data, buildEmitterSpriteParams(config.Emitter.Sprite))
 	data.SpawnInterval = config.Spawn.Interval
 	data.ParticlesPerSpawn = config.Spawn.ParticlesPerSpawn
+	data.SpawnRate = config.Spawn.Rate
+	data.SpawnRateOverDistance = config.Spawn.RateOverDistance
+	if !data.rotationOverridden {
+		data.EmitterRotation = config.Spawn.Rotation
+	}
+	if !data.scaleOverridden {
+		data.EmitterScale = config.Spawn.Scale
+	}
+	// A config edit can move the emitter; do not emit along that jump.
+	data.emitterPathValid = false
+	setBursts(data, buildBurstParams(config.Spawn.Bursts))
 	data.Blend = ParseBlendMode(config.Blend)
+	data.Sort = parseDrawSort(config.Render.Sort)
+	if !data.layerOverridden {
+		data.Layer = ecs.LayerID(config.Render.Layer)
+	}
+	data.SubEmitters = buildSubEmitterParams(config.SubEmitters)
+	applyCollisionConfig(data, config.Collision, originX, originY)
 	data.IsLoop = config.Spawn.IsLoop
 	if !data.IsLoop {
 		data.LifeTime = config.Spawn.LifeTime
 	}
 
-	hadColorVariation := data.AnimParams.Color.HasVariation
+	hadColorRoll := data.AnimParams.Color.rollsPerParticle()
 	data.AnimParams = buildAnimationParams(config)
 	buildSequenceConfigs(config, data)
 
-	shiftActiveParticlesForEmitterDelta(data, data.EmitterX-prevEmitterX, data.EmitterY-prevEmitterY)
-	applyTrailConfigLive(data, config.Trail, data.EmitterX-prevEmitterX, data.EmitterY-prevEmitterY)
-	applyAnimationParamsToActiveParticles(data, hadColorVariation)
+	shiftActiveParticlesForEmitterDelta(data, originX-prevEmitterX, originY-prevEmitterY)
+	applyTrailConfigLive(data, config.Trail, originX-prevEmitterX, originY-prevEmitterY)
+	applyAnimationParamsToActiveParticles(data, hadColorRoll)
 }
 
 func shiftActiveParticlesForEmitterDelta(data *SystemData, dx, dy float32) {
@@ -51,10 +77,12 @@ func shiftActiveParticlesForEmitterDelta(data *SystemData, dx, dy float32) {
 		p.ControlY += dy
 		p.CurrentX += dx
 		p.CurrentY += dy
+		p.PosX += dx
+		p.PosY += dy
 	}
 }
 
-func applyAnimationParamsToActiveParticles(data *SystemData, hadColorVariation bool) {
+func applyAnimationParamsToActiveParticles(data *SystemData, hadColorRoll bool) {
 	pos := data.AnimParams.Position
 	app := data.AnimParams.Appearance
 	clr := data.AnimParams.Color
@@ -66,7 +94,7 @@ func applyAnimationParamsToActiveParticles(data *SystemData, hadColorVariation b
 		applyLiveEasing(p, pos, app, clr)
 		applyLiveAppearance(data, p, app)
 		applyLivePositionSequences(data, p)
-		applyLiveColor(p, clr, hadColorVariation)
+		applyLiveColor(data, p, clr, hadColorRoll)
 		applyLiveFlow(p, pos)
 	}
 }
@@ -91,6 +119,8 @@ func applyLiveEasing(p *Instance, pos PositionParams, app AppearanceParams, clr
 	p.PositionEasing = pos.Easing
 	p.AlphaEasing = app.AlphaEasing
 	p.ScaleEasing = app.ScaleEasing
+	p.ScaleXEasing = app.ScaleXEasing
+	p.ScaleYEasing = app.ScaleYEasing
 	p.RotationEasing = app.RotationEasing
 	p.ColorEasing = clr.Easing
 }
@@ -102,7 +132,7 @@ func applyLiveAppearance(data *SystemData, p *Instance, app AppearanceParams) {
 		p.EndAlpha = app.EndAlpha
 	} else {
 		p.HasAlphaSeq = true
-		FillSnapshot(data.AlphaSeq, &p.AlphaSnap, 0)
+		fillSnapshot(data.AlphaSeq, &p.AlphaSnap, 0, data.rng)
 	}
 
 	if data.ScaleSeq == nil {
@@ -111,7 +141,25 @@ func applyLiveAppearance(data *SystemData, p *Instance, app AppearanceParams) {
 		p.EndScale = app.EndScale
 	} else {
 		p.HasScaleSeq = true
-		FillSnapshot(data.ScaleSeq, &p.ScaleSnap, 0)
+		fillSnapshot(data.ScaleSeq, &p.ScaleSnap, 0, data.rng)
+	}
+
+	if data.ScaleXSeq == nil {
+		p.HasScaleXSeq = false
+		p.StartScaleX = app.StartScaleX
+		p.EndScaleX = app.EndScaleX
+	} else {
+		p.HasScaleXSeq = true
+		fillSnapshot(data.ScaleXSeq, &p.ScaleXSnap, 0, data.rng)
+	}
+
+	if data.ScaleYSeq == nil {
+		p.HasScaleYSeq = false
+		p.StartScaleY = app.StartScaleY
+		p.EndScaleY = app.EndScaleY
+	} else {
+		p.HasScaleYSeq = true
+		fillSnapshot(data.ScaleYSeq, &p.ScaleYSnap, 0, data.rng)
 	}
 
 	if data.RotSeq == nil {
@@ -120,31 +168,31 @@ func applyLiveAppearance(data *SystemData, p *Instance, app AppearanceParams) {
 		p.EndRotation = app.EndRotation
 	} else {
 		p.HasRotSeq = true
-		FillSnapshot(data.RotSeq, &p.RotSnap, 0)
+		fillSnapshot(data.RotSeq, &p.RotSnap, 0, data.rng)
 	}
 }
 
 func applyLivePositionSequences(data *SystemData, p *Instance) {
 	if data.PosXSeq != nil {
 		p.HasPosXSeq = true
-		FillSnapshot(data.PosXSeq, &p.PosXSnap, p.StartX)
+		fillSnapshot(data.PosXSeq, &p.PosXSnap, p.StartX, data.rng)
 	} else {
 		p.HasPosXSeq = false
 	}
 	if data.PosYSeq != nil {
 		p.HasPosYSeq = true
-		FillSnapshot(data.PosYSeq, &p.PosYSnap, p.StartY)
+		fillSnapshot(data.PosYSeq, &p.PosYSnap, p.StartY, data.rng)
 	} else {
 		p.HasPosYSeq = false
 	}
 }
 
-func applyLiveColor(p *Instance, clr ColorParams, hadColorVariation bool) {
-	if clr.HasVariation && !hadColorVariation {
-		assignParticleColor(p, &clr)
+func applyLiveColor(data *SystemData, p *Instance, clr ColorParams, hadColorRoll bool) {
+	if clr.rollsPerParticle() && !hadColorRoll {
+		assignParticleColor(p, &clr, data.rng)
 		return
 	}
-	if !clr.HasVariation {
+	if !clr.rollsPerParticle() {
 		p.ColorVariationMix = 0
 	}
 	applyParticleColor(p, &clr)
@@ -3,6 +3,7 @@ package chirashi
 import (
 	"fmt"
 	"path/filepath"
+	"strings"
 	"sync"
 
 	"gopkg.in/yaml.v3"
@@ -131,6 +132,14 @@ func (l *ConfigLoader) validateConfig(config *ParticleConfig) error {
 	default:
 		return fmt.Errorf("render.particle_shader must be default or blur")
 	}
+	switch config.Render.Sort {
+	case "", "none", "oldest_first", "youngest_first", "by_y", "by_scale":
+	default:
+		return fmt.Errorf("render.sort must be none, oldest_first, youngest_first, by_y, or by_scale")
+	}
+	if config.Render.Layer < 0 {
+		return fmt.Errorf("render.layer must be greater than or equal to 0")
+	}
 	if config.Render.GlitchIntensity < 0 || config.Render.GlitchIntensity > 1 {
 		return fmt.Errorf("render.glitch_intensity must be within [0,1]")
 	}
@@ -155,12 +164,47 @@ func (l *ConfigLoader) validateConfig(config *ParticleConfig) error {
 		return fmt.Errorf("max_particles must be greater than 0")
 	}
 
-	if config.Spawn.ParticlesPerSpawn <= 0 {
-		return fmt.Errorf("particles_per_spawn must be greater than 0")
+	if config.Spawn.Rate < 0 {
+		return fmt.Errorf("spawn.rate must be greater than or equal to 0")
+	}
+
+	if config.Spawn.RateOverDistance < 0 {
+		return fmt.Errorf("spawn.rate_over_distance must be greater than or equal to 0")
+	}
+
+	if config.Spawn.Scale < 0 {
+		return fmt.Errorf("spawn.scale must be greater than or equal to 0")
+	}
+
+	for i, burst := range config.Spawn.Bursts {
+		if burst.Time < 0 {
+			return fmt.Errorf("spawn.bursts[%d].time must be greater than or equal to 0", i)
+		}
+		if burst.CountRange != nil {
+			if burst.CountRange.Min < 0 || burst.CountRange.Min > burst.CountRange.Max {
+				return fmt.Errorf("spawn.bursts[%d].count_range must satisfy 0 <= min <= max", i)
+			}
+		} else if burst.Count <= 0 {
+			return fmt.Errorf("spawn.bursts[%d].count must be greater than 0", i)
+		}
+		if burst.Cycles < 0 {
+			return fmt.Errorf("spawn.bursts[%d].cycles must be greater than or equal to 0", i)
+		}
+		if burst.Cycles > 1 && burst.Interval <= 0 {
+			return fmt.Errorf("spawn.bursts[%d].interval must be greater than 0 when cycles > 1", i)
+		}
 	}
 
-	if config.Spawn.Interval <= 0 {
-		return fmt.Errorf("interval must be greater than 0")
+	// Interval emission is only required when no other emission (rate,
+	// rate over distance or bursts) is set.
+	if config.Spawn.Rate == 0 && config.Spawn.RateOverDistance == 0 && len(config.Spawn.Bursts) == 0 {
+		if config.Spawn.ParticlesPerSpawn <= 0 {
+			return fmt.Errorf("particles_per_spawn must be greater than 0")
+		}
+
+		if config.Spawn.Interval <= 0 {
+			return fmt.Errorf("interval must be greater than 0")
+		}
 	}
 
 	dur := config.Animation.Duration
@@ -179,9 +223,9 @@ func (l *ConfigLoader) validateConfig(config *ParticleConfig) error {
 	}
 	if vector := config.Emitter.Vector; vector != nil {
 		switch vector.Type {
-		case "rect", "polyline":
+		case "rect", "polyline", "text":
 		default:
-			return fmt.Errorf("emitter.vector.type must be rect or polyline")
+			return fmt.Errorf("emitter.vector.type must be rect, polyline, or text")
 		}
 		switch vector.Placement {
 		case "", "fill", "surface":
@@ -200,31 +244,98 @@ func (l *ConfigLoader) validateConfig(config *ParticleConfig) error {
 				return fmt.Errorf("emitter.vector.rect.height must be greater than 0")
 			}
 		case "polyline":
-			if vector.Placement != "" && vector.Placement != "surface" {
-				return fmt.Errorf("emitter.vector.placement must be surface for polyline")
-			}
-			if vector.Polyline == nil {
+			if vector.Polyline == nil && len(vector.Polylines) == 0 {
 				return fmt.Errorf("emitter.vector.polyline is required")
 			}
-			if len(vector.Polyline.Points) < 2 {
-				return fmt.Errorf("emitter.vector.polyline.points must contain at least 2 points")
+			if vector.Polyline != nil {
+				if err := validateVectorPolylineConfig("emitter.vector.polyline", vector.Polyline, vector.Placement); err != nil {
+					return err
+				}
+			}
+			for i := range vector.Polylines {
+				path := fmt.Sprintf("emitter.vector.polylines[%d]", i)
+				if err := validateVectorPolylineConfig(path, &vector.Polylines[i], vector.Placement); err != nil {
+					return err
+				}
+			}
+		case "text":
+			if vector.Text == nil {
+				return fmt.Errorf("emitter.vector.text is required")
+			}
+			if strings.TrimSpace(vector.Text.Value) == "" {
+				return fmt.Errorf("emitter.vector.text.value must not be empty")
+			}
+			if vector.Text.Size <= 0 {
+				return fmt.Errorf("emitter.vector.text.size must be greater than 0")
 			}
-			switch vector.Polyline.Interpolation {
-			case "", "linear", "quadratic":
+			switch vector.Text.Align {
+			case "", "left", "center", "right":
 			default:
-				return fmt.Errorf("emitter.vector.polyline.interpolation must be linear or quadratic")
+				return fmt.Errorf("emitter.vector.text.align must be left, center, or right")
+			}
+		}
+	}
+	if motion := config.Emitter.Motion; motion != nil {
+		switch motion.Type {
+		case "orbit":
+			if motion.Radius <= 0 {
+				return fmt.Errorf("emitter.motion.radius must be greater than 0")
+			}
+		case "sway":
+			if motion.AmplitudeX == 0 && motion.AmplitudeY == 0 {
+				return fmt.Errorf("emitter.motion.amplitude_x or amplitude_y must be non-zero")
+			}
+			if motion.Frequency <= 0 {
+				return fmt.Errorf("emitter.motion.frequency must be greater than 0")
+			}
+		case "path":
+			if motion.Path == nil {
+				return fmt.Errorf("emitter.motion.path is required")
+			}
+			if err := validatePolylineConfig("emitter.motion.path", motion.Path); err != nil {
+				return err
+			}
+			if motion.Speed <= 0 {
+				return fmt.Errorf("emitter.motion.speed must be greater than 0")
 			}
-			if vector.Polyline.CurveSteps < 0 {
-				return fmt.Errorf("emitter.vector.polyline.curve_steps must be greater than or equal to 0")
+			switch motion.Mode {
+			case "", "loop", "ping_pong":
+			default:
+				return fmt.Errorf("emitter.motion.mode must be loop or ping_pong")
 			}
-			if vector.Polyline.Interpolation == "quadratic" {
-				if len(vector.Polyline.Points) < 3 || len(vector.Polyline.Points)%2 == 0 {
-					return fmt.Errorf("emitter.vector.polyline.points must alternate anchor/control/anchor for quadratic interpolation")
+		default:
+			return fmt.Errorf("emitter.motion.type must be orbit, sway, or path")
+		}
+	}
+	if sprite := config.Emitter.Sprite; sprite != nil {
+		if sprite.BlockSize < 0 {
+			return fmt.Errorf("emitter.sprite.block_size must be greater than or equal to 0")
+		}
+		if sprite.AlphaThreshold < 0 || sprite.AlphaThreshold >= 1 {
+			return fmt.Errorf("emitter.sprite.alpha_threshold must be within [0,1)")
+		}
+		if sprite.Scale < 0 {
+			return fmt.Errorf("emitter.sprite.scale must be greater than or equal to 0")
+		}
+		switch sprite.Mode {
+		case "", "disintegrate":
+		case "assemble":
+			// Only start/end lerps can be reversed onto the block.
+			pos := config.Animation.Position
+			switch pos.Type {
+			case "", "cartesian":
+				if pos.X != nil || pos.Y != nil {
+					return fmt.Errorf("emitter.sprite.mode assemble does not support animation.position.x or y")
 				}
-				if vector.Polyline.Closed {
-					return fmt.Errorf("emitter.vector.polyline.closed is not supported for quadratic interpolation")
+			case "polar":
+				if pos.Speed != nil || pos.AngularSpeed != nil {
+					return fmt.Errorf("emitter.sprite.mode assemble does not support polar speed or angular_speed")
 				}
+			default:
+				return fmt.Errorf("emitter.sprite.mode assemble requires animation.position.type cartesian or polar")
 			}
+		default:
+			return fmt.Errorf("emitter.sprite.mode must be disintegrate or assemble")
 		}
 	}
 	switch config.Emitter.Space {
@@ -254,6 +365,62 @@ func (l *ConfigLoader) validateConfig(config *ParticleConfig) error {
 		}
 	}
 
+	if collision := config.Collision; collision != nil {
+		switch collision.Response {
+		case "", "bounce", "stick", "kill":
+		default:
+			return fmt.Errorf("collision.response must be bounce, stick, or kill")
+		}
+		if collision.Restitution < 0 {
+			return fmt.Errorf("collision.restitution must be greater than or equal to 0")
+		}
+		if collision.Friction < 0 || collision.Friction > 1 {
+			return fmt.Errorf("collision.friction must be within [0,1]")
+		}
+		for i, c := range collision.Colliders {
+			switch c.Type {
+			case "plane":
+				if c.NormalX == 0 && c.NormalY == 0 {
+					return fmt.Errorf("collision.colliders[%d] plane needs a non-zero normal_x/normal_y", i)
+				}
+			case "rect":
+				if c.Width <= 0 || c.Height <= 0 {
+					return fmt.Errorf("collision.colliders[%d] rect width and height must be greater than 0", i)
+				}
+			case "circle":
+				if c.Radius <= 0 {
+					return fmt.Errorf("collision.colliders[%d] circle radius must be greater than 0", i)
+				}
+			default:
+				return fmt.Errorf("collision.colliders[%d].type must be plane, rect, or circle", i)
+			}
+		}
+	}
+
+	for i, sub := range config.SubEmitters {
+		if sub.Preset == "" {
+			return fmt.Errorf("sub_emitters[%d].preset is required", i)
+		}
+		switch sub.Trigger {
+		case "birth", "death", "collision":
+		default:
+			return fmt.Errorf("sub_emitters[%d].trigger must be birth, death, or collision", i)
+		}
+		if sub.Probability != nil && (*sub.Probability < 0 || *sub.Probability > 1) {
+			return fmt.Errorf("sub_emitters[%d].probability must be within [0,1]", i)
+		}
+		for _, inherit := range sub.Inherit {
+			switch inherit {
+			case "position", "color", "velocity":
+			default:
+				return fmt.Errorf("sub_emitters[%d].inherit must contain only position, color, or velocity", i)
+			}
+		}
+		if sub.LifeTime < 0 {
+			return fmt.Errorf("sub_emitters[%d].life_time must be greater than or equal to 0", i)
+		}
+	}
+
 	if flow := config.Animation.Position.Flow; flow != nil {
 		switch flow.Type {
 		case "", "curl":
@@ -288,6 +455,85 @@ func (l *ConfigLoader) validateConfig(config *ParticleConfig) error {
 		}
 	}
 
+	if pos := config.Animation.Position; pos.Type == "physics" {
+		if pos.LinearDrag < 0 {
+			return fmt.Errorf("animation.position.linear_drag must be greater than or equal to 0")
+		}
+		if pos.QuadraticDrag < 0 {
+			return fmt.Errorf("animation.position.quadratic_drag must be greater than or equal to 0")
+		}
+		if pos.TerminalSpeed < 0 {
+			return fmt.Errorf("animation.position.terminal_speed must be greater than or equal to 0")
+		}
+	}
+
+	for _, prop := range []struct {
+		path   string
+		config *PropertyConfig
+	}{
+		{"animation.alpha", &config.Animation.Alpha},
+		{"animation.scale", &config.Animation.Scale},
+		{"animation.scale_x", config.Animation.ScaleX},
+		{"animation.scale_y", config.Animation.ScaleY},
+		{"animation.rotation", &config.Animation.Rotation},
+		{"animation.position.x", config.Animation.Position.X},
+		{"animation.position.y", config.Animation.Position.Y},
+	} {
+		if err := validateCurveConfig(prop.path, prop.config); err != nil {
+			return err
+		}
+	}
+
+	switch config.Animation.Align {
+	case "", "velocity":
+	default:
+		return fmt.Errorf("animation.align must be velocity or empty")
+	}
+	if config.Animation.Stretch < 0 {
+		return fmt.Errorf("animation.stretch must be greater than or equal to 0")
+	}
+	if config.Animation.Stretch > 0 && config.Animation.Align != "velocity" {
+		return fmt.Errorf("animation.stretch requires animation.align: velocity")
+	}
+
+	if err := validateColorConfig("animation.color", config.Animation.Color); err != nil {
+		return err
+	}
+	if config.Trail != nil {
+		if err := validateColorConfig("trail.color", config.Trail.Color); err != nil {
+			return err
+		}
+		if config.Trail.Color != nil && len(config.Trail.Color.Palette) > 0 {
+			return fmt.Errorf("trail.color.palette is not supported")
+		}
+	}
+
+	if frames := config.Animation.Frames; frames != nil {
+		if frames.Columns <= 0 || frames.Rows <= 0 {
+			return fmt.Errorf("animation.frames.columns and rows must be greater than 0")
+		}
+		if frames.Count < 0 || frames.Count > frames.Columns*frames.Rows {
+			return fmt.Errorf("animation.frames.count must be within [0,columns*rows]")
+		}
+		switch frames.Mode {
+		case "", "fps":
+			if frames.FPS <= 0 {
+				return fmt.Errorf("animation.frames.fps must be greater than 0 in fps mode")
+			}
+		case "lifetime":
+		default:
+			return fmt.Errorf("animation.frames.mode must be fps or lifetime")
+		}
+		if frames.Cycles < 0 {
+			return fmt.Errorf("animation.frames.cycles must be greater than or equal to 0")
+		}
+		switch frames.Loop {
+		case "", "loop", "once", "ping_pong":
+		default:
+			return fmt.Errorf("animation.frames.loop must be loop, once, or ping_pong")
+		}
+	}
+
 	if config.Emitter.Shape.Radius != nil && config.Emitter.Shape.Radius.Min > config.Emitter.Shape.Radius.Max {
 		return fmt.Errorf("emitter.shape.radius.min must be less than or equal to max")
 	}
@@ -298,3 +544,85 @@ func (l *ConfigLoader) validateConfig(config *ParticleConfig) error {
 
 	return nil
 }
+
+func validateCurveConfig(path string, prop *PropertyConfig) error {
+	if prop == nil || prop.Type != "curve" {
+		return nil
+	}
+	if len(prop.Keys) == 0 {
+		return fmt.Errorf("%s.keys must not be empty in curve mode", path)
+	}
+	for i, key := range prop.Keys {
+		if key.T < 0 || key.T > 1 {
+			return fmt.Errorf("%s.keys[%d].t must be within [0,1]", path, i)
+		}
+	}
+	return nil
+}
+
+func validateColorConfig(path string, color *ColorConfig) error {
+	if color == nil {
+		return nil
+	}
+	switch color.Space {
+	case "", "rgb", "hsv", "oklab":
+	default:
+		return fmt.Errorf("%s.space must be rgb, hsv, or oklab", path)
+	}
+	if len(color.Gradient) > 0 && len(color.Palette) > 0 {
+		return fmt.Errorf("%s.gradient and %s.palette cannot both be set", path, path)
+	}
+	for i, stop := range color.Gradient {
+		if stop.T < 0 || stop.T > 1 {
+			return fmt.Errorf("%s.gradient[%d].t must be within [0,1]", path, i)
+		}
+		if stop.A != nil && (*stop.A < 0 || *stop.A > 1) {
+			return fmt.Errorf("%s.gradient[%d].a must be within [0,1]", path, i)
+		}
+	}
+	return nil
+}
+
+// validateVectorPolylineConfig checks a polyline used for vector placement,
+// including its holes; path is its YAML path.
+func validateVectorPolylineConfig(path string, polyline *EmitterVectorPolylineConfig, placement string) error {
+	if placement == "fill" && !polyline.Closed {
+		return fmt.Errorf("emitter.vector.placement must be surface for polyline unless %s.closed is true", path)
+	}
+	if err := validatePolylineConfig(path, polyline); err != nil {
+		return err
+	}
+	if len(polyline.Holes) > 0 && !polyline.Closed {
+		return fmt.Errorf("%s.holes require %s.closed: true", path, path)
+	}
+	for i, hole := range polyline.Holes {
+		if len(hole) < 3 {
+			return fmt.Errorf("%s.holes[%d] must contain at least 3 points", path, i)
+		}
+	}
+	return nil
+}
+
+// validatePolylineConfig checks a polyline block; path is its YAML path.
+func validatePolylineConfig(path string, polyline *EmitterVectorPolylineConfig) error {
+	if len(polyline.Points) < 2 {
+		return fmt.Errorf("%s.points must contain at least 2 points", path)
+	}
+	switch polyline.Interpolation {
+	case "", "linear", "quadratic":
+	default:
+		return fmt.Errorf("%s.interpolation must be linear or quadratic", path)
+	}
+	if polyline.CurveSteps < 0 {
+		return fmt.Errorf("%s.curve_steps must be greater than or equal to 0", path)
+	}
+	if polyline.Interpolation == "quadratic" {
+		if len(polyline.Points) < 3 || len(polyline.Points)%2 == 0 {
+			return fmt.Errorf("%s.points must alternate anchor/control/anchor for quadratic interpolation", path)
+		}
+		if polyline.Closed {
+			return fmt.Errorf("%s.closed is not supported for quadratic interpolation", path)
+		}
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package chirashi
 
 import (
+	"math"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -49,6 +50,17 @@ func TestValidateConfigAcceptsDurationRangeWithoutValue(t *testing.T) {
 	}
 }
 
+func TestValidateConfigAcceptsRateWithoutInterval(t *testing.T) {
+	cfg := validParticleConfigForTest()
+	cfg.Spawn.Interval = 0
+	cfg.Spawn.ParticlesPerSpawn = 0
+	cfg.Spawn.Rate = 120
+
+	if err := NewConfigLoader().validateConfig(cfg); err != nil {
+		t.Fatalf("expected rate-only spawn to be valid, got: %v", err)
+	}
+}
+
 func TestValidateConfigRejectsInvalidValues(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -125,6 +137,13 @@ func TestValidateConfigRejectsInvalidValues(t *testing.T) {
 			},
 			wantErr: "interval",
 		},
+		{
+			name: "negative spawn rate",
+			mutate: func(c *ParticleConfig) {
+				c.Spawn.Rate = -1
+			},
+			wantErr: "spawn.rate",
+		},
 		{
 			name: "invalid duration",
 			mutate: func(c *ParticleConfig) {
@@ -174,6 +193,62 @@ func TestValidateConfigRejectsInvalidValues(t *testing.T) {
 			},
 			wantErr: "emitter.vector.placement must be surface for polyline",
 		},
+		{
+			name: "polyline holes on an open polyline",
+			mutate: func(c *ParticleConfig) {
+				c.Emitter.Vector = &EmitterVectorConfig{
+					Type: "polyline",
+					Polyline: &EmitterVectorPolylineConfig{
+						Points: []EmitterVectorPoint{{X: -10, Y: -10}, {X: 10, Y: -10}, {X: 0, Y: 10}},
+						Holes:  [][]EmitterVectorPoint{{{X: -1, Y: -1}, {X: 1, Y: -1}, {X: 0, Y: 1}}},
+					},
+				}
+			},
+			wantErr: "emitter.vector.polyline.holes require emitter.vector.polyline.closed",
+		},
+		{
+			name: "text vector without text block",
+			mutate: func(c *ParticleConfig) {
+				c.Emitter.Vector = &EmitterVectorConfig{Type: "text"}
+			},
+			wantErr: "emitter.vector.text is required",
+		},
+		{
+			name: "text vector with blank value",
+			mutate: func(c *ParticleConfig) {
+				c.Emitter.Vector = &EmitterVectorConfig{Type: "text", Text: &EmitterVectorTextConfig{Value: " ", Size: 32}}
+			},
+			wantErr: "emitter.vector.text.value must not be empty",
+		},
+		{
+			name: "text vector with zero size",
+			mutate: func(c *ParticleConfig) {
+				c.Emitter.Vector = &EmitterVectorConfig{Type: "text", Text: &EmitterVectorTextConfig{Value: "x5"}}
+			},
+			wantErr: "emitter.vector.text.size must be greater than 0",
+		},
+		{
+			name: "text vector with unknown align",
+			mutate: func(c *ParticleConfig) {
+				c.Emitter.Vector = &EmitterVectorConfig{Type: "text", Text: &EmitterVectorTextConfig{Value: "x5", Size: 32, Align: "justify"}}
+			},
+			wantErr: "emitter.vector.text.align must be left, center, or right",
+		},
+		{
+			name: "polyline hole with two points",
+			mutate: func(c *ParticleConfig) {
+				c.Emitter.Vector = &EmitterVectorConfig{
+					Type:      "polyline",
+					Placement: "fill",
+					Polyline: &EmitterVectorPolylineConfig{
+						Closed: true,
+						Points: []EmitterVectorPoint{{X: -10, Y: -10}, {X: 10, Y: -10}, {X: 0, Y: 10}},
+						Holes:  [][]EmitterVectorPoint{{{X: -1, Y: -1}, {X: 1, Y: -1}}},
+					},
+				}
+			},
+			wantErr: "emitter.vector.polyline.holes[0]",
+		},
 		{
 			name: "invalid emitter vector placement",
 			mutate: func(c *ParticleConfig) {
@@ -281,6 +356,252 @@ func TestValidateConfigRejectsInvalidValues(t *testing.T) {
 			},
 			wantErr: "trail.max_points",
 		},
+		{
+			name: "missing sub-emitter preset",
+			mutate: func(c *ParticleConfig) {
+				c.SubEmitters = []SubEmitterConfig{{Trigger: "death"}}
+			},
+			wantErr: "sub_emitters[0].preset",
+		},
+		{
+			name: "invalid sub-emitter trigger",
+			mutate: func(c *ParticleConfig) {
+				c.SubEmitters = []SubEmitterConfig{{Preset: "spark", Trigger: "spawn"}}
+			},
+			wantErr: "sub_emitters[0].trigger",
+		},
+		{
+			name: "invalid sub-emitter probability",
+			mutate: func(c *ParticleConfig) {
+				p := float32(1.5)
+				c.SubEmitters = []SubEmitterConfig{{Preset: "spark", Trigger: "death", Probability: &p}}
+			},
+			wantErr: "sub_emitters[0].probability",
+		},
+		{
+			name: "invalid sub-emitter inherit",
+			mutate: func(c *ParticleConfig) {
+				c.SubEmitters = []SubEmitterConfig{{Preset: "spark", Trigger: "death", Inherit: []string{"scale"}}}
+			},
+			wantErr: "sub_emitters[0].inherit",
+		},
+		{
+			name: "negative physics drag",
+			mutate: func(c *ParticleConfig) {
+				c.Animation.Position.Type = "physics"
+				c.Animation.Position.LinearDrag = -1
+			},
+			wantErr: "animation.position.linear_drag",
+		},
+		{
+			name: "unknown color space",
+			mutate: func(c *ParticleConfig) {
+				c.Animation.Color = &ColorConfig{Space: "lch"}
+			},
+			wantErr: "animation.color.space",
+		},
+		{
+			name: "gradient stop out of range",
+			mutate: func(c *ParticleConfig) {
+				c.Animation.Color = &ColorConfig{Gradient: []ColorStopConfig{{T: 1.5}}}
+			},
+			wantErr: "animation.color.gradient[0].t",
+		},
+		{
+			name: "trail palette",
+			mutate: func(c *ParticleConfig) {
+				c.Trail = &TrailConfig{Enabled: true, Color: &ColorConfig{Palette: []PaletteColorConfig{{R: 1}}}}
+			},
+			wantErr: "trail.color.palette",
+		},
+		{
+			name: "negative rate over distance",
+			mutate: func(c *ParticleConfig) {
+				c.Spawn.RateOverDistance = -1
+			},
+			wantErr: "spawn.rate_over_distance",
+		},
+		{
+			name: "unknown render sort",
+			mutate: func(c *ParticleConfig) {
+				c.Render.Sort = "by_depth"
+			},
+			wantErr: "render.sort",
+		},
+		{
+			name: "negative render layer",
+			mutate: func(c *ParticleConfig) {
+				c.Render.Layer = -1
+			},
+			wantErr: "render.layer",
+		},
+		{
+			name: "negative emitter scale",
+			mutate: func(c *ParticleConfig) {
+				c.Spawn.Scale = -1
+			},
+			wantErr: "spawn.scale",
+		},
+		{
+			name: "unknown emitter motion type",
+			mutate: func(c *ParticleConfig) {
+				c.Emitter.Motion = &EmitterMotionConfig{Type: "spiral"}
+			},
+			wantErr: "emitter.motion.type",
+		},
+		{
+			name: "orbit motion without radius",
+			mutate: func(c *ParticleConfig) {
+				c.Emitter.Motion = &EmitterMotionConfig{Type: "orbit", AngularSpeed: 1}
+			},
+			wantErr: "emitter.motion.radius",
+		},
+		{
+			name: "sway motion without frequency",
+			mutate: func(c *ParticleConfig) {
+				c.Emitter.Motion = &EmitterMotionConfig{Type: "sway", AmplitudeX: 10}
+			},
+			wantErr: "emitter.motion.frequency",
+		},
+		{
+			name: "path motion with one point",
+			mutate: func(c *ParticleConfig) {
+				c.Emitter.Motion = &EmitterMotionConfig{
+					Type:  "path",
+					Speed: 100,
+					Path:  &EmitterVectorPolylineConfig{Points: []EmitterVectorPoint{{X: 0, Y: 0}}},
+				}
+			},
+			wantErr: "emitter.motion.path.points",
+		},
+		{
+			name: "path motion without speed",
+			mutate: func(c *ParticleConfig) {
+				c.Emitter.Motion = &EmitterMotionConfig{
+					Type: "path",
+					Path: &EmitterVectorPolylineConfig{Points: []EmitterVectorPoint{{X: 0, Y: 0}, {X: 10, Y: 0}}},
+				}
+			},
+			wantErr: "emitter.motion.speed",
+		},
+		{
+			name: "unknown path motion mode",
+			mutate: func(c *ParticleConfig) {
+				c.Emitter.Motion = &EmitterMotionConfig{
+					Type:  "path",
+					Speed: 100,
+					Mode:  "bounce",
+					Path:  &EmitterVectorPolylineConfig{Points: []EmitterVectorPoint{{X: 0, Y: 0}, {X: 10, Y: 0}}},
+				}
+			},
+			wantErr: "emitter.motion.mode",
+		},
+		{
+			name: "sprite alpha threshold of 1",
+			mutate: func(c *ParticleConfig) {
+				c.Emitter.Sprite = &EmitterSpriteConfig{AlphaThreshold: 1}
+			},
+			wantErr: "emitter.sprite.alpha_threshold",
+		},
+		{
+			name: "unknown sprite mode",
+			mutate: func(c *ParticleConfig) {
+				c.Emitter.Sprite = &EmitterSpriteConfig{Mode: "explode"}
+			},
+			wantErr: "emitter.sprite.mode",
+		},
+		{
+			name: "assembling sprite with physics",
+			mutate: func(c *ParticleConfig) {
+				c.Emitter.Sprite = &EmitterSpriteConfig{Mode: "assemble"}
+				c.Animation.Position.Type = "physics"
+			},
+			wantErr: "emitter.sprite.mode assemble requires",
+		},
+		{
+			name: "burst without count",
+			mutate: func(c *ParticleConfig) {
+				c.Spawn.Bursts = []BurstConfig{{Time: 0.1}}
+			},
+			wantErr: "spawn.bursts[0].count",
+		},
+		{
+			name: "repeating burst without interval",
+			mutate: func(c *ParticleConfig) {
+				c.Spawn.Bursts = []BurstConfig{{Count: 5, Cycles: 3}}
+			},
+			wantErr: "spawn.bursts[0].interval",
+		},
+		{
+			name: "unknown align mode",
+			mutate: func(c *ParticleConfig) {
+				c.Animation.Align = "target"
+			},
+			wantErr: "animation.align",
+		},
+		{
+			name: "stretch without velocity alignment",
+			mutate: func(c *ParticleConfig) {
+				c.Animation.Stretch = 0.02
+			},
+			wantErr: "animation.stretch requires",
+		},
+		{
+			name: "curve without keys",
+			mutate: func(c *ParticleConfig) {
+				c.Animation.Scale = PropertyConfig{Type: "curve"}
+			},
+			wantErr: "animation.scale.keys",
+		},
+		{
+			name: "curve key outside lifetime",
+			mutate: func(c *ParticleConfig) {
+				c.Animation.Position.Y = &PropertyConfig{Type: "curve", Keys: []CurveKeyConfig{{T: 0}, {T: 1.5}}}
+			},
+			wantErr: "animation.position.y.keys[1].t",
+		},
+		{
+			name: "flipbook without fps",
+			mutate: func(c *ParticleConfig) {
+				c.Animation.Frames = &FramesConfig{Columns: 4, Rows: 4}
+			},
+			wantErr: "animation.frames.fps",
+		},
+		{
+			name: "flipbook count larger than sheet",
+			mutate: func(c *ParticleConfig) {
+				c.Animation.Frames = &FramesConfig{Columns: 2, Rows: 2, Count: 5, Mode: "lifetime"}
+			},
+			wantErr: "animation.frames.count",
+		},
+		{
+			name: "invalid collision response",
+			mutate: func(c *ParticleConfig) {
+				c.Collision = &CollisionConfig{Response: "explode"}
+			},
+			wantErr: "collision.response",
+		},
+		{
+			name: "invalid collision friction",
+			mutate: func(c *ParticleConfig) {
+				c.Collision = &CollisionConfig{Friction: 1.5}
+			},
+			wantErr: "collision.friction",
+		},
+		{
+			name: "plane collider without normal",
+			mutate: func(c *ParticleConfig) {
+				c.Collision = &CollisionConfig{Colliders: []ColliderConfig{{Type: "plane"}}}
+			},
+			wantErr: "collision.colliders[0] plane",
+		},
+		{
+			name: "unknown collider type",
+			mutate: func(c *ParticleConfig) {
+				c.Collision = &CollisionConfig{Colliders: []ColliderConfig{{Type: "grid"}}}
+			},
+			wantErr: "collision.colliders[0].type",
+		},
 	}
 
 	loader := NewConfigLoader()
@@ -352,6 +673,194 @@ func TestEmptyRenderConfigIsOmittedFromYAML(t *testing.T) {
 	}
 }
 
+func TestLoadRainSplashSample(t *testing.T) {
+	loader := NewConfigLoader()
+	path := filepath.Join("..", "..", "assets", "particles", "rain_splash.yaml")
+
+	cfg, err := loader.LoadConfig(path)
+	if err != nil {
+		t.Fatalf("expected rain_splash sample to load, got: %v", err)
+	}
+	if cfg.Collision == nil || len(cfg.Collision.Colliders) != 1 || cfg.Collision.Colliders[0].Type != "plane" {
+		t.Fatalf("expected rain_splash to define a ground plane, got: %+v", cfg.Collision)
+	}
+}
+
+func TestLoadCampfireGradientSample(t *testing.T) {
+	loader := NewConfigLoader()
+	path := filepath.Join("..", "..", "assets", "particles", "campfire_gradient.yaml")
+
+	cfg, err := loader.LoadConfig(path)
+	if err != nil {
+		t.Fatalf("expected campfire_gradient sample to load, got: %v", err)
+	}
+	if cfg.Animation.Color == nil || cfg.Animation.Color.Space != "oklab" || len(cfg.Animation.Color.Gradient) != 4 {
+		t.Fatalf("expected campfire_gradient to define a 4-stop OKLab gradient, got: %+v", cfg.Animation.Color)
+	}
+}
+
+func TestLoadPopSettleFadeSample(t *testing.T) {
+	loader := NewConfigLoader()
+	path := filepath.Join("..", "..", "assets", "particles", "pop_settle_fade.yaml")
+
+	cfg, err := loader.LoadConfig(path)
+	if err != nil {
+		t.Fatalf("expected pop_settle_fade sample to load, got: %v", err)
+	}
+	if !cfg.Animation.Scale.IsCurve() || !cfg.Animation.Alpha.IsCurve() {
+		t.Fatalf("expected pop_settle_fade to use scale and alpha curves, got scale=%+v alpha=%+v", cfg.Animation.Scale, cfg.Animation.Alpha)
+	}
+	if cfg.Animation.Position.Y == nil || !cfg.Animation.Position.Y.IsCurve() {
+		t.Fatalf("expected pop_settle_fade to use a position.y curve, got: %+v", cfg.Animation.Position.Y)
+	}
+}
+
+func TestLoadSparkStreaksSample(t *testing.T) {
+	loader := NewConfigLoader()
+	path := filepath.Join("..", "..", "assets", "particles", "spark_streaks.yaml")
+
+	cfg, err := loader.LoadConfig(path)
+	if err != nil {
+		t.Fatalf("expected spark_streaks sample to load, got: %v", err)
+	}
+	if cfg.Animation.Align != "velocity" || cfg.Animation.Stretch <= 0 {
+		t.Fatalf("expected spark_streaks to use stretched velocity alignment, got align=%q stretch=%v", cfg.Animation.Align, cfg.Animation.Stretch)
+	}
+}
+
+func TestLoadDropletSquashSample(t *testing.T) {
+	loader := NewConfigLoader()
+	path := filepath.Join("..", "..", "assets", "particles", "droplet_squash.yaml")
+
+	cfg, err := loader.LoadConfig(path)
+	if err != nil {
+		t.Fatalf("expected droplet_squash sample to load, got: %v", err)
+	}
+	if cfg.Animation.ScaleX == nil || !cfg.Animation.ScaleX.IsSequence() || cfg.Animation.ScaleY == nil || !cfg.Animation.ScaleY.IsSequence() {
+		t.Fatalf("expected droplet_squash to use scale_x and scale_y sequences, got x=%+v y=%+v", cfg.Animation.ScaleX, cfg.Animation.ScaleY)
+	}
+}
+
+func TestLoadGrenadeBurstsSample(t *testing.T) {
+	loader := NewConfigLoader()
+	path := filepath.Join("..", "..", "assets", "particles", "grenade_bursts.yaml")
+
+	cfg, err := loader.LoadConfig(path)
+	if err != nil {
+		t.Fatalf("expected grenade_bursts sample to load, got: %v", err)
+	}
+	if len(cfg.Spawn.Bursts) != 3 || cfg.Spawn.Bursts[1].CountRange == nil || cfg.Spawn.Bursts[2].Cycles != 3 {
+		t.Fatalf("expected grenade_bursts to schedule 3 bursts, got: %+v", cfg.Spawn.Bursts)
+	}
+}
+
+func TestLoadRocketExhaustSample(t *testing.T) {
+	loader := NewConfigLoader()
+	path := filepath.Join("..", "..", "assets", "particles", "rocket_exhaust.yaml")
+
+	cfg, err := loader.LoadConfig(path)
+	if err != nil {
+		t.Fatalf("expected rocket_exhaust sample to load, got: %v", err)
+	}
+	if cfg.Spawn.RateOverDistance <= 0 || cfg.Emitter.Space != EmitterSpaceWorld {
+		t.Fatalf("expected rocket_exhaust to emit over distance in world space, got rate_over_distance=%v space=%v", cfg.Spawn.RateOverDistance, cfg.Emitter.Space)
+	}
+}
+
+func TestLoadOrbitingWispSample(t *testing.T) {
+	loader := NewConfigLoader()
+	path := filepath.Join("..", "..", "assets", "particles", "orbiting_wisp.yaml")
+
+	cfg, err := loader.LoadConfig(path)
+	if err != nil {
+		t.Fatalf("expected orbiting_wisp sample to load, got: %v", err)
+	}
+	if cfg.Emitter.Motion == nil || cfg.Emitter.Motion.Type != "orbit" {
+		t.Fatalf("expected orbiting_wisp to use orbit motion, got: %+v", cfg.Emitter.Motion)
+	}
+	if cfg.Trail == nil || cfg.Trail.Mode != "emitter" {
+		t.Fatalf("expected orbiting_wisp to draw an emitter trail, got: %+v", cfg.Trail)
+	}
+}
+
+func TestLoadFrameShatterFillSample(t *testing.T) {
+	loader := NewConfigLoader()
+	path := filepath.Join("..", "..", "assets", "particles", "frame_shatter_fill.yaml")
+
+	cfg, err := loader.LoadConfig(path)
+	if err != nil {
+		t.Fatalf("expected frame_shatter_fill sample to load, got: %v", err)
+	}
+	params := buildEmitterVectorParams(cfg.Emitter.Vector)
+	// 200x120 frame plus a 40x16 notch, minus the 140x64 window.
+	if want := float32(200*120 + 40*16/2 - 140*64); math.Abs(float64(params.Polyline.TotalArea-want)) > 1 {
+		t.Fatalf("expected frame_shatter_fill fill area %v, got %v", want, params.Polyline.TotalArea)
+	}
+}
+
+func TestLoadSVGHeartFillSample(t *testing.T) {
+	loader := NewConfigLoader()
+	path := filepath.Join("..", "..", "assets", "particles", "svg_heart_fill.yaml")
+
+	cfg, err := loader.LoadConfig(path)
+	if err != nil {
+		t.Fatalf("expected svg_heart_fill sample to load, got: %v", err)
+	}
+	imported, err := NewSVGPathVector("M0 -10C-5 -25-30 -20-25 0S-5 20 0 30C5 20 20 15 25 0S5 -25 0 -10Z", "fill", SVGPathOptions{Scale: 3, CurveSteps: 6})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := buildEmitterVectorParams(cfg.Emitter.Vector).Polyline.TotalArea
+	want := buildEmitterVectorParams(imported).Polyline.TotalArea
+	if want <= 0 || math.Abs(float64(got-want)) > float64(want)*0.01 {
+		t.Fatalf("expected svg_heart_fill to match its SVG source area %v, got %v", want, got)
+	}
+}
+
+func TestLoadComboTextBurstSample(t *testing.T) {
+	loader := NewConfigLoader()
+	path := filepath.Join("..", "..", "assets", "particles", "combo_text_burst.yaml")
+
+	cfg, err := loader.LoadConfig(path)
+	if err != nil {
+		t.Fatalf("expected combo_text_burst sample to load, got: %v", err)
+	}
+	if cfg.Emitter.Vector == nil || cfg.Emitter.Vector.Type != "text" || cfg.Emitter.Vector.Text == nil {
+		t.Fatalf("expected a text vector emitter, got %+v", cfg.Emitter.Vector)
+	}
+
+	fonts := NewFontRegistry()
+	fonts.Register(cfg.Emitter.Vector.Text.Font, newTestFontSource(t))
+	vectorConfig, err := resolveTextVector(cfg.Emitter.Vector, fonts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	params := buildEmitterVectorParams(vectorConfig)
+	if params.Polyline.TotalArea <= 0 || len(params.Polylines) == 0 {
+		t.Fatalf("expected filled glyphs for x and 5, got %+v", params)
+	}
+}
+
+func TestLoadEnemyDissolveSample(t *testing.T) {
+	loader := NewConfigLoader()
+	path := filepath.Join("..", "..", "assets", "particles", "enemy_dissolve.yaml")
+
+	cfg, err := loader.LoadConfig(path)
+	if err != nil {
+		t.Fatalf("expected enemy_dissolve sample to load, got: %v", err)
+	}
+	if cfg.Emitter.Sprite == nil || cfg.Emitter.Sprite.ImageFrom != "enemy" {
+		t.Fatalf("expected a sprite emitter reading the enemy texture, got %+v", cfg.Emitter.Sprite)
+	}
+
+	data := spriteSystemForTest(buildEmitterSpriteParams(cfg.Emitter.Sprite))
+	setEmitterSprite(data, newTestSprite())
+	(&System{}).spawn(data, defaultDeltaTime)
+	if data.ActiveCount != 2 {
+		t.Fatalf("expected one particle per opaque 3x3 block, got %d", data.ActiveCount)
+	}
+}
+
 func TestLoadReentryPlasmaWakeSample(t *testing.T) {
 	loader := NewConfigLoader()
 	path := filepath.Join("..", "..", "assets", "particles", "reentry_plasma_wake.yaml")
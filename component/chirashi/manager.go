@@ -2,28 +2,34 @@ package chirashi
 
 import (
 	"fmt"
+	"image"
 	"sync"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/yohamta/donburi"
+	"github.com/yohamta/donburi/ecs"
 )
 
 // ParticleManager manages particle configurations and provides easy spawning API
 type ParticleManager struct {
-	shader  *ebiten.Shader
-	image   *ebiten.Image
-	configs map[string]*ParticleConfig
-	loader  *ConfigLoader
-	mutex   sync.RWMutex
+	shader   *ebiten.Shader
+	image    *ebiten.Image
+	textures *TextureRegistry
+	fonts    *FontRegistry
+	configs  map[string]*ParticleConfig
+	loader   *ConfigLoader
+	mutex    sync.RWMutex
 }
 
 // NewParticleManager creates a new particle manager
 func NewParticleManager(shader *ebiten.Shader, image *ebiten.Image) *ParticleManager {
 	return &ParticleManager{
-		shader:  shader,
-		image:   image,
-		configs: make(map[string]*ParticleConfig),
-		loader:  NewConfigLoader(),
+		shader:   shader,
+		image:    image,
+		textures: NewTextureRegistry(),
+		fonts:    NewFontRegistry(),
+		configs:  make(map[string]*ParticleConfig),
+		loader:   NewConfigLoader(),
 	}
 }
 
@@ -58,25 +64,120 @@ func (m *ParticleManager) PreloadFromBytes(name string, data []byte) error {
 // SpawnOneShot spawns a one-shot particle effect at the given position
 // The particle system will automatically be removed after the specified lifetime (in frames)
 func (m *ParticleManager) SpawnOneShot(world donburi.World, name string, x, y float32, lifetimeFrames int) error {
+	_, err := m.spawnOneShot(world, name, x, y, lifetimeFrames, nil)
+	return err
+}
+
+// SpawnOneShotOnLayer is SpawnOneShot drawn on layer instead of the
+// preset's render.layer.
+func (m *ParticleManager) SpawnOneShotOnLayer(world donburi.World, name string, x, y float32, lifetimeFrames int, layer ecs.LayerID) error {
+	entity, err := m.spawnOneShot(world, name, x, y, lifetimeFrames, nil)
+	if err != nil {
+		return err
+	}
+	setRenderLayer(Component.Get(world.Entry(entity)), layer)
+	return nil
+}
+
+// SpawnOneShotWithSeed is SpawnOneShot with a fixed random seed, overriding
+// any seed in the preset. The same seed reproduces the same effect.
+func (m *ParticleManager) SpawnOneShotWithSeed(world donburi.World, name string, x, y float32, lifetimeFrames int, seed uint64) error {
+	_, err := m.spawnOneShot(world, name, x, y, lifetimeFrames, func(config *ParticleConfig) {
+		config.Seed = &seed
+	})
+	return err
+}
+
+// spawnOneShot spawns a copy of the named preset; override, if set, adjusts
+// the copy before the entity is created.
+func (m *ParticleManager) spawnOneShot(world donburi.World, name string, x, y float32, lifetimeFrames int, override func(*ParticleConfig)) (donburi.Entity, error) {
 	m.mutex.RLock()
 	baseConfig, exists := m.configs[name]
 	m.mutex.RUnlock()
 
 	if !exists {
-		return fmt.Errorf("particle config '%s' not found, call Preload first", name)
+		return 0, fmt.Errorf("particle config '%s' not found, call Preload first", name)
 	}
 
 	// Copy config to avoid modifying the cached version
 	config := copyConfig(baseConfig)
 	config.Spawn.IsLoop = false
 	config.Spawn.LifeTime = lifetimeFrames
+	if override != nil {
+		override(config)
+	}
 
-	return NewParticlesFromConfig(world, m.shader, m.image, config, x, y)
+	return m.createEntity(world, config, x, y)
+}
+
+// SpawnSprite spawns a one-shot sprite emitter that disintegrates sprite
+// into particles, or assembles it from them with mode: assemble, centered on
+// the given position. The preset must set emitter.sprite. sprite may be nil
+// to use the preset's image_from, or, without one, set later with
+// SetEmitterSprite; the emitter waits for it. The entity is removed once every
+// block has been released and its particle has expired.
+func (m *ParticleManager) SpawnSprite(world donburi.World, name string, sprite image.Image, x, y float32) (donburi.Entity, error) {
+	m.mutex.RLock()
+	baseConfig, exists := m.configs[name]
+	m.mutex.RUnlock()
+
+	if !exists {
+		return 0, fmt.Errorf("particle config '%s' not found, call Preload first", name)
+	}
+	if baseConfig.Emitter.Sprite == nil {
+		return 0, fmt.Errorf("particle config '%s' has no emitter.sprite", name)
+	}
+
+	config := copyConfig(baseConfig)
+	config.Spawn.IsLoop = false
+	// The emitter stays alive until every block is released, which takes
+	// more than one update when the emission scale caps the pool.
+	config.Spawn.LifeTime = max(config.Spawn.LifeTime, 1)
+	if sprite != nil {
+		// The given sprite replaces the preset's image reference.
+		config.Emitter.Sprite.ImageFrom = ""
+		config.Emitter.Sprite.ImageID = 0
+	}
+	entity, err := m.createEntity(world, config, x, y)
+	if err != nil {
+		return 0, err
+	}
+	data := Component.Get(world.Entry(entity))
+	if sprite != nil {
+		setEmitterSprite(data, sprite)
+	} else if data.Sprite.Image == nil {
+		data.Sprite.awaitImage = true
+	}
+	return entity, nil
 }
 
 // SpawnLoop spawns a looping particle effect at the given position
 // Returns the entity for manual removal later
 func (m *ParticleManager) SpawnLoop(world donburi.World, name string, x, y float32) (donburi.Entity, error) {
+	return m.spawnLoop(world, name, x, y, nil)
+}
+
+// SpawnLoopOnLayer is SpawnLoop drawn on layer instead of the preset's
+// render.layer, also across ApplyConfigLive. SetRenderLayer moves the
+// effect later.
+func (m *ParticleManager) SpawnLoopOnLayer(world donburi.World, name string, x, y float32, layer ecs.LayerID) (donburi.Entity, error) {
+	entity, err := m.spawnLoop(world, name, x, y, nil)
+	if err != nil {
+		return 0, err
+	}
+	setRenderLayer(Component.Get(world.Entry(entity)), layer)
+	return entity, nil
+}
+
+// SpawnLoopWithSeed is SpawnLoop with a fixed random seed, overriding any
+// seed in the preset.
+func (m *ParticleManager) SpawnLoopWithSeed(world donburi.World, name string, x, y float32, seed uint64) (donburi.Entity, error) {
+	return m.spawnLoop(world, name, x, y, func(config *ParticleConfig) {
+		config.Seed = &seed
+	})
+}
+
+func (m *ParticleManager) spawnLoop(world donburi.World, name string, x, y float32, override func(*ParticleConfig)) (donburi.Entity, error) {
 	m.mutex.RLock()
 	baseConfig, exists := m.configs[name]
 	m.mutex.RUnlock()
@@ -88,7 +189,49 @@ func (m *ParticleManager) SpawnLoop(world donburi.World, name string, x, y float
 	// Copy config
 	config := copyConfig(baseConfig)
 	config.Spawn.IsLoop = true
-	return createParticleEntityFromConfig(world, m.shader, m.image, config, x, y)
+	if override != nil {
+		override(config)
+	}
+	return m.createEntity(world, config, x, y)
+}
+
+// createEntity creates a particle entity whose sub-emitters resolve their
+// presets against m. Every preset its sub-emitters can reach must be loaded.
+func (m *ParticleManager) createEntity(world donburi.World, config *ParticleConfig, x, y float32) (donburi.Entity, error) {
+	if err := m.checkSubEmitterPresets(config, nil); err != nil {
+		return 0, err
+	}
+	entity, err := createParticleEntityFromConfig(world, m.shader, m.image, m.textures, m.fonts, config, x, y)
+	if err != nil {
+		return 0, err
+	}
+	Component.Get(world.Entry(entity)).subEmitterHost = m
+	return entity, nil
+}
+
+// checkSubEmitterPresets reports the first sub-emitter preset reachable from
+// config that is not loaded. seen holds the presets already checked, so
+// chains that reference themselves terminate.
+func (m *ParticleManager) checkSubEmitterPresets(config *ParticleConfig, seen map[string]bool) error {
+	for _, sub := range config.SubEmitters {
+		if seen[sub.Preset] {
+			continue
+		}
+		m.mutex.RLock()
+		child, exists := m.configs[sub.Preset]
+		m.mutex.RUnlock()
+		if !exists {
+			return fmt.Errorf("sub-emitter preset '%s' not found, call Preload first", sub.Preset)
+		}
+		if seen == nil {
+			seen = make(map[string]bool)
+		}
+		seen[sub.Preset] = true
+		if err := m.checkSubEmitterPresets(child, seen); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // SetShader updates the shader used for rendering
@@ -101,6 +244,30 @@ func (m *ParticleManager) SetImage(image *ebiten.Image) {
 	m.image = image
 }
 
+// Textures returns the registry that preset `image` references resolve
+// against. Presets whose reference is not registered use the default image.
+func (m *ParticleManager) Textures() *TextureRegistry {
+	return m.textures
+}
+
+// SetTextures replaces the texture registry, e.g. to share one registry
+// between managers.
+func (m *ParticleManager) SetTextures(textures *TextureRegistry) {
+	m.textures = textures
+}
+
+// Fonts returns the registry that text vector emitters look their
+// `emitter.vector.text.font` up in.
+func (m *ParticleManager) Fonts() *FontRegistry {
+	return m.fonts
+}
+
+// SetFonts replaces the font registry, e.g. to share one registry between
+// managers.
+func (m *ParticleManager) SetFonts(fonts *FontRegistry) {
+	m.fonts = fonts
+}
+
 // SetAttractor updates the attractor target for a particle entity.
 // Call each frame when the target moves (e.g. a score counter that slides around).
 // Has no effect on particles that do not use position type "attractor".
@@ -118,13 +285,20 @@ func SetAttractor(world donburi.World, entity donburi.Entity, x, y float32) {
 }
 
 // SetEmitterPosition updates the emitter origin for a particle entity.
-// In local emitter space, active particles move with the emitter.
+// In local emitter space, active particles move with the emitter. With
+// emitter.motion, the emitter keeps moving around the new origin.
 func SetEmitterPosition(world donburi.World, entity donburi.Entity, x, y float32) {
 	if !world.Valid(entity) {
 		return
 	}
 	entry := world.Entry(entity)
 	data := Component.Get(entry)
+	moveEmitterTo(data, x+data.motionOffsetX, y+data.motionOffsetY)
+}
+
+// moveEmitterTo moves the emitter origin, carrying local-space particles and
+// trail points along with it.
+func moveEmitterTo(data *SystemData, x, y float32) {
 	dx := x - data.EmitterX
 	dy := y - data.EmitterY
 	data.EmitterX = x
@@ -159,6 +333,22 @@ func SetEmissionScale(world donburi.World, entity donburi.Entity, scale float32)
 	data.EmissionScale = clampEmissionScale(scale)
 }
 
+// SetTimeScale changes how fast a particle entity's time advances, e.g. for
+// hit-stop or slow motion. Scale is clamped to >= 0; 0 freezes the effect and
+// 1 restores real time. The system-wide multiplier set with
+// System.SetTimeScale is applied on top.
+func SetTimeScale(world donburi.World, entity donburi.Entity, scale float32) {
+	if !world.Valid(entity) {
+		return
+	}
+	entry := world.Entry(entity)
+	if !entry.HasComponent(Component) {
+		return
+	}
+	data := Component.Get(entry)
+	data.TimeScale = clampTimeScale(scale)
+}
+
 // copyConfig creates a deep copy of ParticleConfig
 func copyConfig(src *ParticleConfig) *ParticleConfig {
 	dst := *src
@@ -171,6 +361,11 @@ func copyConfig(src *ParticleConfig) *ParticleConfig {
 		dst.Render.Afterimage = &afterimage
 	}
 
+	if src.Seed != nil {
+		seed := *src.Seed
+		dst.Seed = &seed
+	}
+
 	if src.Animation.Duration.Range != nil {
 		r := *src.Animation.Duration.Range
 		dst.Animation.Duration.Range = &r
@@ -180,22 +375,83 @@ func copyConfig(src *ParticleConfig) *ParticleConfig {
 	dst.Animation.Alpha = copyPropertyConfig(src.Animation.Alpha)
 	dst.Animation.Scale = copyPropertyConfig(src.Animation.Scale)
 	dst.Animation.Rotation = copyPropertyConfig(src.Animation.Rotation)
+	if src.Animation.ScaleX != nil {
+		scaleX := copyPropertyConfig(*src.Animation.ScaleX)
+		dst.Animation.ScaleX = &scaleX
+	}
+	if src.Animation.ScaleY != nil {
+		scaleY := copyPropertyConfig(*src.Animation.ScaleY)
+		dst.Animation.ScaleY = &scaleY
+	}
 
-	if src.Animation.Color != nil {
-		c := *src.Animation.Color
-		dst.Animation.Color = &c
+	dst.Animation.Color = copyColorConfig(src.Animation.Color)
+	if src.Animation.Frames != nil {
+		frames := *src.Animation.Frames
+		dst.Animation.Frames = &frames
 	}
 	if src.Trail != nil {
 		trail := *src.Trail
-		if src.Trail.Color != nil {
-			c := *src.Trail.Color
-			trail.Color = &c
-		}
+		trail.Color = copyColorConfig(src.Trail.Color)
 		dst.Trail = &trail
 	}
 
 	dst.Emitter = copyEmitterConfig(src.Emitter)
 
+	if len(src.Spawn.Bursts) > 0 {
+		dst.Spawn.Bursts = make([]BurstConfig, len(src.Spawn.Bursts))
+		for i, burst := range src.Spawn.Bursts {
+			dst.Spawn.Bursts[i] = burst
+			if burst.CountRange != nil {
+				r := *burst.CountRange
+				dst.Spawn.Bursts[i].CountRange = &r
+			}
+		}
+	}
+
+	if src.Collision != nil {
+		collision := *src.Collision
+		if len(src.Collision.Colliders) > 0 {
+			collision.Colliders = append([]ColliderConfig(nil), src.Collision.Colliders...)
+		}
+		dst.Collision = &collision
+	}
+
+	if len(src.SubEmitters) > 0 {
+		dst.SubEmitters = make([]SubEmitterConfig, len(src.SubEmitters))
+		for i, sub := range src.SubEmitters {
+			dst.SubEmitters[i] = sub
+			if sub.Probability != nil {
+				probability := *sub.Probability
+				dst.SubEmitters[i].Probability = &probability
+			}
+			if sub.Inherit != nil {
+				dst.SubEmitters[i].Inherit = append([]string{}, sub.Inherit...)
+			}
+		}
+	}
+
+	return &dst
+}
+
+func copyColorConfig(src *ColorConfig) *ColorConfig {
+	if src == nil {
+		return nil
+	}
+	dst := *src
+	dst.Variation = copyColorConfig(src.Variation)
+	if src.Gradient != nil {
+		dst.Gradient = make([]ColorStopConfig, len(src.Gradient))
+		for i, stop := range src.Gradient {
+			dst.Gradient[i] = stop
+			if stop.A != nil {
+				a := *stop.A
+				dst.Gradient[i].A = &a
+			}
+		}
+	}
+	if src.Palette != nil {
+		dst.Palette = append([]PaletteColorConfig(nil), src.Palette...)
+	}
 	return &dst
 }
 
@@ -212,14 +468,47 @@ func copyEmitterConfig(src EmitterConfig) EmitterConfig {
 			vector.Rect = &rect
 		}
 		if src.Vector.Polyline != nil {
-			polyline := *src.Vector.Polyline
-			if len(src.Vector.Polyline.Points) > 0 {
-				polyline.Points = append([]EmitterVectorPoint(nil), src.Vector.Polyline.Points...)
-			}
+			polyline := copyVectorPolylineConfig(src.Vector.Polyline)
 			vector.Polyline = &polyline
 		}
+		if len(src.Vector.Polylines) > 0 {
+			vector.Polylines = make([]EmitterVectorPolylineConfig, len(src.Vector.Polylines))
+			for i := range src.Vector.Polylines {
+				vector.Polylines[i] = copyVectorPolylineConfig(&src.Vector.Polylines[i])
+			}
+		}
+		if src.Vector.Text != nil {
+			text := *src.Vector.Text
+			vector.Text = &text
+		}
 		dst.Vector = &vector
 	}
+	if src.Sprite != nil {
+		sprite := *src.Sprite
+		dst.Sprite = &sprite
+	}
+	if src.Motion != nil {
+		motion := *src.Motion
+		if src.Motion.Path != nil {
+			path := copyVectorPolylineConfig(src.Motion.Path)
+			motion.Path = &path
+		}
+		dst.Motion = &motion
+	}
+	return dst
+}
+
+func copyVectorPolylineConfig(src *EmitterVectorPolylineConfig) EmitterVectorPolylineConfig {
+	dst := *src
+	if len(src.Points) > 0 {
+		dst.Points = append([]EmitterVectorPoint(nil), src.Points...)
+	}
+	if len(src.Holes) > 0 {
+		dst.Holes = make([][]EmitterVectorPoint, len(src.Holes))
+		for i, hole := range src.Holes {
+			dst.Holes[i] = append([]EmitterVectorPoint(nil), hole...)
+		}
+	}
 	return dst
 }
 
@@ -240,6 +529,8 @@ func copyPositionConfig(src PositionConfig) PositionConfig {
 	dst.Distance = copyRangePtr(src.Distance)
 	dst.ControlX = copyRangePtr(src.ControlX)
 	dst.ControlY = copyRangePtr(src.ControlY)
+	dst.VelocityX = copyRangePtr(src.VelocityX)
+	dst.VelocityY = copyRangePtr(src.VelocityY)
 	if src.Flow != nil {
 		flow := *src.Flow
 		flow.Strength = copyRangePtr(src.Flow.Strength)
@@ -258,6 +549,20 @@ func copyPositionConfig(src PositionConfig) PositionConfig {
 
 func copyPropertyConfig(src PropertyConfig) PropertyConfig {
 	dst := src
+	if len(src.Keys) > 0 {
+		dst.Keys = make([]CurveKeyConfig, len(src.Keys))
+		for i, key := range src.Keys {
+			dst.Keys[i] = key
+			if key.In != nil {
+				in := *key.In
+				dst.Keys[i].In = &in
+			}
+			if key.Out != nil {
+				out := *key.Out
+				dst.Keys[i].Out = &out
+			}
+		}
+	}
 	if len(src.Steps) == 0 {
 		return dst
 	}
@@ -1,9 +1,11 @@
 package chirashi
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/yohamta/donburi"
+	"github.com/yohamta/donburi/ecs"
 	"github.com/yohamta/donburi/filter"
 )
 
@@ -525,6 +527,29 @@ func TestSetEmissionScaleClampsAndPreservesSpawnValues(t *testing.T) {
 	}
 }
 
+func TestSetTimeScaleClampsNegativeValues(t *testing.T) {
+	world := donburi.NewWorld()
+	entity := world.Create(Component)
+	entry := world.Entry(entity)
+	donburi.SetValue(entry, Component, SystemData{TimeScale: 1})
+
+	SetTimeScale(world, entity, 0.25)
+	data := Component.Get(entry)
+	if data.TimeScale != 0.25 {
+		t.Fatalf("TimeScale got %v, want 0.25", data.TimeScale)
+	}
+
+	SetTimeScale(world, entity, -1)
+	if data.TimeScale != 0 {
+		t.Fatalf("negative scale got %v, want 0", data.TimeScale)
+	}
+
+	SetTimeScale(world, entity, 3)
+	if data.TimeScale != 3 {
+		t.Fatalf("fast-forward scale got %v, want 3", data.TimeScale)
+	}
+}
+
 func TestParticleManagerCopiesConfigOnSpawn(t *testing.T) {
 	yaml := []byte(`
 name: copytest
@@ -661,8 +686,8 @@ func TestAttractorParticleHasControlPoint(t *testing.T) {
 		},
 	}
 
-	sys := &System{cnt: 0}
-	sys.spawn(data)
+	sys := &System{}
+	sys.spawn(data, defaultDeltaTime)
 
 	if data.ActiveCount == 0 {
 		t.Fatal("no particles spawned")
@@ -768,3 +793,249 @@ func TestSetEmitterPositionShiftsLocalParticleTrails(t *testing.T) {
 		t.Fatalf("expected second particle trail point to shift with local emitter, got (%v,%v)", data.ParticlePool[0].TrailPoints[1].X, data.ParticlePool[0].TrailPoints[1].Y)
 	}
 }
+
+func TestParticleManagerSpawnLoopWithSeedOverridesPresetSeed(t *testing.T) {
+	m := NewParticleManager(nil, nil)
+	presetSeed := uint64(7)
+	cfg := validParticleConfigForTest()
+	cfg.Seed = &presetSeed
+	m.configs["seeded"] = cfg
+
+	world := donburi.NewWorld()
+	entity, err := m.SpawnLoopWithSeed(world, "seeded", 0, 0, 99)
+	if err != nil {
+		t.Fatalf("SpawnLoopWithSeed failed: %v", err)
+	}
+	if got := Component.Get(world.Entry(entity)).Seed; got != 99 {
+		t.Fatalf("entity seed got %d, want 99", got)
+	}
+	if *cfg.Seed != 7 {
+		t.Fatalf("cached preset seed was modified: %d", *cfg.Seed)
+	}
+
+	entity, err = m.SpawnLoop(world, "seeded", 0, 0)
+	if err != nil {
+		t.Fatalf("SpawnLoop failed: %v", err)
+	}
+	if got := Component.Get(world.Entry(entity)).Seed; got != 7 {
+		t.Fatalf("entity seed got %d, want preset seed 7", got)
+	}
+}
+
+func TestCopyConfigDeepCopiesSubEmitters(t *testing.T) {
+	probability := float32(0.5)
+	src := &ParticleConfig{
+		SubEmitters: []SubEmitterConfig{{
+			Preset:      "spark",
+			Trigger:     "death",
+			Probability: &probability,
+			Inherit:     []string{"position", "color"},
+		}},
+	}
+
+	dst := copyConfig(src)
+	dst.SubEmitters[0].Preset = "smoke"
+	*dst.SubEmitters[0].Probability = 1
+	dst.SubEmitters[0].Inherit[1] = "velocity"
+
+	if src.SubEmitters[0].Preset != "spark" {
+		t.Error("SubEmitters[0].Preset: src was modified by dst change")
+	}
+	if *src.SubEmitters[0].Probability != 0.5 {
+		t.Error("SubEmitters[0].Probability: src was modified by dst change")
+	}
+	if src.SubEmitters[0].Inherit[1] != "color" {
+		t.Error("SubEmitters[0].Inherit: src was modified by dst change")
+	}
+}
+
+func subEmitterParentConfigForTest(probability float32) *ParticleConfig {
+	cfg := validParticleConfigForTest()
+	cfg.Animation.Duration.Value = 0.05
+	cfg.Animation.Position = PositionConfig{
+		EndX:   &RangeFloat{Min: 30, Max: 30},
+		Easing: "Linear",
+	}
+	cfg.Animation.Color = &ColorConfig{StartR: 1, StartG: 0.5, StartB: 0, EndR: 1, EndG: 0.5, EndB: 0}
+	cfg.Spawn.MaxParticles = 1
+	cfg.SubEmitters = []SubEmitterConfig{{
+		Preset:      "burst",
+		Trigger:     "death",
+		Probability: &probability,
+		Inherit:     []string{"position", "color"},
+		LifeTime:    5,
+	}}
+	return cfg
+}
+
+func runUntilRemoved(t *testing.T, gameECS *ecs.ECS, sys *System, entity donburi.Entity) {
+	t.Helper()
+	for i := 0; i < 120 && gameECS.World.Valid(entity); i++ {
+		sys.Update(gameECS)
+	}
+	if gameECS.World.Valid(entity) {
+		t.Fatal("parent effect was not removed")
+	}
+}
+
+func TestSubEmitterSpawnsChildOnDeathWithInheritedState(t *testing.T) {
+	m := NewParticleManager(nil, nil)
+	m.configs["parent"] = subEmitterParentConfigForTest(1)
+	m.configs["burst"] = validParticleConfigForTest()
+
+	world := donburi.NewWorld()
+	gameECS := ecs.NewECS(world)
+	sys := NewSystem()
+	if err := m.SpawnOneShot(world, "parent", 100, 50, 1); err != nil {
+		t.Fatalf("SpawnOneShot failed: %v", err)
+	}
+	var parent donburi.Entity
+	donburi.NewQuery(filter.Contains(Component)).Each(world, func(e *donburi.Entry) { parent = e.Entity() })
+
+	runUntilRemoved(t, gameECS, sys, parent)
+
+	var children []*SystemData
+	donburi.NewQuery(filter.Contains(Component)).Each(world, func(e *donburi.Entry) {
+		children = append(children, Component.Get(e))
+	})
+	if len(children) != 1 {
+		t.Fatalf("expected 1 child effect, got %d", len(children))
+	}
+	child := children[0]
+	if child.EmitterX != 130 || child.EmitterY != 50 {
+		t.Fatalf("child emitter got (%v, %v), want parent death position (130, 50)", child.EmitterX, child.EmitterY)
+	}
+	if clr := child.AnimParams.Color; clr.StartR != 1 || clr.StartG != 0.5 || clr.StartB != 0 {
+		t.Fatalf("child color got (%v, %v, %v), want parent tint (1, 0.5, 0)", clr.StartR, clr.StartG, clr.StartB)
+	}
+	if child.IsLoop || child.subEmitterDepth != 1 || child.subEmitterHost != m {
+		t.Fatalf("unexpected child state: loop=%v depth=%d", child.IsLoop, child.subEmitterDepth)
+	}
+	if !m.configs["burst"].Spawn.IsLoop || m.configs["burst"].Seed != nil {
+		t.Fatal("cached child preset was modified")
+	}
+}
+
+func TestSubEmitterRespectsZeroProbability(t *testing.T) {
+	m := NewParticleManager(nil, nil)
+	m.configs["parent"] = subEmitterParentConfigForTest(0)
+	m.configs["burst"] = validParticleConfigForTest()
+
+	world := donburi.NewWorld()
+	gameECS := ecs.NewECS(world)
+	sys := NewSystem()
+	if err := m.SpawnOneShot(world, "parent", 0, 0, 1); err != nil {
+		t.Fatalf("SpawnOneShot failed: %v", err)
+	}
+	var parent donburi.Entity
+	donburi.NewQuery(filter.Contains(Component)).Each(world, func(e *donburi.Entry) { parent = e.Entity() })
+
+	runUntilRemoved(t, gameECS, sys, parent)
+
+	count := 0
+	donburi.NewQuery(filter.Contains(Component)).Each(world, func(e *donburi.Entry) { count++ })
+	if count != 0 {
+		t.Fatalf("expected no child effects, got %d", count)
+	}
+}
+
+func TestSpawnRejectsMissingSubEmitterPreset(t *testing.T) {
+	m := NewParticleManager(nil, nil)
+	m.configs["parent"] = subEmitterParentConfigForTest(1)
+	world := donburi.NewWorld()
+	if err := m.SpawnOneShot(world, "parent", 0, 0, 1); err == nil || !strings.Contains(err.Error(), "'burst'") {
+		t.Fatalf("expected the missing child preset to be reported, got %v", err)
+	}
+
+	// Children are checked too, and a chain back to the parent terminates.
+	burst := validParticleConfigForTest()
+	burst.SubEmitters = []SubEmitterConfig{{Preset: "parent"}, {Preset: "sparkle"}}
+	m.configs["burst"] = burst
+	if _, err := m.SpawnLoop(world, "parent", 0, 0); err == nil || !strings.Contains(err.Error(), "'sparkle'") {
+		t.Fatalf("expected the missing grandchild preset to be reported, got %v", err)
+	}
+	count := 0
+	donburi.NewQuery(filter.Contains(Component)).Each(world, func(e *donburi.Entry) { count++ })
+	if count != 0 {
+		t.Fatalf("expected no entities to be created, got %d", count)
+	}
+
+	m.configs["sparkle"] = validParticleConfigForTest()
+	if _, err := m.SpawnLoop(world, "parent", 0, 0); err != nil {
+		t.Fatalf("SpawnLoop failed: %v", err)
+	}
+}
+
+func TestInheritedVelocityAppliesToParticlesAtSpawn(t *testing.T) {
+	world := donburi.NewWorld()
+	entity := world.Create(Component)
+	donburi.SetValue(world.Entry(entity), Component, SystemData{
+		ParticlePool:      make([]Instance, 1),
+		SpawnInterval:     1,
+		ParticlesPerSpawn: 1,
+		MaxParticles:      1,
+		EmissionScale:     1,
+		IsLoop:            true,
+		TimeScale:         1,
+		EmitterLocalSpace: true,
+		InheritedVelX:     120,
+		AnimParams:        AnimationParams{Duration: DurationParams{Base: 10}},
+	})
+	gameECS := ecs.NewECS(world)
+	sys := NewSystem()
+	for range 120 {
+		sys.Update(gameECS)
+	}
+
+	data := Component.Get(world.Entry(entity))
+	if data.EmitterX != 0 || data.EmitterY != 0 {
+		t.Fatalf("expected the child emitter to stay put, got (%v, %v)", data.EmitterX, data.EmitterY)
+	}
+	// The drift decays, leveling off at 120 / inheritedVelocityDamping.
+	if x := data.ParticlePool[0].CurrentX; x < 29 || x > 30 {
+		t.Fatalf("path particle drifted to %v, want just under 30", x)
+	}
+
+	physics := physicsSystemForTest(PositionParams{VelXMin: 10, VelXMax: 10})
+	physics.InheritedVelX, physics.InheritedVelY = 120, -40
+	(&System{}).spawn(physics, defaultDeltaTime)
+	if p := &physics.ParticlePool[0]; p.HasInheritedVel || !nearFloat(p.VelX, 130) || !nearFloat(p.VelY, -40) {
+		t.Fatalf("physics launch velocity got (%v, %v), want (130, -40)", p.VelX, p.VelY)
+	}
+}
+
+func TestSpawnOneShotEmitsScheduledBursts(t *testing.T) {
+	cfg := validParticleConfigForTest()
+	cfg.Animation.Duration.Value = 5
+	cfg.Spawn = SpawnConfig{
+		MaxParticles: 100,
+		Bursts: []BurstConfig{
+			{Time: 0, Count: 40},
+			{Time: 0.1, Count: 15},
+			{Time: 0.2, Count: 5, Cycles: 3, Interval: 0.2},
+		},
+	}
+	if err := NewConfigLoader().validateConfig(cfg); err != nil {
+		t.Fatalf("burst-only config should validate, got: %v", err)
+	}
+	m := NewParticleManager(nil, nil)
+	m.configs["explosion"] = cfg
+
+	world := donburi.NewWorld()
+	gameECS := ecs.NewECS(world)
+	sys := NewSystem()
+	if err := m.SpawnOneShot(world, "explosion", 0, 0, 60); err != nil {
+		t.Fatalf("SpawnOneShot failed: %v", err)
+	}
+	var data *SystemData
+	donburi.NewQuery(filter.Contains(Component)).Each(world, func(e *donburi.Entry) { data = Component.Get(e) })
+
+	// Frames just before and after each scheduled time (frame n is n/60 s).
+	want := map[int]int{1: 40, 5: 40, 7: 55, 11: 55, 13: 60, 23: 60, 25: 65, 35: 65, 37: 70, 59: 70}
+	for frame := 1; frame < 60; frame++ {
+		sys.Update(gameECS)
+		if w, ok := want[frame]; ok && data.ActiveCount != w {
+			t.Fatalf("frame %d: active count %d, want %d", frame, data.ActiveCount, w)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package chirashi
+
+import "math"
+
+// EmitterMotionParams is the normalized emitter.motion block. Offsets are
+// relative to the emitter origin and evaluated on the entity clock.
+type EmitterMotionParams struct {
+	Type         EmitterMotionType
+	Radius       float32
+	AngularSpeed float32
+	AmplitudeX   float32
+	AmplitudeY   float32
+	Frequency    float32
+	Phase        float32
+	Path         EmitterVectorPolylineParams
+	Speed        float32
+	PingPong     bool
+}
+
+type EmitterMotionType int
+
+const (
+	EmitterMotionNone EmitterMotionType = iota
+	EmitterMotionOrbit
+	EmitterMotionSway
+	EmitterMotionPath
+)
+
+func buildEmitterMotionParams(config *EmitterMotionConfig) EmitterMotionParams {
+	if config == nil {
+		return EmitterMotionParams{}
+	}
+	params := EmitterMotionParams{
+		Type:         parseEmitterMotionType(config.Type),
+		Radius:       config.Radius,
+		AngularSpeed: config.AngularSpeed,
+		AmplitudeX:   config.AmplitudeX,
+		AmplitudeY:   config.AmplitudeY,
+		Frequency:    config.Frequency,
+		Phase:        config.Phase,
+		Speed:        config.Speed,
+		PingPong:     config.Mode == "ping_pong",
+	}
+	if config.Path != nil {
+		params.Path = buildEmitterVectorPolylineParams(config.Path)
+	}
+	return params
+}
+
+func parseEmitterMotionType(motionType string) EmitterMotionType {
+	switch motionType {
+	case "orbit":
+		return EmitterMotionOrbit
+	case "sway":
+		return EmitterMotionSway
+	case "path":
+		return EmitterMotionPath
+	default:
+		return EmitterMotionNone
+	}
+}
+
+// offset returns the emitter offset from its origin at time t, and the number
+// of times a looping open path has wrapped back to its start.
+func (m *EmitterMotionParams) offset(t float32) (float32, float32, int) {
+	switch m.Type {
+	case EmitterMotionOrbit:
+		angle := float64(m.Phase + m.AngularSpeed*t)
+		return m.Radius * float32(math.Cos(angle)), m.Radius * float32(math.Sin(angle)), 0
+	case EmitterMotionSway:
+		s := float32(math.Sin(2*math.Pi*float64(m.Frequency*t) + float64(m.Phase)))
+		return m.AmplitudeX * s, m.AmplitudeY * s, 0
+	case EmitterMotionPath:
+		return m.pathOffset(t)
+	default:
+		return 0, 0, 0
+	}
+}
+
+func (m *EmitterMotionParams) pathOffset(t float32) (float32, float32, int) {
+	path := &m.Path
+	if len(path.Points) < 2 || path.TotalLength <= 0 {
+		return 0, 0, 0
+	}
+	distance := m.Speed * t
+	if m.PingPong {
+		d := float32(math.Mod(float64(distance), float64(2*path.TotalLength)))
+		if d > path.TotalLength {
+			d = 2*path.TotalLength - d
+		}
+		x, y := polylinePointAtDistance(*path, d)
+		return x, y, 0
+	}
+	lap := int(math.Floor(float64(distance / path.TotalLength)))
+	d := distance - float32(lap)*path.TotalLength
+	x, y := polylinePointAtDistance(*path, d)
+	if path.Closed {
+		// A closed path wraps without a jump.
+		lap = 0
+	}
+	return x, y, lap
+}
+
+// updateEmitterMotion moves the emitter to its origin plus the motion offset
+// at the current entity time. Only the emission point moves: local-space
+// particles and trails stay relative to the origin.
+func updateEmitterMotion(data *SystemData) {
+	if data.EmitterMotion.Type == EmitterMotionNone && data.motionOffsetX == 0 && data.motionOffsetY == 0 {
+		return
+	}
+	x, y, lap := data.EmitterMotion.offset(data.CurrentTime)
+	data.EmitterX += x - data.motionOffsetX
+	data.EmitterY += y - data.motionOffsetY
+	data.motionOffsetX = x
+	data.motionOffsetY = y
+	if lap != data.motionLap {
+		// A looping open path jumps back to its start; do not emit along the jump.
+		data.motionLap = lap
+		data.emitterPathValid = false
+	}
+}
+
+// setEmitterMotion replaces the emitter motion, keeping the emitter origin.
+func setEmitterMotion(data *SystemData, motion EmitterMotionParams) {
+	data.EmitterX -= data.motionOffsetX
+	data.EmitterY -= data.motionOffsetY
+	data.motionOffsetX = 0
+	data.motionOffsetY = 0
+	data.EmitterMotion = motion
+	_, _, data.motionLap = motion.offset(data.CurrentTime)
+	updateEmitterMotion(data)
+}
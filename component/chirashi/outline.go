@@ -0,0 +1,105 @@
+package chirashi
+
+import (
+	"math"
+	"sort"
+)
+
+// outlineSubpath is one flattened contour of an imported outline such as an
+// SVG subpath or a font glyph contour.
+type outlineSubpath struct {
+	points []EmitterVectorPoint
+	closed bool
+	// params is points converted for the polygon helpers, if the importer
+	// already needed it. nestOutlineSubpaths fills it for closed rings.
+	params []EmitterVectorPointParams
+}
+
+// dedupeOutlinePoints drops repeated points, and the explicit closing point
+// of a closed subpath.
+func dedupeOutlinePoints(points []EmitterVectorPoint, closed bool) []EmitterVectorPoint {
+	out := points[:0]
+	for _, pt := range points {
+		if len(out) > 0 && out[len(out)-1] == pt {
+			continue
+		}
+		out = append(out, pt)
+	}
+	if closed {
+		for len(out) > 1 && out[0] == out[len(out)-1] {
+			out = out[:len(out)-1]
+		}
+	}
+	return out
+}
+
+// nestOutlineSubpaths turns closed subpaths at an odd nesting depth into holes
+// of their innermost container. Open subpaths stay separate outlines.
+func nestOutlineSubpaths(subpaths []outlineSubpath) []EmitterVectorPolylineConfig {
+	type ring struct {
+		sub    outlineSubpath
+		area   float32
+		parent int
+		depth  int
+	}
+	rings := make([]ring, len(subpaths))
+	for i, sub := range subpaths {
+		rings[i] = ring{sub: sub, parent: -1}
+		if sub.closed {
+			if sub.params == nil {
+				rings[i].sub.params = outlineRingParams(sub.points)
+			}
+			rings[i].area = float32(math.Abs(float64(polygonSignedArea(rings[i].sub.params))))
+		}
+	}
+	// Visit larger rings first so a parent is resolved before its children.
+	order := make([]int, len(rings))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return rings[order[a]].area > rings[order[b]].area })
+	for oi, i := range order {
+		if !rings[i].sub.closed {
+			continue
+		}
+		probe := rings[i].sub.params[0]
+		for _, j := range order[:oi] {
+			if !rings[j].sub.closed || rings[j].area <= rings[i].area {
+				continue
+			}
+			if pointInPolygon(probe, rings[j].sub.params) {
+				// Later matches in area order are smaller, i.e. closer.
+				rings[i].parent = j
+				rings[i].depth = rings[j].depth + 1
+			}
+		}
+	}
+
+	index := make([]int, len(rings))
+	polylines := make([]EmitterVectorPolylineConfig, 0, len(rings))
+	for i, r := range rings {
+		index[i] = -1
+		if r.depth%2 == 1 {
+			continue
+		}
+		index[i] = len(polylines)
+		polylines = append(polylines, EmitterVectorPolylineConfig{Closed: r.sub.closed, Points: r.sub.points})
+	}
+	for _, r := range rings {
+		if r.depth%2 == 1 && index[r.parent] >= 0 {
+			outer := &polylines[index[r.parent]]
+			outer.Holes = append(outer.Holes, r.sub.points)
+		}
+	}
+	return polylines
+}
+
+// outlineRingParams converts outline points for the polygon helpers in
+// triangulate.go.
+func outlineRingParams(points []EmitterVectorPoint) []EmitterVectorPointParams {
+	params := make([]EmitterVectorPointParams, len(points))
+	for i, point := range points {
+		params[i] = EmitterVectorPointParams(point)
+	}
+	return params
+}
@@ -0,0 +1,48 @@
+package chirashi
+
+import "math/rand/v2"
+
+// randStreamSalt decorrelates the two PCG state words derived from one seed.
+const randStreamSalt = uint64(0x9e3779b97f4a7c15)
+
+// newSystemRand returns the random stream for one particle entity. The same
+// seed always yields the same sequence of draws.
+func newSystemRand(seed uint64) *rand.Rand {
+	return rand.New(rand.NewPCG(seed, seed^randStreamSalt))
+}
+
+// seedSystemRand resets data's random stream to seed and records the seed so
+// callers can read it back for replays.
+func seedSystemRand(data *SystemData, seed uint64) {
+	data.Seed = seed
+	data.rng = newSystemRand(seed)
+}
+
+// randFloat32 returns a value in [0, 1) from rng. A nil rng falls back to the
+// global source so hand-built SystemData values keep working.
+func randFloat32(rng *rand.Rand) float32 {
+	if rng == nil {
+		return rand.Float32()
+	}
+	return rng.Float32()
+}
+
+func randUint64(rng *rand.Rand) uint64 {
+	if rng == nil {
+		return rand.Uint64()
+	}
+	return rng.Uint64()
+}
+
+// particleRandFloat32 advances the particle's own splitmix64 stream and
+// returns a value in [0, 1). Draws made during the (possibly parallel)
+// simulation phase use this instead of the shared entity stream, so results
+// do not depend on goroutine scheduling.
+func particleRandFloat32(p *Instance) float32 {
+	p.RandState += randStreamSalt
+	z := p.RandState
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	z ^= z >> 31
+	return float32(z>>40) / (1 << 24)
+}
@@ -39,6 +39,11 @@ func GenerateSnapshot(config *SequenceConfig, baseValue float32) SequenceSnapsho
 // FillSnapshot randomizes snap in place for one particle, reusing the existing
 // Values slice when possible so respawning does not allocate.
 func FillSnapshot(config *SequenceConfig, snap *SequenceSnapshot, baseValue float32) {
+	fillSnapshot(config, snap, baseValue, nil)
+}
+
+// fillSnapshot is FillSnapshot drawing from rng (nil uses the global source).
+func fillSnapshot(config *SequenceConfig, snap *SequenceSnapshot, baseValue float32, rng *rand.Rand) {
 	need := len(config.Steps) * 2
 	if cap(snap.Values) < need {
 		snap.Values = make([]float32, need)
@@ -52,12 +57,12 @@ func FillSnapshot(config *SequenceConfig, snap *SequenceSnapshot, baseValue floa
 	for i, step := range config.Steps {
 		from := currentBase + step.FromBase
 		if step.FromRange > 0 {
-			from += (rand.Float32()*2 - 1) * step.FromRange
+			from += (randFloat32(rng)*2 - 1) * step.FromRange
 		}
 
 		to := currentBase + step.ToBase
 		if step.ToRange > 0 {
-			to += (rand.Float32()*2 - 1) * step.ToRange
+			to += (randFloat32(rng)*2 - 1) * step.ToRange
 		}
 
 		snap.Values[i*2] = from
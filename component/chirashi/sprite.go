@@ -0,0 +1,233 @@
+package chirashi
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/yohamta/donburi"
+)
+
+// SpriteEmitterParams holds a sprite emitter. Blocks are read from Image on
+// the first spawn after the image is set, so an *ebiten.Image can be handed
+// over before the game loop starts.
+type SpriteEmitterParams struct {
+	Enabled        bool
+	BlockSize      int
+	AlphaThreshold float32
+	Scale          float32
+	// Assemble reverses each particle's path so it comes to rest on its
+	// block instead of leaving it.
+	Assemble bool
+
+	Image       image.Image
+	Blocks      []SpriteBlock
+	blocksValid bool
+	emitted     int  // blocks released since the image was set
+	awaitImage  bool // set by SpawnSprite without a sprite; waits for SetEmitterSprite
+}
+
+// SpriteBlock is one pixel block of a sprite emitter: its center relative to
+// the sprite center in world units, and its mean straight-alpha color.
+type SpriteBlock struct {
+	X, Y       float32
+	R, G, B, A float32
+}
+
+func buildEmitterSpriteParams(config *EmitterSpriteConfig) SpriteEmitterParams {
+	if config == nil {
+		return SpriteEmitterParams{}
+	}
+	params := SpriteEmitterParams{
+		Enabled:        true,
+		BlockSize:      config.BlockSize,
+		AlphaThreshold: config.AlphaThreshold,
+		Scale:          config.Scale,
+		Assemble:       config.Mode == "assemble",
+	}
+	if params.BlockSize <= 0 {
+		params.BlockSize = 1
+	}
+	if params.Scale <= 0 {
+		params.Scale = 1
+	}
+	return params
+}
+
+// SetEmitterSprite sets the sprite a sprite emitter disintegrates or
+// assembles, e.g. an enemy's current frame, and releases its blocks again on
+// the next update. sprite may be an *ebiten.Image (sub-images select a
+// region) or any other image.Image; its pixels are read back once.
+// Has no effect on particles without emitter.sprite.
+func SetEmitterSprite(world donburi.World, entity donburi.Entity, sprite image.Image) {
+	if !world.Valid(entity) {
+		return
+	}
+	entry := world.Entry(entity)
+	if !entry.HasComponent(Component) {
+		return
+	}
+	setEmitterSprite(Component.Get(entry), sprite)
+}
+
+func setEmitterSprite(data *SystemData, sprite image.Image) {
+	if !data.Sprite.Enabled {
+		return
+	}
+	data.Sprite.Image = sprite
+	data.Sprite.Blocks = nil
+	data.Sprite.blocksValid = false
+	data.Sprite.emitted = 0
+}
+
+// setSpriteParams replaces the sprite settings and keeps the sprite image.
+// Changing how blocks are cut re-reads the image and releases it again.
+func setSpriteParams(data *SystemData, params SpriteEmitterParams) {
+	prev := data.Sprite
+	data.Sprite = params
+	if !params.Enabled {
+		return
+	}
+	data.Sprite.Image = prev.Image
+	data.Sprite.awaitImage = prev.awaitImage
+	if prev.Enabled && prev.BlockSize == params.BlockSize && prev.AlphaThreshold == params.AlphaThreshold && prev.Scale == params.Scale {
+		data.Sprite.Blocks = prev.Blocks
+		data.Sprite.blocksValid = prev.blocksValid
+		data.Sprite.emitted = prev.emitted
+	}
+}
+
+// spriteEmission returns how many blocks a sprite emitter releases this
+// update: every block once, and on a looping emitter again after all of the
+// previous release has expired.
+func spriteEmission(data *SystemData) int {
+	sprite := &data.Sprite
+	if !sprite.blocksValid && sprite.Image != nil {
+		sprite.Blocks = readSpriteBlocks(sprite.Image, sprite.BlockSize, sprite.AlphaThreshold, sprite.Scale)
+		sprite.blocksValid = true
+		sprite.emitted = 0
+		growParticlePool(data, len(sprite.Blocks))
+	}
+	if data.IsLoop && sprite.emitted >= len(sprite.Blocks) && data.ActiveCount == 0 {
+		sprite.emitted = 0
+	}
+	return len(sprite.Blocks) - sprite.emitted
+}
+
+// spriteBlocksPending reports whether a sprite emitter has blocks left to
+// release, a sprite not read yet, or is waiting for one from SpawnSprite.
+// One-shot sprite emitters outlive their lifetime until then, e.g. while a
+// low emission scale caps the pool.
+func spriteBlocksPending(data *SystemData) bool {
+	sprite := &data.Sprite
+	if !sprite.Enabled {
+		return false
+	}
+	if !sprite.blocksValid {
+		return sprite.Image != nil || sprite.awaitImage
+	}
+	return sprite.emitted < len(sprite.Blocks)
+}
+
+// resolveSpriteImage looks up the image an emitter.sprite refers to. A
+// sprite without image_from or image_id resolves to nil; a reference that is
+// not in textures is an error.
+func resolveSpriteImage(config *EmitterSpriteConfig, textures *TextureRegistry) (*ebiten.Image, error) {
+	if config == nil || (config.ImageFrom == "" && config.ImageID == 0) {
+		return nil, nil
+	}
+	if textures == nil {
+		return nil, fmt.Errorf("emitter.sprite.image_from %q needs a texture registry: spawn it through ParticleManager or set the sprite with SetEmitterSprite", config.ImageFrom)
+	}
+	img, ok := textures.Lookup(config.ImageFrom, config.ImageID)
+	if !ok || img == nil {
+		return nil, fmt.Errorf("emitter.sprite.image_from %q (image_id %d) is not registered", config.ImageFrom, config.ImageID)
+	}
+	return img, nil
+}
+
+// growParticlePool makes room for n particles, keeping the active ones.
+func growParticlePool(data *SystemData, n int) {
+	data.MaxParticles = max(data.MaxParticles, n)
+	if n <= len(data.ParticlePool) {
+		return
+	}
+	pool := make([]Instance, n)
+	copy(pool, data.ParticlePool)
+	if data.Trail.Params.Enabled && data.Trail.Params.Mode == "particle" {
+		for i := len(data.ParticlePool); i < n; i++ {
+			pool[i].TrailPoints = make([]TrailPoint, 0, data.Trail.Params.MaxPoints)
+		}
+	}
+	data.ParticlePool = pool
+}
+
+// readSpriteBlocks cuts img into blockSize x blockSize blocks (smaller along
+// the right and bottom edges) and returns those whose mean alpha is above
+// alphaThreshold, in row-major order.
+func readSpriteBlocks(img image.Image, blockSize int, alphaThreshold, scale float32) []SpriteBlock {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if w <= 0 || h <= 0 {
+		return nil
+	}
+	pixels := spritePixels(img)
+	blocks := make([]SpriteBlock, 0, ((w+blockSize-1)/blockSize)*((h+blockSize-1)/blockSize))
+	for by := 0; by < h; by += blockSize {
+		bh := min(blockSize, h-by)
+		for bx := 0; bx < w; bx += blockSize {
+			bw := min(blockSize, w-bx)
+			// Pixels are premultiplied, so the sums give an alpha-weighted
+			// mean color once divided by the alpha sum.
+			var sumR, sumG, sumB, sumA int
+			for y := by; y < by+bh; y++ {
+				row := pixels[(y*w+bx)*4 : (y*w+bx+bw)*4]
+				for i := 0; i < len(row); i += 4 {
+					sumR += int(row[i])
+					sumG += int(row[i+1])
+					sumB += int(row[i+2])
+					sumA += int(row[i+3])
+				}
+			}
+			alpha := float32(sumA) / float32(bw*bh*255)
+			if sumA == 0 || alpha <= alphaThreshold {
+				continue
+			}
+			blocks = append(blocks, SpriteBlock{
+				X: (float32(bx) + float32(bw)/2 - float32(w)/2) * scale,
+				Y: (float32(by) + float32(bh)/2 - float32(h)/2) * scale,
+				R: min(float32(sumR)/float32(sumA), 1),
+				G: min(float32(sumG)/float32(sumA), 1),
+				B: min(float32(sumB)/float32(sumA), 1),
+				A: alpha,
+			})
+		}
+	}
+	return blocks
+}
+
+// spritePixels returns img as tightly packed premultiplied RGBA bytes.
+func spritePixels(img image.Image) []byte {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	pixels := make([]byte, 4*w*h)
+	switch src := img.(type) {
+	case *ebiten.Image:
+		src.ReadPixels(pixels)
+	case *image.RGBA:
+		for y := 0; y < h; y++ {
+			offset := src.PixOffset(bounds.Min.X, bounds.Min.Y+y)
+			copy(pixels[y*w*4:(y+1)*w*4], src.Pix[offset:offset+w*4])
+		}
+	default:
+		i := 0
+		for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+			for x := bounds.Min.X; x < bounds.Max.X; x++ {
+				r, g, b, a := src.At(x, y).RGBA()
+				pixels[i], pixels[i+1], pixels[i+2], pixels[i+3] = byte(r>>8), byte(g>>8), byte(b>>8), byte(a>>8)
+				i += 4
+			}
+		}
+	}
+	return pixels
+}
@@ -0,0 +1,249 @@
+package chirashi
+
+import (
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+
+	"github.com/yohamta/donburi"
+	"github.com/yohamta/donburi/ecs"
+	"github.com/yohamta/donburi/filter"
+)
+
+// newTestSprite returns a 6x2 sprite cut into three 2x2 blocks: opaque red,
+// transparent, and half-transparent blue.
+func newTestSprite() *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, 6, 2))
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 2; x++ {
+			img.Set(x, y, color.NRGBA{R: 255, A: 255})
+			img.Set(x+4, y, color.NRGBA{B: 255, A: 128})
+		}
+	}
+	return img
+}
+
+func spriteSystemForTest(sprite SpriteEmitterParams) *SystemData {
+	return &SystemData{
+		EmissionScale: 1,
+		LifeTime:      1,
+		EmitterX:      100,
+		EmitterY:      50,
+		Sprite:        sprite,
+		AnimParams: AnimationParams{
+			Duration: DurationParams{Base: 1},
+			Color: ColorParams{
+				StartR: 1, StartG: 1, StartB: 1,
+				EndR: 1, EndG: 1, EndB: 1,
+			},
+		},
+	}
+}
+
+func TestSpawnSpriteReleasesOneTintedParticlePerOpaqueBlock(t *testing.T) {
+	sys := &System{}
+	data := spriteSystemForTest(buildEmitterSpriteParams(&EmitterSpriteConfig{BlockSize: 2, Scale: 2}))
+	setEmitterSprite(data, newTestSprite())
+
+	sys.spawn(data, defaultDeltaTime)
+	if data.ActiveCount != 2 || len(data.ParticlePool) != 2 {
+		t.Fatalf("expected the pool to grow to the 2 opaque blocks, got %d active of %d", data.ActiveCount, len(data.ParticlePool))
+	}
+	want := []struct{ x, r, b, a float32 }{
+		{x: 96, r: 1, a: 1},
+		{x: 104, b: 1, a: 128.0 / 255},
+	}
+	for i, w := range want {
+		p := &data.ParticlePool[i]
+		if !nearFloat(p.StartX, w.x) || !nearFloat(p.StartY, 50) {
+			t.Fatalf("particle %d spawned at (%v, %v), want (%v, 50)", i, p.StartX, p.StartY, w.x)
+		}
+		r, g, b, a := particleTint(p, 0)
+		if !nearFloat(r, w.r) || g != 0 || !nearFloat(b, w.b) || !nearFloat(a, w.a) {
+			t.Fatalf("particle %d tint got (%v, %v, %v, %v), want the block color", i, r, g, b, a)
+		}
+	}
+
+	sys.spawn(data, defaultDeltaTime)
+	if data.ActiveCount != 2 {
+		t.Fatalf("a one-shot sprite released its blocks twice, got %d active", data.ActiveCount)
+	}
+
+	// A new frame releases again.
+	data.ActiveCount = 0
+	setEmitterSprite(data, newTestSprite())
+	sys.spawn(data, defaultDeltaTime)
+	if data.ActiveCount != 2 {
+		t.Fatalf("expected SetEmitterSprite to release the blocks again, got %d active", data.ActiveCount)
+	}
+}
+
+func TestSpawnSpriteAssembleEndsOnBlock(t *testing.T) {
+	sys := &System{}
+	data := spriteSystemForTest(buildEmitterSpriteParams(&EmitterSpriteConfig{BlockSize: 2, AlphaThreshold: 0.6, Mode: "assemble"}))
+	data.AnimParams.Position = PositionParams{EndXMin: 30, EndXMax: 30, EndYMin: -20, EndYMax: -20}
+	setEmitterSprite(data, newTestSprite())
+
+	sys.spawn(data, defaultDeltaTime)
+	if data.ActiveCount != 1 {
+		t.Fatalf("expected the threshold to drop the half-transparent block, got %d active", data.ActiveCount)
+	}
+	p := &data.ParticlePool[0]
+	if !nearFloat(p.EndX, 98) || !nearFloat(p.EndY, 50) {
+		t.Fatalf("assembling particle ends at (%v, %v), want its block at (98, 50)", p.EndX, p.EndY)
+	}
+	if !nearFloat(p.StartX, 128) || !nearFloat(p.StartY, 30) {
+		t.Fatalf("assembling particle starts at (%v, %v), want the reversed end (128, 30)", p.StartX, p.StartY)
+	}
+}
+
+func TestParticleManagerSpawnSprite(t *testing.T) {
+	m := NewParticleManager(nil, nil)
+	if err := m.PreloadFromBytes("dissolve", []byte(`
+name: dissolve
+emitter:
+  sprite:
+    block_size: 2
+animation:
+  duration:
+    value: 1.0
+spawn:
+  interval: 1
+  particles_per_spawn: 1
+  max_particles: 1
+`)); err != nil {
+		t.Fatalf("PreloadFromBytes failed: %v", err)
+	}
+	if err := m.PreloadFromBytes("plain", []byte(`
+name: plain
+animation:
+  duration:
+    value: 1.0
+spawn:
+  interval: 1
+  particles_per_spawn: 1
+  max_particles: 1
+`)); err != nil {
+		t.Fatalf("PreloadFromBytes failed: %v", err)
+	}
+
+	world := donburi.NewWorld()
+	if _, err := m.SpawnSprite(world, "plain", newTestSprite(), 0, 0); err == nil || !strings.Contains(err.Error(), "emitter.sprite") {
+		t.Fatalf("expected a preset without emitter.sprite to be rejected, got %v", err)
+	}
+	entity, err := m.SpawnSprite(world, "dissolve", newTestSprite(), 10, 20)
+	if err != nil {
+		t.Fatalf("SpawnSprite failed: %v", err)
+	}
+	data := Component.Get(world.Entry(entity))
+	if data.IsLoop || data.LifeTime <= 0 {
+		t.Fatalf("expected a one-shot entity, got loop %v lifetime %v", data.IsLoop, data.LifeTime)
+	}
+	(&System{}).spawn(data, defaultDeltaTime)
+	if data.ActiveCount != 2 {
+		t.Fatalf("expected every block on the first update despite max_particles, got %d", data.ActiveCount)
+	}
+}
+
+func TestSpawnSpriteReleasesEveryBlockAtLowEmissionScale(t *testing.T) {
+	m := NewParticleManager(nil, nil)
+	if err := m.PreloadFromBytes("dissolve", []byte(`
+name: dissolve
+emitter:
+  sprite:
+    block_size: 2
+animation:
+  duration:
+    value: 0.5
+spawn:
+  interval: 1
+  particles_per_spawn: 1
+  max_particles: 1
+`)); err != nil {
+		t.Fatalf("PreloadFromBytes failed: %v", err)
+	}
+	world := donburi.NewWorld()
+	gameECS := ecs.NewECS(world)
+	sys := NewSystem()
+
+	entity, err := m.SpawnSprite(world, "dissolve", newTestSprite(), 0, 0)
+	if err != nil {
+		t.Fatalf("SpawnSprite failed: %v", err)
+	}
+	SetEmissionScale(world, entity, 0.5)
+	released := 0
+	for i := 0; i < 240 && world.Valid(entity); i++ {
+		sys.Update(gameECS)
+		if world.Valid(entity) {
+			data := Component.Get(world.Entry(entity))
+			if data.ActiveCount > 1 {
+				t.Fatalf("expected emission scale 0.5 to cap the 2 blocks at 1 live particle, got %d", data.ActiveCount)
+			}
+			released = data.Sprite.emitted
+		}
+	}
+	if world.Valid(entity) || released != 2 {
+		t.Fatalf("expected both blocks released before removal, got %d (entity alive %v)", released, world.Valid(entity))
+	}
+
+	// Without a sprite the emitter waits for SetEmitterSprite.
+	entity, err = m.SpawnSprite(world, "dissolve", nil, 0, 0)
+	if err != nil {
+		t.Fatalf("SpawnSprite failed: %v", err)
+	}
+	for range 10 {
+		sys.Update(gameECS)
+	}
+	if !world.Valid(entity) {
+		t.Fatal("expected a sprite emitter without a sprite to stay alive")
+	}
+	SetEmitterSprite(world, entity, newTestSprite())
+	sys.Update(gameECS)
+	if got := Component.Get(world.Entry(entity)).ActiveCount; got != 2 {
+		t.Fatalf("expected the late sprite to release its 2 blocks, got %d", got)
+	}
+}
+
+func TestSpawnOneShotRejectsUnregisteredSprite(t *testing.T) {
+	preset := []byte(`
+name: dissolve
+emitter:
+  sprite:
+    image_from: enemy_frame
+    block_size: 2
+animation:
+  duration:
+    value: 0.5
+spawn:
+  interval: 1
+  particles_per_spawn: 1
+  max_particles: 1
+`)
+	m := NewParticleManager(nil, nil)
+	if err := m.PreloadFromBytes("dissolve", preset); err != nil {
+		t.Fatalf("PreloadFromBytes failed: %v", err)
+	}
+	world := donburi.NewWorld()
+	if err := m.SpawnOneShot(world, "dissolve", 0, 0, 1); err == nil || !strings.Contains(err.Error(), "enemy_frame") {
+		t.Fatalf("expected an unregistered sprite to be rejected, got %v", err)
+	}
+	if _, err := m.SpawnSprite(world, "dissolve", nil, 0, 0); err == nil {
+		t.Fatal("expected SpawnSprite without a sprite to reject an unregistered image_from")
+	}
+	config, err := NewConfigLoader().LoadConfigFromBytes(preset, "dissolve")
+	if err != nil {
+		t.Fatalf("LoadConfigFromBytes failed: %v", err)
+	}
+	if err := NewParticlesFromConfig(world, nil, nil, config, 0, 0); err == nil {
+		t.Fatal("expected NewParticlesFromConfig to reject a sprite it cannot resolve")
+	}
+	if count := donburi.NewQuery(filter.Contains(Component)).Count(world); count != 0 {
+		t.Fatalf("expected no entities to be created, got %d", count)
+	}
+
+	// A sprite handed to SpawnSprite replaces the reference.
+	if _, err := m.SpawnSprite(world, "dissolve", newTestSprite(), 0, 0); err != nil {
+		t.Fatalf("SpawnSprite failed: %v", err)
+	}
+}
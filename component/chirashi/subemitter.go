@@ -0,0 +1,224 @@
+package chirashi
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/yohamta/donburi"
+	"github.com/yohamta/donburi/ecs"
+)
+
+// maxSubEmitterDepth bounds how many generations of children a sub-emitter
+// chain may create, so a preset that (indirectly) references itself cannot
+// spawn entities forever.
+const maxSubEmitterDepth = 4
+
+// inheritedVelocityDamping is the decay rate (1/sec) of velocity inherited
+// by particles that follow a closed-form path.
+const inheritedVelocityDamping = 4
+
+// subEmitterSpawn is a child effect queued during System.Update. Children
+// are created after the entity loop so the query is never modified while it
+// is being iterated.
+type subEmitterSpawn struct {
+	host       *ParticleManager
+	params     SubEmitterParams
+	x, y       float32
+	r, g, b    float32
+	velX, velY float32
+	seed       uint64
+	depth      int
+	layer      ecs.LayerID
+}
+
+func buildSubEmitterParams(configs []SubEmitterConfig) []SubEmitterParams {
+	if len(configs) == 0 {
+		return nil
+	}
+	params := make([]SubEmitterParams, len(configs))
+	for i, config := range configs {
+		p := SubEmitterParams{
+			Preset:      config.Preset,
+			Trigger:     parseSubEmitterTrigger(config.Trigger),
+			Probability: 1,
+			LifeTime:    config.LifeTime,
+		}
+		if config.Probability != nil {
+			p.Probability = *config.Probability
+		}
+		if config.Inherit == nil {
+			p.InheritPosition = true
+		}
+		for _, inherit := range config.Inherit {
+			switch inherit {
+			case "position":
+				p.InheritPosition = true
+			case "color":
+				p.InheritColor = true
+			case "velocity":
+				p.InheritVelocity = true
+			}
+		}
+		params[i] = p
+	}
+	return params
+}
+
+func parseSubEmitterTrigger(trigger string) SubEmitterTrigger {
+	switch trigger {
+	case "birth":
+		return SubEmitterOnBirth
+	case "collision":
+		return SubEmitterOnCollision
+	default:
+		return SubEmitterOnDeath
+	}
+}
+
+// triggerSubEmitters queues every sub-emitter of data that listens for
+// trigger, carrying over only the particle state the entry inherits.
+// Probability rolls and child seeds come from the entity's random stream, so
+// seeded effects reproduce their children too.
+func (sys *System) triggerSubEmitters(data *SystemData, p *Instance, trigger SubEmitterTrigger) {
+	if data.subEmitterHost == nil || data.subEmitterDepth >= maxSubEmitterDepth {
+		return
+	}
+	for i := range data.SubEmitters {
+		sub := &data.SubEmitters[i]
+		if sub.Trigger != trigger {
+			continue
+		}
+		if sub.Probability < 1 && randFloat32(data.rng) >= sub.Probability {
+			continue
+		}
+		spawn := subEmitterSpawn{
+			host:   data.subEmitterHost,
+			params: *sub,
+			x:      data.EmitterX,
+			y:      data.EmitterY,
+			seed:   randUint64(data.rng),
+			depth:  data.subEmitterDepth + 1,
+			layer:  data.Layer,
+		}
+		if sub.InheritPosition {
+			spawn.x, spawn.y = p.CurrentX, p.CurrentY
+		}
+		if sub.InheritColor {
+			spawn.r, spawn.g, spawn.b, _ = particleTint(p, particleNormalizedTime(data, p))
+		}
+		if sub.InheritVelocity {
+			spawn.velX, spawn.velY = particleVelocity(data, p)
+		}
+		sys.pendingSubEmitters = append(sys.pendingSubEmitters, spawn)
+	}
+}
+
+// flushSubEmitters creates the child effects queued during this update.
+// Their presets were checked when the root effect was spawned, so a child
+// that still fails to create is skipped.
+func (sys *System) flushSubEmitters(world donburi.World) {
+	for i := range sys.pendingSubEmitters {
+		spawn := &sys.pendingSubEmitters[i]
+		_ = spawn.host.spawnSubEmitter(world, spawn)
+	}
+	sys.pendingSubEmitters = sys.pendingSubEmitters[:0]
+}
+
+// spawnSubEmitter creates one child effect through the same copy-and-create
+// path SpawnOneShot uses.
+func (m *ParticleManager) spawnSubEmitter(world donburi.World, spawn *subEmitterSpawn) error {
+	m.mutex.RLock()
+	baseConfig, exists := m.configs[spawn.params.Preset]
+	m.mutex.RUnlock()
+
+	if !exists {
+		return fmt.Errorf("sub-emitter preset '%s' not found", spawn.params.Preset)
+	}
+
+	config := copyConfig(baseConfig)
+	config.Spawn.IsLoop = false
+	if spawn.params.LifeTime > 0 {
+		config.Spawn.LifeTime = spawn.params.LifeTime
+	}
+	if config.Spawn.LifeTime <= 0 {
+		config.Spawn.LifeTime = 1
+	}
+	config.Seed = &spawn.seed
+
+	entity, err := m.createEntity(world, config, spawn.x, spawn.y)
+	if err != nil {
+		return err
+	}
+	data := Component.Get(world.Entry(entity))
+	data.subEmitterDepth = spawn.depth
+	// Children draw on their parent's layer, e.g. a firework behind
+	// characters bursts behind them too.
+	setRenderLayer(data, spawn.layer)
+	if spawn.params.InheritColor {
+		tintColorParams(&data.AnimParams.Color, spawn.r, spawn.g, spawn.b)
+	}
+	data.InheritedVelX = spawn.velX
+	data.InheritedVelY = spawn.velY
+	return nil
+}
+
+// inheritedVelocityDrift returns how far a unit inherited velocity has
+// carried a particle after elapsed seconds. The velocity decays at
+// inheritedVelocityDamping per second, so the drift levels off at
+// 1/inheritedVelocityDamping instead of growing for the whole lifetime.
+func inheritedVelocityDrift(elapsed float32) float32 {
+	if elapsed <= 0 {
+		return 0
+	}
+	return float32(-math.Expm1(-inheritedVelocityDamping*float64(elapsed))) / inheritedVelocityDamping
+}
+
+// tintColorParams multiplies every color of clr by (r, g, b).
+func tintColorParams(clr *ColorParams, r, g, b float32) {
+	clr.Enabled = true
+	clr.StartR *= r
+	clr.StartG *= g
+	clr.StartB *= b
+	clr.EndR *= r
+	clr.EndG *= g
+	clr.EndB *= b
+	clr.Start2R *= r
+	clr.Start2G *= g
+	clr.Start2B *= b
+	clr.End2R *= r
+	clr.End2G *= g
+	clr.End2B *= b
+	if clr.Gradient != nil {
+		clr.Gradient = clr.Gradient.tinted(r, g, b)
+	}
+	if len(clr.Palette) > 0 {
+		palette := make([][3]float32, len(clr.Palette))
+		for i, c := range clr.Palette {
+			palette[i] = [3]float32{c[0] * r, c[1] * g, c[2] * b}
+		}
+		clr.Palette = palette
+	}
+}
+
+// particleVelocity returns a particle's velocity in units/sec. Closed-form
+// particles estimate it from their cached position history; right after
+// spawn there is no history yet, so the base path is sampled one reference
+// frame ahead instead.
+func particleVelocity(data *SystemData, p *Instance) (float32, float32) {
+	if p.Integrated {
+		return p.VelX, p.VelY
+	}
+	if dt := p.CurrentPosTime - p.PrevPosTime; dt > 0 {
+		return (p.CurrentX - p.PrevX) / dt, (p.CurrentY - p.PrevY) / dt
+	}
+	if p.Duration <= 0 {
+		return 0, 0
+	}
+	elapsed := data.CurrentTime - p.SpawnTime + defaultDeltaTime
+	normalizedT := elapsed / p.Duration
+	if normalizedT > 1 {
+		normalizedT = 1
+	}
+	x, y := evaluateParticleBasePosition(data, p, elapsed, ApplyEasing(normalizedT, p.PositionEasing))
+	return (x - p.CurrentX) / defaultDeltaTime, (y - p.CurrentY) / defaultDeltaTime
+}
@@ -0,0 +1,420 @@
+package chirashi
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// SVGPathOptions controls how SVG path data is converted into polylines.
+type SVGPathOptions struct {
+	// Scale multiplies every coordinate after centering; 0 means 1.
+	Scale float32
+	// Center moves the center of the path's bounding box to the emitter origin.
+	Center bool
+	// CurveSteps is the number of segments per Q/C/S/T curve and per quarter
+	// turn of an A arc; 0 uses the polyline default (12).
+	CurveSteps int
+}
+
+// ParseSVGPath converts SVG path data (the `d` attribute) into polylines,
+// one per subpath. Subpaths ending in Z are closed; a closed subpath that
+// lies inside another closed subpath is added to it as a hole (even-odd),
+// so letters such as "O" fill correctly. M, L, H, V, Q, T, C, S, A and Z
+// are supported in absolute and relative form.
+func ParseSVGPath(d string, options SVGPathOptions) ([]EmitterVectorPolylineConfig, error) {
+	subpaths, err := flattenSVGPath(d, options.CurveSteps)
+	if err != nil {
+		return nil, err
+	}
+	if len(subpaths) == 0 {
+		return nil, fmt.Errorf("svg path has no drawable subpaths")
+	}
+	transformSVGSubpaths(subpaths, options)
+	return nestOutlineSubpaths(subpaths), nil
+}
+
+// NewSVGPathVector builds a polyline vector emitter from SVG path data.
+// placement is "surface" for outlines or "fill" for the interior of closed
+// subpaths.
+func NewSVGPathVector(d string, placement string, options SVGPathOptions) (*EmitterVectorConfig, error) {
+	polylines, err := ParseSVGPath(d, options)
+	if err != nil {
+		return nil, err
+	}
+	vector := &EmitterVectorConfig{
+		Type:      "polyline",
+		Placement: placement,
+		Polyline:  &polylines[0],
+	}
+	if len(polylines) > 1 {
+		vector.Polylines = polylines[1:]
+	}
+	return vector, nil
+}
+
+// svgPathParser reads commands, numbers and arc flags from path data.
+type svgPathParser struct {
+	d   string
+	pos int
+}
+
+func (p *svgPathParser) skipSeparators() {
+	for p.pos < len(p.d) {
+		switch p.d[p.pos] {
+		case ' ', '\t', '\n', '\r', '\f', ',':
+			p.pos++
+		default:
+			return
+		}
+	}
+}
+
+// command returns the next command letter, or 0 when a number follows.
+func (p *svgPathParser) command() (byte, bool) {
+	p.skipSeparators()
+	if p.pos >= len(p.d) {
+		return 0, false
+	}
+	c := p.d[p.pos]
+	if (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') {
+		p.pos++
+		return c, true
+	}
+	return 0, true
+}
+
+func (p *svgPathParser) number() (float64, error) {
+	p.skipSeparators()
+	start := p.pos
+	if p.pos < len(p.d) && (p.d[p.pos] == '-' || p.d[p.pos] == '+') {
+		p.pos++
+	}
+	digits := false
+	for p.pos < len(p.d) && p.d[p.pos] >= '0' && p.d[p.pos] <= '9' {
+		p.pos++
+		digits = true
+	}
+	if p.pos < len(p.d) && p.d[p.pos] == '.' {
+		p.pos++
+		for p.pos < len(p.d) && p.d[p.pos] >= '0' && p.d[p.pos] <= '9' {
+			p.pos++
+			digits = true
+		}
+	}
+	if digits && p.pos < len(p.d) && (p.d[p.pos] == 'e' || p.d[p.pos] == 'E') {
+		exp := p.pos + 1
+		if exp < len(p.d) && (p.d[exp] == '-' || p.d[exp] == '+') {
+			exp++
+		}
+		if exp < len(p.d) && p.d[exp] >= '0' && p.d[exp] <= '9' {
+			p.pos = exp
+			for p.pos < len(p.d) && p.d[p.pos] >= '0' && p.d[p.pos] <= '9' {
+				p.pos++
+			}
+		}
+	}
+	if !digits {
+		return 0, fmt.Errorf("svg path: expected number at offset %d", start)
+	}
+	return strconv.ParseFloat(p.d[start:p.pos], 64)
+}
+
+// flag reads an arc flag, which may be written without a separator ("011").
+func (p *svgPathParser) flag() (bool, error) {
+	p.skipSeparators()
+	if p.pos < len(p.d) {
+		switch p.d[p.pos] {
+		case '0':
+			p.pos++
+			return false, nil
+		case '1':
+			p.pos++
+			return true, nil
+		}
+	}
+	return false, fmt.Errorf("svg path: expected arc flag at offset %d", p.pos)
+}
+
+func (p *svgPathParser) numbers(n int) ([]float64, error) {
+	values := make([]float64, n)
+	for i := range values {
+		v, err := p.number()
+		if err != nil {
+			return nil, err
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+func flattenSVGPath(d string, curveSteps int) ([]outlineSubpath, error) {
+	if curveSteps <= 0 {
+		curveSteps = defaultEmitterVectorCurveSteps
+	}
+	p := &svgPathParser{d: d}
+	var subpaths []outlineSubpath
+	var current *outlineSubpath
+	var x, y, startX, startY float64
+	// Reflected control point for S and T; valid only right after C/S or Q/T.
+	var ctrlX, ctrlY float64
+	var prevCmd byte
+	var cmd byte
+
+	lineTo := func(nx, ny float64) {
+		if current == nil {
+			subpaths = append(subpaths, outlineSubpath{points: []EmitterVectorPoint{{X: float32(x), Y: float32(y)}}})
+			current = &subpaths[len(subpaths)-1]
+		}
+		current.points = append(current.points, EmitterVectorPoint{X: float32(nx), Y: float32(ny)})
+		x, y = nx, ny
+	}
+
+	for {
+		letter, ok := p.command()
+		if !ok {
+			break
+		}
+		if letter != 0 {
+			cmd = letter
+			if prevCmd == 0 && cmd != 'M' && cmd != 'm' {
+				return nil, fmt.Errorf("svg path: path data must start with M")
+			}
+		} else if cmd == 0 {
+			return nil, fmt.Errorf("svg path: path data must start with M")
+		} else if cmd == 'Z' || cmd == 'z' {
+			return nil, fmt.Errorf("svg path: unexpected number after Z at offset %d", p.pos)
+		}
+
+		rel := cmd >= 'a'
+		ox, oy := 0.0, 0.0
+		if rel {
+			ox, oy = x, y
+		}
+		switch cmd {
+		case 'M', 'm':
+			v, err := p.numbers(2)
+			if err != nil {
+				return nil, err
+			}
+			x, y = ox+v[0], oy+v[1]
+			startX, startY = x, y
+			subpaths = append(subpaths, outlineSubpath{points: []EmitterVectorPoint{{X: float32(x), Y: float32(y)}}})
+			current = &subpaths[len(subpaths)-1]
+			// Further pairs are implicit line-tos.
+			if rel {
+				cmd = 'l'
+			} else {
+				cmd = 'L'
+			}
+		case 'L', 'l':
+			v, err := p.numbers(2)
+			if err != nil {
+				return nil, err
+			}
+			lineTo(ox+v[0], oy+v[1])
+		case 'H', 'h':
+			v, err := p.number()
+			if err != nil {
+				return nil, err
+			}
+			lineTo(ox+v, y)
+		case 'V', 'v':
+			v, err := p.number()
+			if err != nil {
+				return nil, err
+			}
+			lineTo(x, oy+v)
+		case 'Q', 'q', 'T', 't':
+			var cx, cy float64
+			var end []float64
+			var err error
+			if cmd == 'Q' || cmd == 'q' {
+				v, err := p.numbers(4)
+				if err != nil {
+					return nil, err
+				}
+				cx, cy = ox+v[0], oy+v[1]
+				end = v[2:]
+			} else {
+				cx, cy = x, y
+				if prevCmd == 'Q' || prevCmd == 'T' {
+					cx, cy = 2*x-ctrlX, 2*y-ctrlY
+				}
+				if end, err = p.numbers(2); err != nil {
+					return nil, err
+				}
+			}
+			x0, y0 := x, y
+			ex, ey := ox+end[0], oy+end[1]
+			for step := 1; step <= curveSteps; step++ {
+				t := float64(step) / float64(curveSteps)
+				u := 1 - t
+				lineTo(u*u*x0+2*u*t*cx+t*t*ex, u*u*y0+2*u*t*cy+t*t*ey)
+			}
+			ctrlX, ctrlY = cx, cy
+		case 'C', 'c', 'S', 's':
+			var c1x, c1y, c2x, c2y float64
+			var end []float64
+			if cmd == 'C' || cmd == 'c' {
+				v, err := p.numbers(6)
+				if err != nil {
+					return nil, err
+				}
+				c1x, c1y = ox+v[0], oy+v[1]
+				c2x, c2y = ox+v[2], oy+v[3]
+				end = v[4:]
+			} else {
+				v, err := p.numbers(4)
+				if err != nil {
+					return nil, err
+				}
+				c1x, c1y = x, y
+				if prevCmd == 'C' || prevCmd == 'S' {
+					c1x, c1y = 2*x-ctrlX, 2*y-ctrlY
+				}
+				c2x, c2y = ox+v[0], oy+v[1]
+				end = v[2:]
+			}
+			x0, y0 := x, y
+			ex, ey := ox+end[0], oy+end[1]
+			for step := 1; step <= curveSteps; step++ {
+				t := float64(step) / float64(curveSteps)
+				u := 1 - t
+				lineTo(
+					u*u*u*x0+3*u*u*t*c1x+3*u*t*t*c2x+t*t*t*ex,
+					u*u*u*y0+3*u*u*t*c1y+3*u*t*t*c2y+t*t*t*ey,
+				)
+			}
+			ctrlX, ctrlY = c2x, c2y
+		case 'A', 'a':
+			v, err := p.numbers(3)
+			if err != nil {
+				return nil, err
+			}
+			large, err := p.flag()
+			if err != nil {
+				return nil, err
+			}
+			sweep, err := p.flag()
+			if err != nil {
+				return nil, err
+			}
+			end, err := p.numbers(2)
+			if err != nil {
+				return nil, err
+			}
+			for _, pt := range flattenSVGArc(x, y, v[0], v[1], v[2], large, sweep, ox+end[0], oy+end[1], curveSteps) {
+				lineTo(pt[0], pt[1])
+			}
+		case 'Z', 'z':
+			if current != nil {
+				current.closed = true
+			}
+			current = nil
+			x, y = startX, startY
+		default:
+			return nil, fmt.Errorf("svg path: unsupported command %q", cmd)
+		}
+		prevCmd = cmd
+		if prevCmd >= 'a' {
+			prevCmd -= 'a' - 'A'
+		}
+	}
+
+	out := subpaths[:0]
+	for _, sub := range subpaths {
+		sub.points = dedupeOutlinePoints(sub.points, sub.closed)
+		if len(sub.points) < 2 || sub.closed && len(sub.points) < 3 {
+			continue
+		}
+		out = append(out, sub)
+	}
+	return out, nil
+}
+
+// flattenSVGArc converts an SVG elliptical arc to points after the start
+// point, following the endpoint-to-center conversion in SVG 1.1 F.6.5.
+func flattenSVGArc(x1, y1, rx, ry, rotationDeg float64, large, sweep bool, x2, y2 float64, stepsPerQuarter int) [][2]float64 {
+	if x1 == x2 && y1 == y2 {
+		return nil
+	}
+	rx, ry = math.Abs(rx), math.Abs(ry)
+	if rx == 0 || ry == 0 {
+		return [][2]float64{{x2, y2}}
+	}
+	sinPhi, cosPhi := math.Sincos(rotationDeg * math.Pi / 180)
+	dx, dy := (x1-x2)/2, (y1-y2)/2
+	x1p := cosPhi*dx + sinPhi*dy
+	y1p := -sinPhi*dx + cosPhi*dy
+
+	// Scale up radii that are too small to reach the end point.
+	if lambda := x1p*x1p/(rx*rx) + y1p*y1p/(ry*ry); lambda > 1 {
+		s := math.Sqrt(lambda)
+		rx *= s
+		ry *= s
+	}
+	num := rx*rx*ry*ry - rx*rx*y1p*y1p - ry*ry*x1p*x1p
+	den := rx*rx*y1p*y1p + ry*ry*x1p*x1p
+	coef := 0.0
+	if den > 0 && num > 0 {
+		coef = math.Sqrt(num / den)
+	}
+	if large == sweep {
+		coef = -coef
+	}
+	cxp := coef * rx * y1p / ry
+	cyp := -coef * ry * x1p / rx
+	cx := cosPhi*cxp - sinPhi*cyp + (x1+x2)/2
+	cy := sinPhi*cxp + cosPhi*cyp + (y1+y2)/2
+
+	theta1 := math.Atan2((y1p-cyp)/ry, (x1p-cxp)/rx)
+	theta2 := math.Atan2((-y1p-cyp)/ry, (-x1p-cxp)/rx)
+	delta := theta2 - theta1
+	if sweep && delta < 0 {
+		delta += 2 * math.Pi
+	} else if !sweep && delta > 0 {
+		delta -= 2 * math.Pi
+	}
+
+	steps := int(math.Ceil(math.Abs(delta) / (math.Pi / 2) * float64(stepsPerQuarter)))
+	if steps < 1 {
+		steps = 1
+	}
+	points := make([][2]float64, 0, steps)
+	for i := 1; i < steps; i++ {
+		sinT, cosT := math.Sincos(theta1 + delta*float64(i)/float64(steps))
+		points = append(points, [2]float64{
+			cx + rx*cosT*cosPhi - ry*sinT*sinPhi,
+			cy + rx*cosT*sinPhi + ry*sinT*cosPhi,
+		})
+	}
+	// End exactly on the requested point.
+	return append(points, [2]float64{x2, y2})
+}
+
+func transformSVGSubpaths(subpaths []outlineSubpath, options SVGPathOptions) {
+	scale := options.Scale
+	if scale == 0 {
+		scale = 1
+	}
+	var offsetX, offsetY float32
+	if options.Center {
+		minX, minY := float32(math.Inf(1)), float32(math.Inf(1))
+		maxX, maxY := float32(math.Inf(-1)), float32(math.Inf(-1))
+		for _, sub := range subpaths {
+			for _, pt := range sub.points {
+				minX, maxX = min(minX, pt.X), max(maxX, pt.X)
+				minY, maxY = min(minY, pt.Y), max(maxY, pt.Y)
+			}
+		}
+		offsetX = (minX + maxX) / 2
+		offsetY = (minY + maxY) / 2
+	}
+	for _, sub := range subpaths {
+		for i := range sub.points {
+			sub.points[i].X = (sub.points[i].X - offsetX) * scale
+			sub.points[i].Y = (sub.points[i].Y - offsetY) * scale
+		}
+	}
+}
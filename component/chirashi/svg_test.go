@@ -0,0 +1,114 @@
+package chirashi
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestParseSVGPathCommands(t *testing.T) {
+	tests := []struct {
+		name   string
+		d      string
+		closed bool
+		want   []EmitterVectorPoint
+	}{
+		{
+			name:   "absolute lines",
+			d:      "M0 0 H10 V10 L0 10 Z",
+			closed: true,
+			want:   []EmitterVectorPoint{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 0, Y: 10}},
+		},
+		{
+			name: "relative with implicit line-to and compact numbers",
+			d:    "m10,10 5-5.5.5.5e1h-1",
+			want: []EmitterVectorPoint{{X: 10, Y: 10}, {X: 15, Y: 4.5}, {X: 15.5, Y: 9.5}, {X: 14.5, Y: 9.5}},
+		},
+	}
+	for _, tt := range tests {
+		polylines, err := ParseSVGPath(tt.d, SVGPathOptions{})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if len(polylines) != 1 || polylines[0].Closed != tt.closed {
+			t.Fatalf("%s: got %+v", tt.name, polylines)
+		}
+		got := polylines[0].Points
+		if len(got) != len(tt.want) {
+			t.Fatalf("%s: got points %v, want %v", tt.name, got, tt.want)
+		}
+		for i := range got {
+			if !nearFloat(got[i].X, tt.want[i].X) || !nearFloat(got[i].Y, tt.want[i].Y) {
+				t.Fatalf("%s: got points %v, want %v", tt.name, got, tt.want)
+			}
+		}
+	}
+}
+
+func TestParseSVGPathCurvesEndOnTarget(t *testing.T) {
+	polylines, err := ParseSVGPath("M0 0 Q10 10 20 0 T40 0 C40 10 50 10 50 0 S60 -10 60 0 A10 10 0 0 1 80 0", SVGPathOptions{CurveSteps: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	points := polylines[0].Points
+	// 4 points per Q/T/C/S segment and 2 quarter turns of 4 for the arc.
+	if want := 1 + 4*4 + 8; len(points) != want {
+		t.Fatalf("point count got %d, want %d", len(points), want)
+	}
+	for _, i := range []int{4, 8, 12, 16, 24} {
+		if !nearFloat(points[i].Y, 0) {
+			t.Fatalf("segment end %d got %v, want y=0", i, points[i])
+		}
+	}
+	// The arc is a half circle of radius 10 around (70, 0).
+	for _, pt := range points[17:] {
+		if r := math.Hypot(float64(pt.X-70), float64(pt.Y)); math.Abs(r-10) > 1e-3 {
+			t.Fatalf("arc point %v is %v from the center, want 10", pt, r)
+		}
+	}
+}
+
+func TestParseSVGPathNestsHolesAndTransforms(t *testing.T) {
+	d := "M0 0H100V100H0Z M25 25H75V75H25Z M40 40H60V60H40Z M200 0L300 0"
+	polylines, err := ParseSVGPath(d, SVGPathOptions{Center: true, Scale: 0.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(polylines) != 3 {
+		t.Fatalf("polyline count got %d, want 3 (frame, island, open line)", len(polylines))
+	}
+	if len(polylines[0].Holes) != 1 || len(polylines[1].Holes) != 0 || polylines[2].Closed {
+		t.Fatalf("unexpected nesting: %+v", polylines)
+	}
+	// Bounds (0,0)-(300,100) are centered on (150,50) and halved.
+	if first := polylines[0].Points[0]; !nearFloat(first.X, -75) || !nearFloat(first.Y, -25) {
+		t.Fatalf("first point got %v, want (-75, -25)", first)
+	}
+
+	vector, err := NewSVGPathVector(d, "fill", SVGPathOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := validParticleConfigForTest()
+	cfg.Emitter.Vector = vector
+	if err := NewConfigLoader().validateConfig(cfg); err == nil || !strings.Contains(err.Error(), "emitter.vector.polylines[1]") {
+		t.Fatalf("fill with an open subpath should be rejected, got %v", err)
+	}
+	vector.Polylines = vector.Polylines[:1]
+	if err := NewConfigLoader().validateConfig(cfg); err != nil {
+		t.Fatalf("closed SVG subpaths should validate for fill, got %v", err)
+	}
+	params := buildEmitterVectorParams(vector)
+	area := params.Polyline.TotalArea + params.Polylines[0].TotalArea
+	if want := float32(100*100 - 50*50 + 20*20); !nearFloat(area, want) {
+		t.Fatalf("fill area got %v, want %v", area, want)
+	}
+}
+
+func TestParseSVGPathRejectsMalformedData(t *testing.T) {
+	for _, d := range []string{"", "L10 10", "M0 0 X10", "M0 0 L10", "M0 0 A5 5 0 2 0 10 0"} {
+		if _, err := ParseSVGPath(d, SVGPathOptions{}); err == nil {
+			t.Fatalf("expected %q to be rejected", d)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"math"
 	"math/rand/v2"
 	"runtime"
+	"sort"
 	"sync"
 	"time"
 
@@ -18,6 +19,15 @@ const (
 	// indices (65535); the largest multiple of 4 below that is 65532.
 	maxParticleBatchVertices = 65532
 
+	// referenceTPS is the tick rate spawn.interval and spawn.life_time are
+	// authored against.
+	referenceTPS = float32(60)
+
+	// minHeadingSpeedSq is the squared speed (units/sec) below which a
+	// velocity-aligned particle keeps its last heading instead of snapping
+	// to an arbitrary angle.
+	minHeadingSpeedSq = float32(1e-4)
+
 	defaultDeltaTime        = float32(1.0 / 60.0)
 	flowSeedRange           = float32(32)
 	flowSeedHalfRange       = flowSeedRange / 2
@@ -39,37 +49,66 @@ const (
 
 // System manages GPU-based particle systems with batch rendering
 type System struct {
-	query *donburi.Query
-	cnt   int
+	query           *donburi.Query
+	forceFieldQuery *donburi.Query
+	timeScale       float32
+	camera          Camera
+
+	pendingSubEmitters []subEmitterSpawn
+	drawEntries        []layerDrawEntry
+	forceFields        []ForceFieldData
+	expiredForceFields []donburi.Entity
 }
 
 // NewSystem creates a particle ECS system that updates and draws particle entities.
 func NewSystem() *System {
 	return &System{
-		query: donburi.NewQuery(filter.Contains(Component)),
-		cnt:   0,
+		query:           donburi.NewQuery(filter.Contains(Component)),
+		forceFieldQuery: donburi.NewQuery(filter.Contains(ForceField)),
+		timeScale:       1,
 	}
 }
 
+// SetTimeScale sets the multiplier applied to every particle entity's time
+// step, on top of each entity's own TimeScale. 0 pauses all effects (e.g. a
+// pause menu); negative values are clamped to 0.
+func (sys *System) SetTimeScale(scale float32) {
+	sys.timeScale = clampTimeScale(scale)
+}
+
+// TimeScale returns the system-wide time multiplier.
+func (sys *System) TimeScale() float32 {
+	return sys.timeScale
+}
+
 // Update advances particle simulation for all entities with the particle component.
 func (sys *System) Update(ecs *ecs.ECS) {
-	sys.cnt++
 	tps := ebiten.TPS()
-	deltaTime := defaultDeltaTime
+	baseDeltaTime := defaultDeltaTime
 	if tps > 0 {
-		deltaTime = float32(1.0 / float64(tps))
+		baseDeltaTime = float32(1.0 / float64(tps))
 	}
+	baseDeltaTime *= sys.timeScale
+	sys.collectForceFields(ecs.World, baseDeltaTime)
 
 	for entry := range sys.query.Iter(ecs.World) {
 		data := Component.Get(entry)
+		data.forceFields = sys.forceFields
 
 		startTime := time.Now()
+		deltaTime := baseDeltaTime * clampTimeScale(data.TimeScale)
 
 		// Update current time
 		data.CurrentTime += deltaTime
 
+		if entry.HasComponent(Attachment) && !followAttachment(ecs.World, Attachment.Get(entry), data) {
+			ecs.World.Remove(entry.Entity())
+			continue
+		}
+		updateEmitterMotion(data)
+
 		// Spawn new particles
-		sys.spawn(data)
+		sys.spawn(data, deltaTime)
 
 		// Deactivate expired particles
 		sys.updateParticles(data, deltaTime)
@@ -81,49 +120,68 @@ func (sys *System) Update(ecs *ecs.ECS) {
 
 		// Handle lifetime
 		if !data.IsLoop {
-			if data.LifeTime > 0 {
-				data.LifeTime--
-			}
-			if data.LifeTime <= 0 && data.ActiveCount == 0 && !trailHasVisiblePoints(data) {
+			advanceLifeTime(data, deltaTime)
+			if data.LifeTime <= 0 && data.ActiveCount == 0 && !trailHasVisiblePoints(data) && !spriteBlocksPending(data) {
 				ecs.World.Remove(entry.Entity())
 			}
 		}
 	}
-}
 
-func (sys *System) spawn(data *SystemData) {
-	if !data.IsLoop && data.LifeTime <= 0 {
-		return
-	}
-	if data.SpawnInterval <= 0 || sys.cnt%data.SpawnInterval != 0 {
-		return
-	}
+	sys.flushSubEmitters(ecs.World)
+}
 
+func (sys *System) spawn(data *SystemData, deltaTime float32) {
 	emissionScale := clampEmissionScale(data.EmissionScale)
-	if emissionScale <= 0 {
+	// The emitter path is tracked every update so a paused or capped
+	// emitter does not release its backlog later.
+	path := emitterPathForStep(data, emissionScale)
+	if !data.IsLoop && data.LifeTime <= 0 && !spriteBlocksPending(data) {
 		return
 	}
+	var steadyCount int
+	if data.Sprite.Enabled {
+		// Sprite emitters release their pixel blocks instead of following
+		// the rate, interval and bursts.
+		path.count = 0
+		steadyCount = spriteEmission(data)
+		if steadyCount <= 0 || emissionScale <= 0 {
+			return
+		}
+	} else {
+		emitted := emissionForStep(data, deltaTime) + float32(burstEmission(data))
+		if emitted <= 0 && path.count == 0 {
+			return
+		}
 
-	maxParticles := scaledMaxParticles(data.MaxParticles, emissionScale)
-	if data.ActiveCount >= maxParticles {
-		return
-	}
+		if emissionScale <= 0 {
+			return
+		}
+
+		if data.ActiveCount >= scaledMaxParticles(data.MaxParticles, emissionScale) {
+			return
+		}
 
-	// Preserve fractional emission so low scales still work for presets that
-	// spawn one particle at a time. For example, scale 0.5 emits one particle
-	// every other configured spawn tick instead of rounding down to zero.
-	data.emissionRemainder += float32(data.ParticlesPerSpawn) * emissionScale
-	particlesToSpawn := int(data.emissionRemainder)
+		// Preserve fractional emission so low scales and low rates still work
+		// for presets that spawn one particle at a time. For example, scale 0.5
+		// emits one particle every other configured spawn tick instead of
+		// rounding down to zero.
+		data.emissionRemainder += emitted * emissionScale
+		steadyCount = int(data.emissionRemainder)
+		data.emissionRemainder -= float32(steadyCount)
+	}
+	maxParticles := scaledMaxParticles(data.MaxParticles, emissionScale)
+	particlesToSpawn := steadyCount + path.count
 	if particlesToSpawn <= 0 {
 		return
 	}
-	data.emissionRemainder -= float32(particlesToSpawn)
 
 	dur := &data.AnimParams.Duration
 	pos := &data.AnimParams.Position
 	app := &data.AnimParams.Appearance
 	clr := &data.AnimParams.Color
 	currentTime := data.CurrentTime
+	rng := data.rng
+	transform := currentEmitterTransform(data)
 
 	for i := 0; i < particlesToSpawn && data.ActiveCount < maxParticles; i++ {
 		if data.ActiveCount >= len(data.ParticlePool) {
@@ -134,27 +192,73 @@ func (sys *System) spawn(data *SystemData) {
 		particle := &data.ParticlePool[data.ActiveCount]
 		particle.TrailPoints = particle.TrailPoints[:0]
 
-		spawnX, spawnY := sampleEmitterPosition(data.EmitterX, data.EmitterY, data.EmitterShape, data.EmitterVector, i, particlesToSpawn)
+		originX, originY := data.EmitterX, data.EmitterY
+		if i >= steadyCount {
+			// Distance-based particles are spaced along the emitter's path.
+			originX, originY = path.point(i - steadyCount)
+		}
+		var block *SpriteBlock
+		var spawnX, spawnY float32
+		if data.Sprite.Enabled {
+			block = &data.Sprite.Blocks[data.Sprite.emitted]
+			data.Sprite.emitted++
+			spawnX, spawnY = originX+block.X, originY+block.Y
+		} else {
+			spawnX, spawnY = sampleEmitterPosition(rng, originX, originY, data.EmitterShape, data.EmitterVector, i, particlesToSpawn)
+		}
+		if transform.enabled {
+			offsetX, offsetY := transform.apply(spawnX-originX, spawnY-originY)
+			spawnX, spawnY = originX+offsetX, originY+offsetY
+		}
 
 		// Initialize particle with randomized values
 		particle.SpawnTime = currentTime
 		particle.Duration = dur.Base
 		if dur.Range > 0 {
-			particle.Duration += (rand.Float32()*2 - 1) * dur.Range
+			particle.Duration += (randFloat32(rng)*2 - 1) * dur.Range
 		}
 
 		// Position
+		particle.Integrated = false
+		particle.Stuck = false
+		particle.Collided = false
+		particle.InContact = false
+		particle.VelX = 0
+		particle.VelY = 0
 		switch {
+		case pos.UsePhysics:
+			// Physics mode: launch from the cone, then integrate per update.
+			angle := rangeFloat32(rng, pos.AngleMin, pos.AngleMax) + transform.rotation
+			sinA, cosA := fastSincos(angle)
+			dist := rangeFloat32(rng, pos.DistMin, pos.DistMax) * transform.scale
+			speed := rangeFloat32(rng, pos.SpeedMin, pos.SpeedMax) * transform.scale
+			particle.StartX = spawnX + cosA*dist
+			particle.StartY = spawnY + sinA*dist
+			particle.EndX = particle.StartX
+			particle.EndY = particle.StartY
+			particle.HasAttractor = false
+			particle.HasPolarVelocity = false
+			particle.Integrated = true
+			particle.PosX = particle.StartX
+			particle.PosY = particle.StartY
+			velX := rangeFloat32(rng, pos.VelXMin, pos.VelXMax)
+			velY := rangeFloat32(rng, pos.VelYMin, pos.VelYMax)
+			velX, velY = transform.apply(velX, velY)
+			particle.VelX = cosA*speed + velX
+			particle.VelY = sinA*speed + velY
 		case pos.UseAttractor:
 			// Attractor mode: quadratic bezier P0=emitter, P1=random control, P2=AttractorX/Y
 			// EndX/Y are unused; attractor coords are read from SystemData each frame.
 			particle.StartX = spawnX
 			particle.StartY = spawnY
-			particle.ControlX = spawnX + rangeFloat32(pos.ControlXMin, pos.ControlXMax)
-			particle.ControlY = spawnY + rangeFloat32(pos.ControlYMin, pos.ControlYMax)
+			controlX := rangeFloat32(rng, pos.ControlXMin, pos.ControlXMax)
+			controlY := rangeFloat32(rng, pos.ControlYMin, pos.ControlYMax)
+			controlX, controlY = transform.apply(controlX, controlY)
+			particle.ControlX = spawnX + controlX
+			particle.ControlY = spawnY + controlY
 			particle.HasAttractor = true
 		case pos.UsePolar:
-			angle := rangeFloat32(pos.AngleMin, pos.AngleMax)
+			angle := rangeFloat32(rng, pos.AngleMin, pos.AngleMax) + transform.rotation
 			sinA, cosA := fastSincos(angle)
 			particle.StartX = spawnX
 			particle.StartY = spawnY
@@ -164,33 +268,62 @@ func (sys *System) spawn(data *SystemData) {
 				particle.DirX = cosA
 				particle.DirY = sinA
 				particle.StartAngle = angle
-				particle.SpawnDist = rangeFloat32(pos.DistMin, pos.DistMax)
-				particle.Speed = rangeFloat32(pos.SpeedMin, pos.SpeedMax)
-				particle.AngularSpeed = rangeFloat32(pos.AngularSpeedMin, pos.AngularSpeedMax)
+				particle.SpawnDist = rangeFloat32(rng, pos.DistMin, pos.DistMax) * transform.scale
+				particle.Speed = rangeFloat32(rng, pos.SpeedMin, pos.SpeedMax) * transform.scale
+				particle.AngularSpeed = rangeFloat32(rng, pos.AngularSpeedMin, pos.AngularSpeedMax)
 				particle.HasPolarVelocity = true
 			} else {
 				// Legacy lerp mode: convert to cartesian at spawn time
-				dist := rangeFloat32(pos.DistMin, pos.DistMax)
+				dist := rangeFloat32(rng, pos.DistMin, pos.DistMax) * transform.scale
 				particle.EndX = spawnX + dist*cosA
 				particle.EndY = spawnY + dist*sinA
 				particle.HasPolarVelocity = false
 			}
 		default:
 			// Cartesian mode
-			particle.StartX = spawnX + rangeFloat32(pos.StartXMin, pos.StartXMax)
-			particle.EndX = spawnX + rangeFloat32(pos.EndXMin, pos.EndXMax)
-			particle.StartY = spawnY + rangeFloat32(pos.StartYMin, pos.StartYMax)
-			particle.EndY = spawnY + rangeFloat32(pos.EndYMin, pos.EndYMax)
+			startX := rangeFloat32(rng, pos.StartXMin, pos.StartXMax)
+			endX := rangeFloat32(rng, pos.EndXMin, pos.EndXMax)
+			startY := rangeFloat32(rng, pos.StartYMin, pos.StartYMax)
+			endY := rangeFloat32(rng, pos.EndYMin, pos.EndYMax)
+			startX, startY = transform.apply(startX, startY)
+			endX, endY = transform.apply(endX, endY)
+			particle.StartX = spawnX + startX
+			particle.EndX = spawnX + endX
+			particle.StartY = spawnY + startY
+			particle.EndY = spawnY + endY
 			particle.HasAttractor = false
 		}
+		if block != nil && data.Sprite.Assemble {
+			// Assembling particles travel the same path backwards and come
+			// to rest on their block.
+			particle.StartX, particle.EndX = particle.EndX, particle.StartX
+			particle.StartY, particle.EndY = particle.EndY, particle.StartY
+		}
+		// Integrated particles take inherited velocity directly; paths
+		// drift with it instead.
+		particle.HasInheritedVel = false
+		if data.InheritedVelX != 0 || data.InheritedVelY != 0 {
+			if particle.Integrated {
+				particle.VelX += data.InheritedVelX
+				particle.VelY += data.InheritedVelY
+			} else {
+				particle.HasInheritedVel = true
+				particle.InheritedVelX = data.InheritedVelX
+				particle.InheritedVelY = data.InheritedVelY
+			}
+		}
 		particle.CurrentX = particle.StartX
 		particle.CurrentY = particle.StartY
 		particle.CurrentPosValid = true
 		particle.CurrentPosTime = currentTime
+		particle.PrevX = particle.CurrentX
+		particle.PrevY = particle.CurrentY
+		particle.PrevPosTime = currentTime
 		particle.PositionEasing = pos.Easing
 		particle.HasFlow = pos.HasFlow
+		particle.RandState = randUint64(rng)
 		if pos.HasFlow {
-			particle.FlowGain = rangeFloat32(pos.FlowStrengthMin, pos.FlowStrengthMax)
+			particle.FlowGain = rangeFloat32(rng, pos.FlowStrengthMin, pos.FlowStrengthMax)
 			resetParticleFlowState(particle, true)
 		} else {
 			resetParticleFlowState(particle, false)
@@ -203,39 +336,158 @@ func (sys *System) spawn(data *SystemData) {
 		particle.StartScale = app.StartScale
 		particle.EndScale = app.EndScale
 		particle.ScaleEasing = app.ScaleEasing
+		particle.StartScaleX = app.StartScaleX
+		particle.EndScaleX = app.EndScaleX
+		particle.ScaleXEasing = app.ScaleXEasing
+		particle.StartScaleY = app.StartScaleY
+		particle.EndScaleY = app.EndScaleY
+		particle.ScaleYEasing = app.ScaleYEasing
 		particle.StartRotation = app.StartRotation
 		particle.EndRotation = app.EndRotation
 		particle.RotationEasing = app.RotationEasing
 
 		// Color
-		assignParticleColor(particle, clr)
+		assignParticleColor(particle, clr, rng)
+		particle.HasTint = block != nil
+		if block != nil {
+			particle.TintR, particle.TintG, particle.TintB, particle.TintA = block.R, block.G, block.B, block.A
+		}
+		particle.HasTransform = transform.enabled
+		particle.TransformRotation = transform.rotation
+		particle.TransformScale = transform.scale
+
+		particle.StartFrame = 0
+		particle.Heading = 0
+		if frames := &data.AnimParams.Frames; frames.Enabled && frames.RandomStart {
+			particle.StartFrame = minInt(int(randFloat32(rng)*float32(frames.Count)), frames.Count-1)
+		}
 
 		particle.Active = true
 
 		// Initialize per-property sequence snapshots, reusing pooled slices
 		particle.HasPosXSeq = data.PosXSeq != nil
 		if particle.HasPosXSeq {
-			FillSnapshot(data.PosXSeq, &particle.PosXSnap, spawnX)
+			fillSnapshot(data.PosXSeq, &particle.PosXSnap, spawnX, rng)
 		}
 		particle.HasPosYSeq = data.PosYSeq != nil
 		if particle.HasPosYSeq {
-			FillSnapshot(data.PosYSeq, &particle.PosYSnap, spawnY)
+			fillSnapshot(data.PosYSeq, &particle.PosYSnap, spawnY, rng)
 		}
 		particle.HasScaleSeq = data.ScaleSeq != nil
 		if particle.HasScaleSeq {
-			FillSnapshot(data.ScaleSeq, &particle.ScaleSnap, 0)
+			fillSnapshot(data.ScaleSeq, &particle.ScaleSnap, 0, rng)
+		}
+		particle.HasScaleXSeq = data.ScaleXSeq != nil
+		if particle.HasScaleXSeq {
+			fillSnapshot(data.ScaleXSeq, &particle.ScaleXSnap, 0, rng)
+		}
+		particle.HasScaleYSeq = data.ScaleYSeq != nil
+		if particle.HasScaleYSeq {
+			fillSnapshot(data.ScaleYSeq, &particle.ScaleYSnap, 0, rng)
 		}
 		particle.HasRotSeq = data.RotSeq != nil
 		if particle.HasRotSeq {
-			FillSnapshot(data.RotSeq, &particle.RotSnap, 0)
+			fillSnapshot(data.RotSeq, &particle.RotSnap, 0, rng)
 		}
 		particle.HasAlphaSeq = data.AlphaSeq != nil
 		if particle.HasAlphaSeq {
-			FillSnapshot(data.AlphaSeq, &particle.AlphaSnap, 0)
+			fillSnapshot(data.AlphaSeq, &particle.AlphaSnap, 0, rng)
 		}
 
+		particle.SpawnSeq = data.spawnSeq
+		data.spawnSeq++
 		data.ActiveCount++
 		data.Metrics.SpawnCount++
+
+		if len(data.SubEmitters) > 0 {
+			sys.triggerSubEmitters(data, particle, SubEmitterOnBirth)
+		}
+	}
+}
+
+// emissionForStep returns how many particles (possibly fractional) the steady
+// emitter produces over deltaTime. The clock is per entity, so emission does
+// not depend on TPS or on when the effect was created.
+func emissionForStep(data *SystemData, deltaTime float32) float32 {
+	if deltaTime <= 0 {
+		return 0
+	}
+	if data.SpawnRate > 0 {
+		return data.SpawnRate * deltaTime
+	}
+	if data.SpawnInterval <= 0 {
+		return 0
+	}
+	interval := float32(data.SpawnInterval)
+	data.spawnClock += deltaTime * referenceTPS
+	ticks := 0
+	for data.spawnClock >= interval {
+		data.spawnClock -= interval
+		ticks++
+	}
+	return float32(ticks * data.ParticlesPerSpawn)
+}
+
+// emitterPath is the segment the emitter moved along during one update and
+// the evenly spaced points on it that spawn.rate_over_distance emits from.
+type emitterPath struct {
+	x0, y0     float32
+	dirX, dirY float32
+	first      float32 // distance from (x0, y0) to the first point
+	spacing    float32
+	count      int
+}
+
+func (p *emitterPath) point(i int) (float32, float32) {
+	d := p.first + float32(i)*p.spacing
+	return p.x0 + p.dirX*d, p.y0 + p.dirY*d
+}
+
+// emitterPathForStep returns the points the emitter passed since the last
+// update, one every 1/rate_over_distance units. Distance not yet worth a
+// particle is carried over, so spacing stays even at any emitter speed.
+func emitterPathForStep(data *SystemData, emissionScale float32) emitterPath {
+	x0, y0 := data.lastEmitterX, data.lastEmitterY
+	valid := data.emitterPathValid
+	data.lastEmitterX, data.lastEmitterY = data.EmitterX, data.EmitterY
+	data.emitterPathValid = true
+
+	rate := data.SpawnRateOverDistance * emissionScale
+	if !valid || rate <= 0 {
+		data.distanceCarry = 0
+		return emitterPath{}
+	}
+	dx := data.EmitterX - x0
+	dy := data.EmitterY - y0
+	dist := float32(math.Sqrt(float64(dx*dx + dy*dy)))
+	if dist <= 0 {
+		return emitterPath{}
+	}
+	spacing := 1 / rate
+	travelled := data.distanceCarry + dist
+	count := int(travelled / spacing)
+	data.distanceCarry = travelled - float32(count)*spacing
+	return emitterPath{
+		x0: x0, y0: y0,
+		dirX: dx / dist, dirY: dy / dist,
+		first:   spacing - (travelled - dist),
+		spacing: spacing,
+		count:   count,
+	}
+}
+
+// advanceLifeTime counts LifeTime down by the reference frames covered by
+// deltaTime, carrying the fractional part to the next update.
+func advanceLifeTime(data *SystemData, deltaTime float32) {
+	if data.LifeTime <= 0 {
+		return
+	}
+	data.lifeTimeClock += deltaTime * referenceTPS
+	frames := int(data.lifeTimeClock)
+	data.lifeTimeClock -= float32(frames)
+	data.LifeTime -= frames
+	if data.LifeTime < 0 {
+		data.LifeTime = 0
 	}
 }
 
@@ -252,6 +504,16 @@ func clampEmissionScale(scale float32) float32 {
 	return scale
 }
 
+func clampTimeScale(scale float32) float32 {
+	if math.IsNaN(float64(scale)) {
+		return 1
+	}
+	if scale < 0 {
+		return 0
+	}
+	return scale
+}
+
 func scaledMaxParticles(maxParticles int, scale float32) int {
 	if maxParticles <= 0 || scale <= 0 {
 		return 0
@@ -263,18 +525,18 @@ func scaledMaxParticles(maxParticles int, scale float32) int {
 	return scaled
 }
 
-func sampleEmitterPosition(emitterX, emitterY float32, shape EmitterShapeParams, vector EmitterVectorParams, spawnIndex, spawnTotal int) (float32, float32) {
+func sampleEmitterPosition(rng *rand.Rand, emitterX, emitterY float32, shape EmitterShapeParams, vector EmitterVectorParams, spawnIndex, spawnTotal int) (float32, float32) {
 	if vector.Enabled {
-		return sampleEmitterVectorPosition(emitterX, emitterY, vector, spawnIndex, spawnTotal)
+		return sampleEmitterVectorPosition(rng, emitterX, emitterY, vector, spawnIndex, spawnTotal)
 	}
 	switch shape.Type {
 	case EmitterShapeCircle:
-		angle := sampleCircleAngle(shape.StartAngle, shape.EndAngle)
-		radius := rangeFloat32(shape.RadiusMin, shape.RadiusMax)
+		angle := sampleCircleAngle(rng, shape.StartAngle, shape.EndAngle)
+		radius := rangeFloat32(rng, shape.RadiusMin, shape.RadiusMax)
 		if !shape.FromEdge {
 			minRadiusSq := shape.RadiusMin * shape.RadiusMin
 			maxRadiusSq := shape.RadiusMax * shape.RadiusMax
-			radius = float32(math.Sqrt(float64(minRadiusSq + rand.Float32()*(maxRadiusSq-minRadiusSq))))
+			radius = float32(math.Sqrt(float64(minRadiusSq + randFloat32(rng)*(maxRadiusSq-minRadiusSq))))
 		}
 		sin, cos := fastSincos(angle)
 		return emitterX + radius*cos, emitterY + radius*sin
@@ -286,7 +548,7 @@ func sampleEmitterPosition(emitterX, emitterY float32, shape EmitterShapeParams,
 			if perimeter <= 0 {
 				return emitterX, emitterY
 			}
-			d := rand.Float32() * perimeter
+			d := randFloat32(rng) * perimeter
 			switch {
 			case d < shape.Width:
 				return rotateOffset(emitterX, emitterY, d-halfW, -halfH, shape.Rotation)
@@ -301,8 +563,8 @@ func sampleEmitterPosition(emitterX, emitterY float32, shape EmitterShapeParams,
 		return rotateOffset(
 			emitterX,
 			emitterY,
-			rangeFloat32(-halfW, halfW),
-			rangeFloat32(-halfH, halfH),
+			rangeFloat32(rng, -halfW, halfW),
+			rangeFloat32(rng, -halfH, halfH),
 			shape.Rotation,
 		)
 	case EmitterShapeLine:
@@ -310,7 +572,7 @@ func sampleEmitterPosition(emitterX, emitterY float32, shape EmitterShapeParams,
 		return rotateOffset(
 			emitterX,
 			emitterY,
-			rangeFloat32(-halfLen, halfLen),
+			rangeFloat32(rng, -halfLen, halfLen),
 			0,
 			shape.Rotation,
 		)
@@ -319,11 +581,17 @@ func sampleEmitterPosition(emitterX, emitterY float32, shape EmitterShapeParams,
 	}
 }
 
-func sampleEmitterVectorPosition(emitterX, emitterY float32, vector EmitterVectorParams, spawnIndex, spawnTotal int) (float32, float32) {
+func sampleEmitterVectorPosition(rng *rand.Rand, emitterX, emitterY float32, vector EmitterVectorParams, spawnIndex, spawnTotal int) (float32, float32) {
 	switch vector.Type {
 	case EmitterVectorRect:
 		return sampleRectVectorPosition(emitterX, emitterY, vector.Rect, vector.Placement, spawnIndex, spawnTotal)
 	case EmitterVectorPolyline:
+		if len(vector.Polylines) > 0 {
+			return samplePolylinePartsPosition(rng, emitterX, emitterY, &vector, spawnIndex, spawnTotal)
+		}
+		if vector.Placement == EmitterVectorFill && vector.Polyline.TotalArea > 0 {
+			return samplePolylineFillPosition(rng, emitterX, emitterY, vector.Polyline, stratifiedSampleRatio(spawnIndex, spawnTotal)*vector.Polyline.TotalArea)
+		}
 		return samplePolylineVectorPosition(emitterX, emitterY, vector.Polyline, spawnIndex, spawnTotal)
 	default:
 		return emitterX, emitterY
@@ -374,7 +642,92 @@ func samplePolylineVectorPosition(emitterX, emitterY float32, polyline EmitterVe
 		return emitterX, emitterY
 	}
 
-	target := stratifiedSampleRatio(spawnIndex, spawnTotal) * polyline.TotalLength
+	x, y := polylinePointAtDistance(polyline, stratifiedSampleRatio(spawnIndex, spawnTotal)*polyline.TotalLength)
+	return emitterX + x, emitterY + y
+}
+
+// samplePolylinePartsPosition samples Polyline and Polylines as one shape:
+// the stratified target runs over their combined length (surface) or area
+// (fill), so every part gets its share of a burst.
+func samplePolylinePartsPosition(rng *rand.Rand, emitterX, emitterY float32, vector *EmitterVectorParams, spawnIndex, spawnTotal int) (float32, float32) {
+	fill := vector.Placement == EmitterVectorFill
+	measure := func(part *EmitterVectorPolylineParams) float32 {
+		if fill {
+			return part.TotalArea
+		}
+		if len(part.Points) < 2 {
+			return 0
+		}
+		return part.TotalLength
+	}
+	part := func(i int) *EmitterVectorPolylineParams {
+		if i == 0 {
+			return &vector.Polyline
+		}
+		return &vector.Polylines[i-1]
+	}
+
+	count := 1 + len(vector.Polylines)
+	total := float32(0)
+	last := 0
+	for i := 0; i < count; i++ {
+		if m := measure(part(i)); m > 0 {
+			total += m
+			last = i
+		}
+	}
+	if total <= 0 {
+		if fill {
+			// Nothing to fill (e.g. only open parts); fall back to outlines.
+			surface := *vector
+			surface.Placement = EmitterVectorSurface
+			return samplePolylinePartsPosition(rng, emitterX, emitterY, &surface, spawnIndex, spawnTotal)
+		}
+		return emitterX, emitterY
+	}
+
+	target := stratifiedSampleRatio(spawnIndex, spawnTotal) * total
+	for i := 0; i < count; i++ {
+		p := part(i)
+		m := measure(p)
+		if m <= 0 {
+			continue
+		}
+		if target <= m || i == last {
+			target = min(target, m)
+			if fill {
+				return samplePolylineFillPosition(rng, emitterX, emitterY, *p, target)
+			}
+			x, y := polylinePointAtDistance(*p, target)
+			return emitterX + x, emitterY + y
+		}
+		target -= m
+	}
+	return emitterX, emitterY
+}
+
+// samplePolylineFillPosition picks the triangle covering the given cumulative
+// area, so stratified targets spread a burst evenly over the interior, then a
+// uniform point inside it.
+func samplePolylineFillPosition(rng *rand.Rand, emitterX, emitterY float32, polyline EmitterVectorPolylineParams, target float32) (float32, float32) {
+	i := sort.Search(len(polyline.TriangleAreas), func(i int) bool { return polyline.TriangleAreas[i] >= target })
+	if i >= len(polyline.Triangles) {
+		i = len(polyline.Triangles) - 1
+	}
+	t := polyline.Triangles[i]
+	u := randFloat32(rng)
+	v := randFloat32(rng)
+	if u+v > 1 {
+		u, v = 1-u, 1-v
+	}
+	x := t.A.X + (t.B.X-t.A.X)*u + (t.C.X-t.A.X)*v
+	y := t.A.Y + (t.B.Y-t.A.Y)*u + (t.C.Y-t.A.Y)*v
+	return emitterX + x, emitterY + y
+}
+
+// polylinePointAtDistance returns the point the given arc length along the
+// polyline. The polyline must have at least two points.
+func polylinePointAtDistance(polyline EmitterVectorPolylineParams, target float32) (float32, float32) {
 	accumulated := float32(0)
 	for i, segmentLength := range polyline.SegmentLengths {
 		if segmentLength <= 0 {
@@ -389,15 +742,13 @@ func samplePolylineVectorPosition(emitterX, emitterY float32, polyline EmitterVe
 			} else if localT > 1 {
 				localT = 1
 			}
-			x := start.X + (end.X-start.X)*localT
-			y := start.Y + (end.Y-start.Y)*localT
-			return emitterX + x, emitterY + y
+			return start.X + (end.X-start.X)*localT, start.Y + (end.Y-start.Y)*localT
 		}
 		accumulated = next
 	}
 
 	last := polyline.Points[len(polyline.Points)-1]
-	return emitterX + last.X, emitterY + last.Y
+	return last.X, last.Y
 }
 
 func polylineSegmentEndpoints(polyline EmitterVectorPolylineParams, index int) (EmitterVectorPointParams, EmitterVectorPointParams) {
@@ -422,12 +773,19 @@ func maxInt(a, b int) int {
 	return b
 }
 
-func sampleCircleAngle(startAngle, endAngle float32) float32 {
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func sampleCircleAngle(rng *rand.Rand, startAngle, endAngle float32) float32 {
 	tau := float32(2 * math.Pi)
 
 	rawSpan := endAngle - startAngle
 	if rawSpan >= tau-fullCircleEpsilon || rawSpan <= -tau+fullCircleEpsilon {
-		return rand.Float32() * tau
+		return randFloat32(rng) * tau
 	}
 
 	start := normalizeAngle(startAngle)
@@ -439,12 +797,12 @@ func sampleCircleAngle(startAngle, endAngle float32) float32 {
 
 	if span <= fullCircleEpsilon {
 		if math.Abs(float64(rawSpan)) > float64(fullCircleEpsilon) {
-			return rand.Float32() * tau
+			return randFloat32(rng) * tau
 		}
 		return start
 	}
 
-	return normalizeAngle(start + rand.Float32()*span)
+	return normalizeAngle(start + randFloat32(rng)*span)
 }
 
 func normalizeAngle(angle float32) float32 {
@@ -474,10 +832,19 @@ func (sys *System) updateParticles(data *SystemData, deltaTime float32) {
 		particle := &data.ParticlePool[i]
 
 		elapsed := currentTime - particle.SpawnTime
+		if particle.Collided {
+			particle.Collided = false
+			if len(data.SubEmitters) > 0 {
+				sys.triggerSubEmitters(data, particle, SubEmitterOnCollision)
+			}
+		}
 		if elapsed >= particle.Duration {
+			if len(data.SubEmitters) > 0 {
+				sys.triggerSubEmitters(data, particle, SubEmitterOnDeath)
+			}
 			particle.Active = false
 			if data.Trail.Params.Mode == "particle" {
-				detachParticleTrail(&data.Trail, particle.TrailPoints)
+				detachParticleTrail(&data.Trail, particle.TrailPoints, particleTransformScale(particle))
 			}
 			particle.TrailPoints = particle.TrailPoints[:0]
 			data.ActiveCount--
@@ -503,7 +870,8 @@ const (
 
 // simulateActiveParticles advances flow state and caches positions for all
 // active particles. Each particle only touches its own state, so the work is
-// split across goroutines for large pools.
+// split across goroutines for large pools, unless a grid collider's Solid
+// callback would then run concurrently.
 func simulateActiveParticles(data *SystemData, deltaTime float32) {
 	n := data.ActiveCount
 	if n == 0 {
@@ -515,10 +883,10 @@ func simulateActiveParticles(data *SystemData, deltaTime float32) {
 		workers = 8
 	}
 	threshold := parallelSimulateThresholdPlain
-	if data.AnimParams.Position.HasFlow {
+	if data.AnimParams.Position.HasFlow || len(data.forceFields) > 0 {
 		threshold = parallelSimulateThresholdFlow
 	}
-	if n < threshold || workers <= 1 {
+	if n < threshold || workers <= 1 || hasGridCollider(data.Colliders) {
 		simulateParticleRange(data, data.ParticlePool[:n], deltaTime)
 		return
 	}
@@ -541,11 +909,25 @@ func simulateActiveParticles(data *SystemData, deltaTime float32) {
 
 func simulateParticleRange(data *SystemData, particles []Instance, deltaTime float32) {
 	currentTime := data.CurrentTime
-	hasFlow := data.AnimParams.Position.HasFlow
+	// A paused or frozen step must not integrate (or damp) flow velocity.
+	hasFlow := data.AnimParams.Position.HasFlow && deltaTime > 0
+	flowDrag := float32(0)
+	if hasFlow {
+		flowDrag = stepDrag(data.AnimParams.Position.FlowDrag, deltaTime)
+	}
+	hasColliders := len(data.Colliders) > 0 && deltaTime > 0
 	for i := range particles {
 		particle := &particles[i]
 		elapsed := currentTime - particle.SpawnTime
-		if particle.HasFlow && hasFlow {
+		if deltaTime > 0 {
+			if len(data.forceFields) > 0 && particle.Integrated && !particle.Stuck {
+				ax, ay := forceFieldAcceleration(data.forceFields, particle.CurrentX, particle.CurrentY, currentTime)
+				particle.VelX += ax * deltaTime
+				particle.VelY += ay * deltaTime
+			}
+			integrateParticle(particle, &data.AnimParams.Position, deltaTime)
+		}
+		if particle.HasFlow && hasFlow && !particle.Stuck {
 			normalizedT := elapsed / particle.Duration
 			if normalizedT < 0 {
 				normalizedT = 0
@@ -553,165 +935,254 @@ func simulateParticleRange(data *SystemData, particles []Instance, deltaTime flo
 			if normalizedT > 1 {
 				normalizedT = 1
 			}
-			updateParticleFlow(data, particle, elapsed, normalizedT, deltaTime)
+			updateParticleFlow(data, particle, elapsed, normalizedT, deltaTime, flowDrag)
 		}
 		cacheParticleCurrentPosition(data, particle, elapsed)
+		if hasColliders && !particle.Stuck {
+			resolveParticleCollision(data, particle, elapsed)
+		}
 	}
 }
 
-// Draw renders all particles using GPU batch rendering
+// Draw renders the particles of every layer using GPU batch rendering,
+// lower render layers first, through the camera set by SetCamera.
 func (sys *System) Draw(ecs *ecs.ECS, screen *ebiten.Image) {
-	for entry := range sys.query.Iter(ecs.World) {
-		data := Component.Get(entry)
+	sys.DrawWithCamera(ecs, screen, sys.camera)
+}
 
-		if data.Trail.Params.Enabled {
-			drawTrail(screen, data)
-		}
+// drawEntity renders the trail and particles of one entity through view.
+func (sys *System) drawEntity(screen *ebiten.Image, data *SystemData, view *viewTransform) {
+	if data.Trail.Params.Enabled {
+		drawTrail(screen, data, view)
+	}
 
-		if data.SourceImage == nil {
-			continue
-		}
+	if data.SourceImage == nil {
+		return
+	}
 
-		if data.ActiveCount == 0 {
-			continue
-		}
+	if data.ActiveCount == 0 {
+		return
+	}
 
-		startTime := time.Now()
+	startTime := time.Now()
 
-		// Build vertex buffer; the quad index pattern is static and grown once.
-		data.Vertices = data.Vertices[:0]
-		batchQuads := data.ActiveCount
-		if batchQuads > maxParticleBatchVertices/4 {
-			batchQuads = maxParticleBatchVertices / 4
-		}
-		ensureQuadIndices(data, batchQuads)
-
-		currentTime := data.CurrentTime
-		imgW := data.ImageWidth
-		imgH := data.ImageHeight
-		halfW := imgW / 2
-		halfH := imgH / 2
-
-		// Colors are fully evaluated on the CPU into vertex data, so the
-		// shader-less path renders with plain DrawTriangles and benefits
-		// from Ebiten's internal draw-command batching across systems.
-		var shaderOpts *ebiten.DrawTrianglesShaderOptions
-		var plainOpts *ebiten.DrawTrianglesOptions
+	// Build vertex buffer; the quad index pattern is static and grown once.
+	data.Vertices = data.Vertices[:0]
+	batchQuads := data.ActiveCount
+	if batchQuads > maxParticleBatchVertices/4 {
+		batchQuads = maxParticleBatchVertices / 4
+	}
+	ensureQuadIndices(data, batchQuads)
+
+	currentTime := data.CurrentTime
+	imgW := data.ImageWidth
+	imgH := data.ImageHeight
+	app := &data.AnimParams.Appearance
+	frames := &data.AnimParams.Frames
+	cellW, cellH := imgW, imgH
+	if frames.Enabled {
+		_, _, cellW, cellH = frameSourceRect(frames, 0, imgW, imgH)
+	}
+	halfW := cellW / 2
+	halfH := cellH / 2
+
+	// Colors are fully evaluated on the CPU into vertex data, so the
+	// shader-less path renders with plain DrawTriangles and benefits
+	// from Ebiten's internal draw-command batching across systems.
+	var shaderOpts *ebiten.DrawTrianglesShaderOptions
+	var plainOpts *ebiten.DrawTrianglesOptions
+	if data.Shader != nil {
+		shaderOpts = &ebiten.DrawTrianglesShaderOptions{
+			Uniforms: data.ShaderUniforms,
+			Images:   [4]*ebiten.Image{data.SourceImage},
+			Blend:    data.Blend,
+		}
+	} else {
+		plainOpts = &ebiten.DrawTrianglesOptions{
+			ColorScaleMode: ebiten.ColorScaleModeStraightAlpha,
+			Blend:          data.Blend,
+		}
+	}
+	flush := func() {
+		indices := data.Indices[:len(data.Vertices)/4*6]
 		if data.Shader != nil {
-			shaderOpts = &ebiten.DrawTrianglesShaderOptions{
-				Uniforms: data.ShaderUniforms,
-				Images:   [4]*ebiten.Image{data.SourceImage},
-				Blend:    data.Blend,
-			}
+			screen.DrawTrianglesShader(data.Vertices, indices, data.Shader, shaderOpts)
 		} else {
-			plainOpts = &ebiten.DrawTrianglesOptions{
-				ColorScaleMode: ebiten.ColorScaleModeStraightAlpha,
-				Blend:          data.Blend,
-			}
+			screen.DrawTriangles(data.Vertices, indices, data.SourceImage, plainOpts)
 		}
-		flush := func() {
-			indices := data.Indices[:len(data.Vertices)/4*6]
-			if data.Shader != nil {
-				screen.DrawTrianglesShader(data.Vertices, indices, data.Shader, shaderOpts)
-			} else {
-				screen.DrawTriangles(data.Vertices, indices, data.SourceImage, plainOpts)
-			}
-			data.Vertices = data.Vertices[:0]
+		data.Vertices = data.Vertices[:0]
+	}
+
+	order := particleDrawOrder(data)
+	for n := 0; n < data.ActiveCount; n++ {
+		// Flush before the vertex count overflows uint16 indices.
+		if len(data.Vertices) >= maxParticleBatchVertices {
+			flush()
 		}
+		particleIdx := n
+		if order != nil {
+			particleIdx = int(order[n].index)
+		}
+		p := &data.ParticlePool[particleIdx]
 
-		for particleIdx := 0; particleIdx < data.ActiveCount; particleIdx++ {
-			// Flush before the vertex count overflows uint16 indices.
-			if len(data.Vertices) >= maxParticleBatchVertices {
-				flush()
-			}
-			p := &data.ParticlePool[particleIdx]
+		// Calculate normalized time
+		elapsed := currentTime - p.SpawnTime
+		normalizedT := particleNormalizedTime(data, p)
 
-			// Calculate normalized time
-			elapsed := currentTime - p.SpawnTime
-			normalizedT := elapsed / p.Duration
-			if normalizedT < 0 {
-				normalizedT = 0
-			}
-			if normalizedT > 1 {
-				normalizedT = 1
-			}
+		// Position is cached during update for draw/trail reuse.
+		x, y := currentParticlePosition(data, p, elapsed)
 
-			// Position is cached during update for draw/trail reuse.
-			x, y := currentParticlePosition(data, p, elapsed)
+		scale := particleScale(data, p, elapsed, normalizedT)
 
-			var scale float32
-			if p.HasScaleSeq {
-				scale = EvaluateSequence(data.ScaleSeq, &p.ScaleSnap, elapsed)
-			} else {
-				scale = lerp(p.StartScale, p.EndScale, ApplyEasing(normalizedT, p.ScaleEasing))
-			}
+		var rotation float32
+		switch {
+		case p.HasRotSeq:
+			rotation = EvaluateSequence(data.RotSeq, &p.RotSnap, elapsed)
+		case app.RotationCurve != nil:
+			rotation = app.RotationCurve.Evaluate(normalizedT)
+		default:
+			rotation = lerp(p.StartRotation, p.EndRotation, ApplyEasing(normalizedT, p.RotationEasing))
+		}
 
-			var rotation float32
-			if p.HasRotSeq {
-				rotation = EvaluateSequence(data.RotSeq, &p.RotSnap, elapsed)
-			} else {
-				rotation = lerp(p.StartRotation, p.EndRotation, ApplyEasing(normalizedT, p.RotationEasing))
-			}
+		// Calculate scaled dimensions; scale_x / scale_y replace the
+		// uniform scale on their axis.
+		scaleX, scaleY := particleScaleAxes(data, p, scale, elapsed, normalizedT)
+		scaledHalfW := halfW * scaleX
+		scaledHalfH := halfH * scaleY
+		if p.HasTransform {
+			scaledHalfW *= p.TransformScale
+			scaledHalfH *= p.TransformScale
+		}
 
-			// Calculate scaled dimensions
-			scaledHalfW := halfW * scale
-			scaledHalfH := halfH * scale
+		if app.AlignVelocity {
+			rotation, scaledHalfW = alignParticleToVelocity(data, p, app.Stretch, rotation, scaledHalfW)
+		} else if p.HasTransform {
+			// Aligned particles already follow the rotated motion.
+			rotation += p.TransformRotation
+		}
+		if view.enabled {
+			x, y = view.apply(x, y)
+			scaledHalfW *= view.zoom
+			scaledHalfH *= view.zoom
+			rotation += view.rotation
+		}
 
-			// Calculate rotated corner positions
-			cos := float32(1.0)
-			sin := float32(0.0)
-			if rotation != 0 {
-				sin, cos = fastSincos(rotation)
-			}
+		// Calculate rotated corner positions
+		cos := float32(1.0)
+		sin := float32(0.0)
+		if rotation != 0 {
+			sin, cos = fastSincos(rotation)
+		}
 
-			// Evaluate alpha and tint per particle; vertex colors carry the
-			// final straight-alpha color (custom.x carries normalized time
-			// for effect shaders such as blur).
-			var alpha float32
-			if p.HasAlphaSeq {
-				alpha = EvaluateSequence(data.AlphaSeq, &p.AlphaSnap, elapsed)
-			} else {
-				alpha = lerp(p.StartAlpha, p.EndAlpha, ApplyEasing(normalizedT, p.AlphaEasing))
-			}
-			colorT := ApplyEasing(normalizedT, p.ColorEasing)
-			tintR := lerp(p.StartR, p.EndR, colorT)
-			tintG := lerp(p.StartG, p.EndG, colorT)
-			tintB := lerp(p.StartB, p.EndB, colorT)
-
-			// Rotated half extents; the four corners are +/- combinations.
-			// Top-left, Top-right, Bottom-left, Bottom-right
-			wx := scaledHalfW * cos
-			wy := scaledHalfW * sin
-			hx := -scaledHalfH * sin
-			hy := scaledHalfH * cos
-
-			vertex := ebiten.Vertex{
-				ColorR:  tintR,
-				ColorG:  tintG,
-				ColorB:  tintB,
-				ColorA:  alpha,
-				Custom0: normalizedT,
-			}
-			vertex.DstX, vertex.DstY = x-wx-hx, y-wy-hy
-			vertex.SrcX, vertex.SrcY = 0, 0
-			data.Vertices = append(data.Vertices, vertex)
-			vertex.DstX, vertex.DstY = x+wx-hx, y+wy-hy
-			vertex.SrcX, vertex.SrcY = imgW, 0
-			data.Vertices = append(data.Vertices, vertex)
-			vertex.DstX, vertex.DstY = x-wx+hx, y-wy+hy
-			vertex.SrcX, vertex.SrcY = 0, imgH
-			data.Vertices = append(data.Vertices, vertex)
-			vertex.DstX, vertex.DstY = x+wx+hx, y+wy+hy
-			vertex.SrcX, vertex.SrcY = imgW, imgH
-			data.Vertices = append(data.Vertices, vertex)
-		}
-
-		if len(data.Vertices) > 0 {
-			flush()
+		// Evaluate alpha and tint per particle; vertex colors carry the
+		// final straight-alpha color (custom.x carries normalized time
+		// for effect shaders such as blur).
+		var alpha float32
+		switch {
+		case p.HasAlphaSeq:
+			alpha = EvaluateSequence(data.AlphaSeq, &p.AlphaSnap, elapsed)
+		case app.AlphaCurve != nil:
+			alpha = app.AlphaCurve.Evaluate(normalizedT)
+		default:
+			alpha = lerp(p.StartAlpha, p.EndAlpha, ApplyEasing(normalizedT, p.AlphaEasing))
+		}
+		tintR, tintG, tintB, tintA := particleTint(p, normalizedT)
+		alpha *= tintA
+
+		// Rotated half extents; the four corners are +/- combinations.
+		// Top-left, Top-right, Bottom-left, Bottom-right
+		wx := scaledHalfW * cos
+		wy := scaledHalfW * sin
+		hx := -scaledHalfH * sin
+		hy := scaledHalfH * cos
+
+		vertex := ebiten.Vertex{
+			ColorR:  tintR,
+			ColorG:  tintG,
+			ColorB:  tintB,
+			ColorA:  alpha,
+			Custom0: normalizedT,
 		}
+		srcX, srcY := data.ImageX, data.ImageY
+		if frames.Enabled {
+			cellX, cellY, _, _ := frameSourceRect(frames, particleFrame(frames, p, elapsed, normalizedT), imgW, imgH)
+			srcX += cellX
+			srcY += cellY
+		}
+
+		vertex.DstX, vertex.DstY = x-wx-hx, y-wy-hy
+		vertex.SrcX, vertex.SrcY = srcX, srcY
+		data.Vertices = append(data.Vertices, vertex)
+		vertex.DstX, vertex.DstY = x+wx-hx, y+wy-hy
+		vertex.SrcX, vertex.SrcY = srcX+cellW, srcY
+		data.Vertices = append(data.Vertices, vertex)
+		vertex.DstX, vertex.DstY = x-wx+hx, y-wy+hy
+		vertex.SrcX, vertex.SrcY = srcX, srcY+cellH
+		data.Vertices = append(data.Vertices, vertex)
+		vertex.DstX, vertex.DstY = x+wx+hx, y+wy+hy
+		vertex.SrcX, vertex.SrcY = srcX+cellW, srcY+cellH
+		data.Vertices = append(data.Vertices, vertex)
+	}
 
-		data.Metrics.DrawTimeUs = time.Since(startTime).Microseconds()
+	if len(data.Vertices) > 0 {
+		flush()
 	}
+
+	data.Metrics.DrawTimeUs = time.Since(startTime).Microseconds()
+}
+
+// particleScale evaluates the uniform scale of p.
+func particleScale(data *SystemData, p *Instance, elapsed, normalizedT float32) float32 {
+	switch {
+	case p.HasScaleSeq:
+		return EvaluateSequence(data.ScaleSeq, &p.ScaleSnap, elapsed)
+	case data.AnimParams.Appearance.ScaleCurve != nil:
+		return data.AnimParams.Appearance.ScaleCurve.Evaluate(normalizedT)
+	default:
+		return lerp(p.StartScale, p.EndScale, ApplyEasing(normalizedT, p.ScaleEasing))
+	}
+}
+
+// particleScaleAxes returns the X and Y scale, using scale for any axis
+// without its own scale_x / scale_y animation.
+func particleScaleAxes(data *SystemData, p *Instance, scale, elapsed, normalizedT float32) (float32, float32) {
+	app := &data.AnimParams.Appearance
+	scaleX, scaleY := scale, scale
+	if app.HasScaleX {
+		switch {
+		case p.HasScaleXSeq:
+			scaleX = EvaluateSequence(data.ScaleXSeq, &p.ScaleXSnap, elapsed)
+		case app.ScaleXCurve != nil:
+			scaleX = app.ScaleXCurve.Evaluate(normalizedT)
+		default:
+			scaleX = lerp(p.StartScaleX, p.EndScaleX, ApplyEasing(normalizedT, p.ScaleXEasing))
+		}
+	}
+	if app.HasScaleY {
+		switch {
+		case p.HasScaleYSeq:
+			scaleY = EvaluateSequence(data.ScaleYSeq, &p.ScaleYSnap, elapsed)
+		case app.ScaleYCurve != nil:
+			scaleY = app.ScaleYCurve.Evaluate(normalizedT)
+		default:
+			scaleY = lerp(p.StartScaleY, p.EndScaleY, ApplyEasing(normalizedT, p.ScaleYEasing))
+		}
+	}
+	return scaleX, scaleY
+}
+
+// alignParticleToVelocity adds the particle's heading to rotation and
+// stretches halfW along it in proportion to speed.
+func alignParticleToVelocity(data *SystemData, p *Instance, stretch, rotation, halfW float32) (float32, float32) {
+	vx, vy := particleVelocity(data, p)
+	speedSq := vx*vx + vy*vy
+	if speedSq > minHeadingSpeedSq {
+		p.Heading = float32(math.Atan2(float64(vy), float64(vx)))
+	}
+	if stretch > 0 {
+		halfW *= 1 + stretch*float32(math.Sqrt(float64(speedSq)))
+	}
+	return rotation + p.Heading, halfW
 }
 
 // ensureQuadIndices grows the static quad index buffer to cover quadCount
@@ -732,16 +1203,24 @@ func ensureQuadIndices(data *SystemData, quadCount int) {
 
 // assignParticleColor sets a particle's color pair from config, mixing toward
 // the variation pair by one random factor when variation is enabled.
-func assignParticleColor(particle *Instance, clr *ColorParams) {
+func assignParticleColor(particle *Instance, clr *ColorParams, rng *rand.Rand) {
 	particle.ColorVariationMix = 0
-	if clr.HasVariation {
-		particle.ColorVariationMix = rand.Float32()
+	if clr.rollsPerParticle() {
+		particle.ColorVariationMix = randFloat32(rng)
 	}
 	applyParticleColor(particle, clr)
 }
 
 func applyParticleColor(particle *Instance, clr *ColorParams) {
-	if clr.HasVariation {
+	particle.ColorSpace = clr.Space
+	particle.ColorGradient = clr.Gradient
+	if n := len(clr.Palette); n > 0 {
+		c := clr.Palette[minInt(int(particle.ColorVariationMix*float32(n)), n-1)]
+		particle.StartR, particle.EndR = c[0], c[0]
+		particle.StartG, particle.EndG = c[1], c[1]
+		particle.StartB, particle.EndB = c[2], c[2]
+		particle.ColorGradient = nil
+	} else if clr.HasVariation {
 		t := particle.ColorVariationMix
 		particle.StartR = lerp(clr.StartR, clr.Start2R, t)
 		particle.StartG = lerp(clr.StartG, clr.Start2G, t)
@@ -757,15 +1236,52 @@ func applyParticleColor(particle *Instance, clr *ColorParams) {
 		particle.EndG = clr.EndG
 		particle.EndB = clr.EndB
 	}
+	if particle.ColorSpace != ColorSpaceRGB {
+		// Convert once here instead of on every draw.
+		particle.StartColor = toColorSpace(particle.ColorSpace, particle.StartR, particle.StartG, particle.StartB)
+		particle.EndColor = toColorSpace(particle.ColorSpace, particle.EndR, particle.EndG, particle.EndB)
+	}
 	particle.ColorEasing = clr.Easing
 }
 
+// particleNormalizedTime returns the particle's lifetime progress in [0, 1].
+func particleNormalizedTime(data *SystemData, p *Instance) float32 {
+	normalizedT := (data.CurrentTime - p.SpawnTime) / p.Duration
+	if normalizedT < 0 {
+		return 0
+	}
+	if normalizedT > 1 {
+		return 1
+	}
+	return normalizedT
+}
+
+// particleTint returns the particle's RGB tint and gradient alpha factor at
+// normalized lifetime t.
+func particleTint(p *Instance, normalizedT float32) (float32, float32, float32, float32) {
+	colorT := ApplyEasing(normalizedT, p.ColorEasing)
+	var r, g, b, a float32
+	switch {
+	case p.ColorGradient != nil:
+		r, g, b, a = p.ColorGradient.Evaluate(colorT)
+	case p.ColorSpace == ColorSpaceRGB:
+		r, g, b, a = lerp(p.StartR, p.EndR, colorT), lerp(p.StartG, p.EndG, colorT), lerp(p.StartB, p.EndB, colorT), 1
+	default:
+		r, g, b = fromColorSpace(p.ColorSpace, mixColorCoords(p.ColorSpace, p.StartColor, p.EndColor, colorT))
+		a = 1
+	}
+	if p.HasTint {
+		return r * p.TintR, g * p.TintG, b * p.TintB, a * p.TintA
+	}
+	return r, g, b, a
+}
+
 // Helper functions
-func rangeFloat32(min, max float32) float32 {
+func rangeFloat32(rng *rand.Rand, min, max float32) float32 {
 	if min == max {
 		return min
 	}
-	return min + rand.Float32()*(max-min)
+	return min + randFloat32(rng)*(max-min)
 }
 
 func lerp(a, b, t float32) float32 {
@@ -774,6 +1290,8 @@ func lerp(a, b, t float32) float32 {
 
 func evaluateParticleBasePosition(data *SystemData, p *Instance, elapsed, posT float32) (float32, float32) {
 	switch {
+	case p.Integrated:
+		return p.PosX, p.PosY
 	case p.HasPolarVelocity:
 		dist := p.SpawnDist + p.Speed*elapsed
 		if p.AngularSpeed != 0 {
@@ -790,22 +1308,73 @@ func evaluateParticleBasePosition(data *SystemData, p *Instance, elapsed, posT f
 		u := 1 - posT
 		return u*u*p.StartX + 2*u*posT*p.ControlX + posT*posT*data.AttractorX,
 			u*u*p.StartY + 2*u*posT*p.ControlY + posT*posT*data.AttractorY
-	case p.HasPosXSeq:
-		x := EvaluateSequence(data.PosXSeq, &p.PosXSnap, elapsed)
-		if p.HasPosYSeq {
-			return x, EvaluateSequence(data.PosYSeq, &p.PosYSnap, elapsed)
-		}
-		return x, lerp(p.StartY, p.EndY, posT)
 	default:
-		x := lerp(p.StartX, p.EndX, posT)
-		if p.HasPosYSeq {
-			return x, EvaluateSequence(data.PosYSeq, &p.PosYSnap, elapsed)
+		// Per axis: sequence, then curve (offset from the start position over
+		// un-eased lifetime), then the start/end lerp.
+		pos := &data.AnimParams.Position
+		var x, y float32
+		switch {
+		case p.HasPosXSeq:
+			x = EvaluateSequence(data.PosXSeq, &p.PosXSnap, elapsed)
+		case pos.XCurve != nil:
+			x = p.StartX + pos.XCurve.Evaluate(clamp01(elapsed/p.Duration))
+		default:
+			x = lerp(p.StartX, p.EndX, posT)
+		}
+		switch {
+		case p.HasPosYSeq:
+			y = EvaluateSequence(data.PosYSeq, &p.PosYSnap, elapsed)
+		case pos.YCurve != nil:
+			y = p.StartY + pos.YCurve.Evaluate(clamp01(elapsed/p.Duration))
+		default:
+			y = lerp(p.StartY, p.EndY, posT)
+		}
+		return x, y
+	}
+}
+
+// stepDrag converts a per-reference-frame drag factor into the factor for a
+// deltaTime step, so damping follows the (scaled) time step rather than the
+// update count.
+func stepDrag(drag, deltaTime float32) float32 {
+	frames := deltaTime * referenceTPS
+	if frames == 1 || drag <= 0 {
+		return drag
+	}
+	return float32(math.Pow(float64(drag), float64(frames)))
+}
+
+// integrateParticle advances an integrated particle by one step: constant
+// acceleration, then linear and quadratic drag, then the terminal speed
+// clamp, then position (semi-implicit Euler).
+func integrateParticle(p *Instance, pos *PositionParams, deltaTime float32) {
+	if !p.Integrated || p.Stuck {
+		return
+	}
+	vx := p.VelX + pos.AccelX*deltaTime
+	vy := p.VelY + pos.AccelY*deltaTime
+	if pos.LinearDrag > 0 || pos.QuadraticDrag > 0 || pos.TerminalSpeed > 0 {
+		speed := float32(math.Sqrt(float64(vx*vx + vy*vy)))
+		if drag := pos.LinearDrag + pos.QuadraticDrag*speed; drag > 0 {
+			// Implicit drag step: stable for any drag and time step.
+			k := 1 / (1 + drag*deltaTime)
+			vx *= k
+			vy *= k
+			speed *= k
+		}
+		if pos.TerminalSpeed > 0 && speed > pos.TerminalSpeed {
+			k := pos.TerminalSpeed / speed
+			vx *= k
+			vy *= k
 		}
-		return x, lerp(p.StartY, p.EndY, posT)
 	}
+	p.VelX = vx
+	p.VelY = vy
+	p.PosX += vx * deltaTime
+	p.PosY += vy * deltaTime
 }
 
-func updateParticleFlow(data *SystemData, p *Instance, elapsed, normalizedT, deltaTime float32) {
+func updateParticleFlow(data *SystemData, p *Instance, elapsed, normalizedT, deltaTime, drag float32) {
 	pos := data.AnimParams.Position
 	if !pos.HasFlow || p.FlowGain == 0 {
 		return
@@ -823,8 +1392,8 @@ func updateParticleFlow(data *SystemData, p *Instance, elapsed, normalizedT, del
 	sampleY = sampleY/pos.FlowScale + p.FlowSeedY
 	t := elapsed * pos.FlowTimeScale
 	fieldX, fieldY := sampleCurlNoiseField(sampleX, sampleY, t, pos.FlowOctaves, pos.FlowPersistence)
-	p.FlowVelX = p.FlowVelX*pos.FlowDrag + fieldX*p.FlowGain*deltaTime
-	p.FlowVelY = p.FlowVelY*pos.FlowDrag + fieldY*p.FlowGain*deltaTime
+	p.FlowVelX = p.FlowVelX*drag + fieldX*p.FlowGain*deltaTime
+	p.FlowVelY = p.FlowVelY*drag + fieldY*p.FlowGain*deltaTime
 	p.FlowOffsetX += p.FlowVelX * deltaTime
 	p.FlowOffsetY += p.FlowVelY * deltaTime
 
@@ -851,6 +1420,16 @@ func cacheParticleCurrentPosition(data *SystemData, p *Instance, elapsed float32
 		x += p.FlowOffsetX
 		y += p.FlowOffsetY
 	}
+	if p.HasInheritedVel && !p.Integrated {
+		drift := inheritedVelocityDrift(elapsed)
+		x += p.InheritedVelX * drift
+		y += p.InheritedVelY * drift
+	}
+	if p.CurrentPosValid && p.CurrentPosTime != data.CurrentTime {
+		p.PrevX = p.CurrentX
+		p.PrevY = p.CurrentY
+		p.PrevPosTime = p.CurrentPosTime
+	}
 	p.CurrentX = x
 	p.CurrentY = y
 	p.CurrentPosValid = true
@@ -871,8 +1450,8 @@ func resetParticleFlowState(p *Instance, randomizeSeed bool) {
 	p.FlowVelX = 0
 	p.FlowVelY = 0
 	if randomizeSeed {
-		p.FlowSeedX = rand.Float32()*flowSeedRange - flowSeedHalfRange
-		p.FlowSeedY = rand.Float32()*flowSeedRange - flowSeedHalfRange
+		p.FlowSeedX = particleRandFloat32(p)*flowSeedRange - flowSeedHalfRange
+		p.FlowSeedY = particleRandFloat32(p)*flowSeedRange - flowSeedHalfRange
 		return
 	}
 	p.FlowSeedX = 0
@@ -2,6 +2,7 @@ package chirashi
 
 import (
 	"math"
+	"reflect"
 	"testing"
 
 	"github.com/yohamta/donburi"
@@ -9,7 +10,7 @@ import (
 )
 
 func TestSpawnRespectsMaxParticles(t *testing.T) {
-	sys := &System{cnt: 0}
+	sys := &System{}
 	data := &SystemData{
 		ParticlePool:      make([]Instance, 3),
 		SpawnInterval:     1,
@@ -37,7 +38,7 @@ func TestSpawnRespectsMaxParticles(t *testing.T) {
 		},
 	}
 
-	sys.spawn(data)
+	sys.spawn(data, defaultDeltaTime)
 
 	if got := data.ActiveCount; got != 3 {
 		t.Fatalf("active count got %d, want 3", got)
@@ -51,7 +52,7 @@ func TestSpawnRespectsMaxParticles(t *testing.T) {
 }
 
 func TestSpawnAppliesEmissionScaleWithoutMutatingBaseValues(t *testing.T) {
-	sys := &System{cnt: 0}
+	sys := &System{}
 	data := &SystemData{
 		ParticlePool:      make([]Instance, 10),
 		SpawnInterval:     1,
@@ -65,7 +66,7 @@ func TestSpawnAppliesEmissionScaleWithoutMutatingBaseValues(t *testing.T) {
 	}
 
 	for range 3 {
-		sys.spawn(data)
+		sys.spawn(data, defaultDeltaTime)
 	}
 
 	if got := data.ActiveCount; got != 5 {
@@ -77,7 +78,7 @@ func TestSpawnAppliesEmissionScaleWithoutMutatingBaseValues(t *testing.T) {
 }
 
 func TestSpawnCarriesFractionalEmissionAtLowScale(t *testing.T) {
-	sys := &System{cnt: 0}
+	sys := &System{}
 	data := &SystemData{
 		ParticlePool:      make([]Instance, 4),
 		SpawnInterval:     1,
@@ -90,18 +91,141 @@ func TestSpawnCarriesFractionalEmissionAtLowScale(t *testing.T) {
 		},
 	}
 
-	sys.spawn(data)
+	sys.spawn(data, defaultDeltaTime)
 	if got := data.ActiveCount; got != 0 {
 		t.Fatalf("first spawn tick active count got %d, want 0", got)
 	}
-	sys.spawn(data)
+	sys.spawn(data, defaultDeltaTime)
 	if got := data.ActiveCount; got != 1 {
 		t.Fatalf("second spawn tick active count got %d, want 1", got)
 	}
 }
 
+func TestSpawnEmissionIsIndependentOfTPS(t *testing.T) {
+	for _, spawn := range []struct {
+		name              string
+		interval          int
+		particlesPerSpawn int
+		rate              float32
+		want              int
+	}{
+		{name: "interval", interval: 3, particlesPerSpawn: 2, want: 40},
+		{name: "rate", rate: 45, want: 45},
+	} {
+		for _, tps := range []int{30, 60, 144} {
+			sys := &System{}
+			data := &SystemData{
+				ParticlePool:      make([]Instance, 256),
+				SpawnInterval:     spawn.interval,
+				ParticlesPerSpawn: spawn.particlesPerSpawn,
+				SpawnRate:         spawn.rate,
+				MaxParticles:      256,
+				EmissionScale:     1,
+				IsLoop:            true,
+				AnimParams: AnimationParams{
+					Duration: DurationParams{Base: 10},
+				},
+			}
+
+			deltaTime := float32(1.0 / float64(tps))
+			for range tps {
+				sys.spawn(data, deltaTime)
+			}
+			// Accumulated float error may defer the final tick by one frame.
+			if got := data.ActiveCount; got < spawn.want-spawn.particlesPerSpawn-1 || got > spawn.want {
+				t.Fatalf("%s at %d TPS: one second emitted %d, want %d", spawn.name, tps, got, spawn.want)
+			}
+		}
+	}
+}
+
+func physicsSystemForTest(pos PositionParams) *SystemData {
+	pos.UsePhysics = true
+	return &SystemData{
+		ParticlePool:      make([]Instance, 1),
+		SpawnInterval:     1,
+		ParticlesPerSpawn: 1,
+		MaxParticles:      1,
+		EmissionScale:     1,
+		IsLoop:            true,
+		AnimParams: AnimationParams{
+			Duration: DurationParams{Base: 10},
+			Position: pos,
+		},
+	}
+}
+
+func TestPhysicsModeLaunchesAlongCone(t *testing.T) {
+	sys := &System{}
+	data := physicsSystemForTest(PositionParams{
+		AngleMin: -math.Pi / 2, AngleMax: -math.Pi / 2,
+		SpeedMin: 200, SpeedMax: 200,
+		DistMin: 10, DistMax: 10,
+		VelXMin: 30, VelXMax: 30,
+	})
+	data.EmitterX, data.EmitterY = 100, 100
+
+	sys.spawn(data, defaultDeltaTime)
+
+	p := &data.ParticlePool[0]
+	if !p.Integrated {
+		t.Fatal("expected physics particle to use integrated motion")
+	}
+	if math.Abs(float64(p.PosY-90)) > 1e-3 || math.Abs(float64(p.PosX-100)) > 1e-3 {
+		t.Fatalf("spawn offset got (%v, %v), want (100, 90)", p.PosX, p.PosY)
+	}
+	if math.Abs(float64(p.VelX-30)) > 1e-2 || math.Abs(float64(p.VelY+200)) > 1e-2 {
+		t.Fatalf("launch velocity got (%v, %v), want (30, -200)", p.VelX, p.VelY)
+	}
+}
+
+func TestPhysicsModeIntegratesGravityDragAndTerminalSpeed(t *testing.T) {
+	sys := &System{}
+	data := physicsSystemForTest(PositionParams{AccelY: 600, TerminalSpeed: 100})
+	sys.spawn(data, defaultDeltaTime)
+	p := &data.ParticlePool[0]
+
+	lastY := p.PosY
+	for range 60 {
+		data.CurrentTime += defaultDeltaTime
+		sys.updateParticles(data, defaultDeltaTime)
+		if p.CurrentY < lastY {
+			t.Fatalf("falling particle moved up: %v -> %v", lastY, p.CurrentY)
+		}
+		lastY = p.CurrentY
+	}
+	if math.Abs(float64(p.VelY-100)) > 1e-3 {
+		t.Fatalf("velocity got %v, want terminal speed 100", p.VelY)
+	}
+
+	data.AnimParams.Position.LinearDrag = 2
+	data.AnimParams.Position.AccelY = 0
+	before := p.VelY
+	data.CurrentTime += defaultDeltaTime
+	sys.updateParticles(data, defaultDeltaTime)
+	if want := before / (1 + 2*defaultDeltaTime); math.Abs(float64(p.VelY-want)) > 1e-3 {
+		t.Fatalf("linear drag velocity got %v, want %v", p.VelY, want)
+	}
+}
+
+func TestAdvanceLifeTimeCountsReferenceFrames(t *testing.T) {
+	for _, tps := range []int{30, 60, 144} {
+		data := &SystemData{LifeTime: 30}
+		deltaTime := float32(1.0 / float64(tps))
+		updates := 0
+		for data.LifeTime > 0 {
+			advanceLifeTime(data, deltaTime)
+			updates++
+		}
+		want := tps / 2
+		if updates < want || updates > want+1 {
+			t.Fatalf("at %d TPS life_time 30 lasted %d updates, want %d", tps, updates, want)
+		}
+	}
+}
+
 func TestSpawnHonorsExplicitZeroEmissionScale(t *testing.T) {
-	sys := &System{cnt: 0}
+	sys := &System{}
 	data := &SystemData{
 		ParticlePool:      make([]Instance, 4),
 		SpawnInterval:     1,
@@ -111,14 +235,14 @@ func TestSpawnHonorsExplicitZeroEmissionScale(t *testing.T) {
 		IsLoop:            true,
 	}
 
-	sys.spawn(data)
+	sys.spawn(data, defaultDeltaTime)
 	if got := data.ActiveCount; got != 0 {
 		t.Fatalf("active count got %d, want 0 for explicit zero scale", got)
 	}
 }
 
 func TestSpawnFollowsRuntimeEmissionScaleChanges(t *testing.T) {
-	sys := &System{cnt: 0}
+	sys := &System{}
 	data := &SystemData{
 		ParticlePool:      make([]Instance, 8),
 		SpawnInterval:     1,
@@ -131,25 +255,25 @@ func TestSpawnFollowsRuntimeEmissionScaleChanges(t *testing.T) {
 		},
 	}
 
-	sys.spawn(data)
+	sys.spawn(data, defaultDeltaTime)
 	if got := data.ActiveCount; got != 2 {
 		t.Fatalf("full-scale active count got %d, want 2", got)
 	}
 
 	data.EmissionScale = 0
-	sys.spawn(data)
+	sys.spawn(data, defaultDeltaTime)
 	if got := data.ActiveCount; got != 2 {
 		t.Fatalf("paused active count got %d, want 2", got)
 	}
 
 	data.EmissionScale = 0.5
-	sys.spawn(data)
+	sys.spawn(data, defaultDeltaTime)
 	if got := data.ActiveCount; got != 3 {
 		t.Fatalf("half-scale active count got %d, want 3", got)
 	}
 
 	data.EmissionScale = 1
-	sys.spawn(data)
+	sys.spawn(data, defaultDeltaTime)
 	if got := data.ActiveCount; got != 5 {
 		t.Fatalf("restored active count got %d, want 5", got)
 	}
@@ -301,6 +425,7 @@ func TestUpdateRemovesExpiredOneShotEntity(t *testing.T) {
 		IsLoop:        false,
 		LifeTime:      1,
 		SpawnInterval: 0,
+		TimeScale:     1,
 	})
 
 	sys.Update(gameECS)
@@ -310,6 +435,86 @@ func TestUpdateRemovesExpiredOneShotEntity(t *testing.T) {
 	}
 }
 
+func TestUpdateAppliesEntityAndSystemTimeScale(t *testing.T) {
+	world := donburi.NewWorld()
+	gameECS := ecs.NewECS(world)
+	sys := NewSystem()
+
+	entity := world.Create(Component)
+	entry := world.Entry(entity)
+	donburi.SetValue(entry, Component, SystemData{
+		ParticlePool: []Instance{
+			{Active: true, Duration: 1, EndX: 60, PositionEasing: EasingLinear},
+		},
+		ActiveCount:       1,
+		IsLoop:            true,
+		SpawnInterval:     1,
+		ParticlesPerSpawn: 1,
+		MaxParticles:      1,
+		EmissionScale:     1,
+		TimeScale:         0.5,
+	})
+	data := Component.Get(entry)
+
+	sys.Update(gameECS)
+	if got, want := data.CurrentTime, defaultDeltaTime*0.5; math.Abs(float64(got-want)) > 1e-6 {
+		t.Fatalf("half-speed entity time got %v, want %v", got, want)
+	}
+
+	sys.SetTimeScale(0)
+	timeBefore := data.CurrentTime
+	before := data.ParticlePool[0]
+	for range 10 {
+		sys.Update(gameECS)
+	}
+	if data.CurrentTime != timeBefore {
+		t.Fatalf("paused system advanced entity time to %v", data.CurrentTime)
+	}
+	if data.ParticlePool[0].CurrentX != before.CurrentX {
+		t.Fatalf("paused particle moved from %v to %v", before.CurrentX, data.ParticlePool[0].CurrentX)
+	}
+
+	sys.SetTimeScale(-3)
+	if got := sys.TimeScale(); got != 0 {
+		t.Fatalf("negative system time scale got %v, want 0", got)
+	}
+	sys.SetTimeScale(2)
+	sys.Update(gameECS)
+	if got, want := data.CurrentTime, defaultDeltaTime*1.5; math.Abs(float64(got-want)) > 1e-6 {
+		t.Fatalf("combined time scale time got %v, want %v", got, want)
+	}
+}
+
+func TestUpdateParticlesSkipsFlowWhenFrozen(t *testing.T) {
+	sys := &System{}
+	data := &SystemData{
+		ParticlePool: []Instance{
+			{Active: true, Duration: 10, HasFlow: true, FlowGain: 24, FlowVelX: 5, FlowVelY: -5},
+		},
+		ActiveCount: 1,
+		CurrentTime: 0.5,
+		AnimParams: AnimationParams{
+			Position: PositionParams{
+				HasFlow:         true,
+				FlowStrengthMin: 24,
+				FlowStrengthMax: 24,
+				FlowScale:       160,
+				FlowOctaves:     2,
+				FlowPersistence: 0.5,
+				FlowTimeScale:   0.3,
+				FlowDrag:        0.5,
+			},
+		},
+	}
+
+	sys.updateParticles(data, 0)
+
+	p := data.ParticlePool[0]
+	if p.FlowVelX != 5 || p.FlowVelY != -5 || p.FlowOffsetX != 0 {
+		t.Fatalf("frozen step changed flow state: vel=(%v,%v) offset=%v", p.FlowVelX, p.FlowVelY, p.FlowOffsetX)
+	}
+}
+
 func TestUpdateTrailTracksEmitterHistory(t *testing.T) {
 	data := &SystemData{
 		CurrentTime: 0,
@@ -486,7 +691,7 @@ func TestExpiredParticleTrailReusesGhostPointBuffers(t *testing.T) {
 		},
 		Runtime: trail,
 	}
-	detachParticleTrail(&data, []TrailPoint{{X: 2, CapturedAt: 0.3}, {X: 6, CapturedAt: 0.4}})
+	detachParticleTrail(&data, []TrailPoint{{X: 2, CapturedAt: 0.3}, {X: 6, CapturedAt: 0.4}}, 1)
 	if len(data.Runtime.Ghosts) != 1 {
 		t.Fatalf("expected detached ghost, got %d", len(data.Runtime.Ghosts))
 	}
@@ -499,7 +704,7 @@ func TestExpiredParticleTrailReusesGhostPointBuffers(t *testing.T) {
 }
 
 func TestSpawnCircleEmitterSamplesInsideRadius(t *testing.T) {
-	sys := &System{cnt: 0}
+	sys := &System{}
 	data := &SystemData{
 		ParticlePool:      make([]Instance, 32),
 		SpawnInterval:     1,
@@ -532,7 +737,7 @@ func TestSpawnCircleEmitterSamplesInsideRadius(t *testing.T) {
 		},
 	}
 
-	sys.spawn(data)
+	sys.spawn(data, defaultDeltaTime)
 
 	for idx := 0; idx < data.ActiveCount; idx++ {
 		p := data.ParticlePool[idx]
@@ -546,7 +751,7 @@ func TestSpawnCircleEmitterSamplesInsideRadius(t *testing.T) {
 }
 
 func TestSpawnLineEmitterRespectsRotation(t *testing.T) {
-	sys := &System{cnt: 0}
+	sys := &System{}
 	data := &SystemData{
 		ParticlePool:      make([]Instance, 16),
 		SpawnInterval:     1,
@@ -579,7 +784,7 @@ func TestSpawnLineEmitterRespectsRotation(t *testing.T) {
 		},
 	}
 
-	sys.spawn(data)
+	sys.spawn(data, defaultDeltaTime)
 
 	for idx := 0; idx < data.ActiveCount; idx++ {
 		p := data.ParticlePool[idx]
@@ -593,7 +798,7 @@ func TestSpawnLineEmitterRespectsRotation(t *testing.T) {
 }
 
 func TestSpawnRectVectorFillDistributesAcrossArea(t *testing.T) {
-	sys := &System{cnt: 0}
+	sys := &System{}
 	data := &SystemData{
 		ParticlePool:      make([]Instance, 9),
 		SpawnInterval:     1,
@@ -623,7 +828,7 @@ func TestSpawnRectVectorFillDistributesAcrossArea(t *testing.T) {
 		},
 	}
 
-	sys.spawn(data)
+	sys.spawn(data, defaultDeltaTime)
 
 	seenCols := map[int]bool{}
 	seenRows := map[int]bool{}
@@ -645,7 +850,7 @@ func TestSpawnRectVectorFillDistributesAcrossArea(t *testing.T) {
 }
 
 func TestSpawnRectVectorSurfaceStaysOnPerimeter(t *testing.T) {
-	sys := &System{cnt: 0}
+	sys := &System{}
 	data := &SystemData{
 		ParticlePool:      make([]Instance, 8),
 		SpawnInterval:     1,
@@ -675,7 +880,7 @@ func TestSpawnRectVectorSurfaceStaysOnPerimeter(t *testing.T) {
 		},
 	}
 
-	sys.spawn(data)
+	sys.spawn(data, defaultDeltaTime)
 
 	for idx := 0; idx < data.ActiveCount; idx++ {
 		p := data.ParticlePool[idx]
@@ -690,7 +895,7 @@ func TestSpawnRectVectorSurfaceStaysOnPerimeter(t *testing.T) {
 }
 
 func TestSpawnPolylineVectorSurfaceStaysOnSegments(t *testing.T) {
-	sys := &System{cnt: 0}
+	sys := &System{}
 	data := &SystemData{
 		ParticlePool:      make([]Instance, 6),
 		SpawnInterval:     1,
@@ -726,7 +931,7 @@ func TestSpawnPolylineVectorSurfaceStaysOnSegments(t *testing.T) {
 		},
 	}
 
-	sys.spawn(data)
+	sys.spawn(data, defaultDeltaTime)
 
 	for idx := 0; idx < data.ActiveCount; idx++ {
 		p := data.ParticlePool[idx]
@@ -761,7 +966,7 @@ func TestBuildEmitterVectorPolylineParamsQuadraticCompilesCurve(t *testing.T) {
 }
 
 func TestSpawnCircleEmitterArcLimitsAngle(t *testing.T) {
-	sys := &System{cnt: 0}
+	sys := &System{}
 	data := &SystemData{
 		ParticlePool:      make([]Instance, 16),
 		SpawnInterval:     1,
@@ -795,7 +1000,7 @@ func TestSpawnCircleEmitterArcLimitsAngle(t *testing.T) {
 		},
 	}
 
-	sys.spawn(data)
+	sys.spawn(data, defaultDeltaTime)
 
 	for idx := 0; idx < data.ActiveCount; idx++ {
 		p := data.ParticlePool[idx]
@@ -806,7 +1011,7 @@ func TestSpawnCircleEmitterArcLimitsAngle(t *testing.T) {
 }
 
 func TestSpawnBoxEmitterFromEdgeStaysOnPerimeter(t *testing.T) {
-	sys := &System{cnt: 0}
+	sys := &System{}
 	data := &SystemData{
 		ParticlePool:      make([]Instance, 16),
 		SpawnInterval:     1,
@@ -840,7 +1045,7 @@ func TestSpawnBoxEmitterFromEdgeStaysOnPerimeter(t *testing.T) {
 		},
 	}
 
-	sys.spawn(data)
+	sys.spawn(data, defaultDeltaTime)
 
 	for idx := 0; idx < data.ActiveCount; idx++ {
 		p := data.ParticlePool[idx]
@@ -855,7 +1060,7 @@ func TestSpawnBoxEmitterFromEdgeStaysOnPerimeter(t *testing.T) {
 }
 
 func TestSpawnCircleEmitterFullCircleWithTwoPiEndAngle(t *testing.T) {
-	sys := &System{cnt: 0}
+	sys := &System{}
 	data := &SystemData{
 		ParticlePool:      make([]Instance, 128),
 		SpawnInterval:     1,
@@ -889,7 +1094,7 @@ func TestSpawnCircleEmitterFullCircleWithTwoPiEndAngle(t *testing.T) {
 		},
 	}
 
-	sys.spawn(data)
+	sys.spawn(data, defaultDeltaTime)
 
 	var hasNegX, hasPosX, hasNegY, hasPosY bool
 	for idx := 0; idx < data.ActiveCount; idx++ {
@@ -913,7 +1118,7 @@ func TestSpawnCircleEmitterFullCircleWithTwoPiEndAngle(t *testing.T) {
 }
 
 func TestSpawnCircleEmitterTreatsSixPointTwoEightAsFullCircle(t *testing.T) {
-	sys := &System{cnt: 0}
+	sys := &System{}
 	data := &SystemData{
 		ParticlePool:      make([]Instance, 128),
 		SpawnInterval:     1,
@@ -947,7 +1152,7 @@ func TestSpawnCircleEmitterTreatsSixPointTwoEightAsFullCircle(t *testing.T) {
 		},
 	}
 
-	sys.spawn(data)
+	sys.spawn(data, defaultDeltaTime)
 
 	var hasNegX, hasPosX, hasNegY, hasPosY bool
 	for idx := 0; idx < data.ActiveCount; idx++ {
@@ -971,7 +1176,7 @@ func TestSpawnCircleEmitterTreatsSixPointTwoEightAsFullCircle(t *testing.T) {
 }
 
 func TestSpawnCircleEmitterWrapArc(t *testing.T) {
-	sys := &System{cnt: 0}
+	sys := &System{}
 	data := &SystemData{
 		ParticlePool:      make([]Instance, 64),
 		SpawnInterval:     1,
@@ -1005,7 +1210,7 @@ func TestSpawnCircleEmitterWrapArc(t *testing.T) {
 		},
 	}
 
-	sys.spawn(data)
+	sys.spawn(data, defaultDeltaTime)
 
 	for idx := 0; idx < data.ActiveCount; idx++ {
 		p := data.ParticlePool[idx]
@@ -1027,7 +1232,7 @@ func TestAssignParticleColorVariation(t *testing.T) {
 	}
 	var p Instance
 	for i := 0; i < 32; i++ {
-		assignParticleColor(&p, clr)
+		assignParticleColor(&p, clr, nil)
 		if p.ColorVariationMix < 0 || p.ColorVariationMix >= 1 {
 			t.Fatalf("variation mix out of range: %v", p.ColorVariationMix)
 		}
@@ -1042,7 +1247,7 @@ func TestAssignParticleColorVariation(t *testing.T) {
 	}
 
 	clr.HasVariation = false
-	assignParticleColor(&p, clr)
+	assignParticleColor(&p, clr, nil)
 	if p.ColorVariationMix != 0 {
 		t.Fatalf("variation mix was not reset: %v", p.ColorVariationMix)
 	}
@@ -1050,3 +1255,186 @@ func TestAssignParticleColorVariation(t *testing.T) {
 		t.Fatalf("base color not applied verbatim: %+v", p)
 	}
 }
+
+func TestSeededSystemsProduceIdenticalParticleState(t *testing.T) {
+	seed := uint64(42)
+	cfg := validParticleConfigForTest()
+	cfg.Seed = &seed
+	cfg.Emitter.Shape = EmitterShapeConfig{Type: "circle", Radius: &RangeFloat{Min: 10, Max: 40}}
+	cfg.Animation.Duration = DurationConfig{Range: &RangeFloat{Min: 0.2, Max: 0.6}}
+	cfg.Animation.Position = PositionConfig{
+		Type:     "polar",
+		Angle:    &RangeFloat{Min: 0, Max: 6.28},
+		Distance: &RangeFloat{Min: 20, Max: 80},
+		Flow:     &FlowConfig{Strength: &RangeFloat{Min: 10, Max: 30}, BoundRadius: 30, RespawnOnEscape: true},
+		Easing:   "OutQuad",
+	}
+	cfg.Animation.Alpha = PropertyConfig{Type: "sequence", Steps: []StepConfig{
+		{From: 0, To: 1, ToRange: &RangeFloat{Min: 0.5, Max: 1}, Duration: 0.3},
+	}}
+	cfg.Animation.Color = &ColorConfig{StartR: 1, EndG: 1, Variation: &ColorConfig{StartB: 1, EndB: 1}}
+	cfg.Spawn.ParticlesPerSpawn = 8
+	cfg.Spawn.MaxParticles = 64
+
+	run := func() []Instance {
+		sys := NewSystem()
+		data := buildSystemDataFromConfig(nil, nil, cfg, 100, 100)
+		buildSequenceConfigs(cfg, &data)
+		for range 40 {
+			data.CurrentTime += defaultDeltaTime
+			sys.spawn(&data, defaultDeltaTime)
+			sys.updateParticles(&data, defaultDeltaTime)
+		}
+		return data.ParticlePool[:data.ActiveCount]
+	}
+
+	a := run()
+	b := run()
+	if len(a) == 0 {
+		t.Fatal("expected active particles")
+	}
+	if !reflect.DeepEqual(a, b) {
+		t.Fatal("same seed produced different particle state")
+	}
+
+	other := uint64(43)
+	cfg.Seed = &other
+	if c := run(); reflect.DeepEqual(a, c) {
+		t.Fatal("different seeds produced identical particle state")
+	}
+}
+
+func TestAlignParticleToVelocityFollowsMotion(t *testing.T) {
+	data := &SystemData{CurrentTime: 1}
+	p := &Instance{
+		PrevX: 0, PrevY: 0, PrevPosTime: 0.5,
+		CurrentX: 0, CurrentY: 50, CurrentPosTime: 1,
+		Duration: 2,
+	}
+
+	rotation, halfW := alignParticleToVelocity(data, p, 0.01, 0.25, 4)
+	if math.Abs(float64(rotation-(math.Pi/2+0.25))) > 1e-5 {
+		t.Fatalf("rotation got %v, want heading pi/2 plus the 0.25 offset", rotation)
+	}
+	// Speed is 100 units/sec, so the quad stretches to 1 + 0.01*100 = 2x.
+	if math.Abs(float64(halfW-8)) > 1e-4 {
+		t.Fatalf("stretched half width got %v, want 8", halfW)
+	}
+
+	// At rest the particle keeps its last heading and is not stretched.
+	p.PrevY, p.PrevPosTime, p.CurrentPosTime = 50, 1, 1.5
+	data.CurrentTime = 1.5
+	rotation, halfW = alignParticleToVelocity(data, p, 0.01, 0, 4)
+	if math.Abs(float64(rotation-math.Pi/2)) > 1e-5 || halfW != 4 {
+		t.Fatalf("at rest got rotation %v and half width %v, want pi/2 and 4", rotation, halfW)
+	}
+}
+
+func TestSpawnRateOverDistanceSpacesParticlesAlongPath(t *testing.T) {
+	sys := &System{}
+	data := &SystemData{
+		ParticlePool:          make([]Instance, 64),
+		SpawnRateOverDistance: 0.1,
+		MaxParticles:          64,
+		EmissionScale:         1,
+		IsLoop:                true,
+		AnimParams: AnimationParams{
+			Duration: DurationParams{Base: 10},
+		},
+	}
+
+	sys.spawn(data, defaultDeltaTime)
+	if data.ActiveCount != 0 {
+		t.Fatalf("a stationary emitter emitted %d particles", data.ActiveCount)
+	}
+
+	// 35 + 65 units: the carried 5 units put the second segment's first
+	// particle 5 units in, keeping a 10 unit spacing across updates.
+	moveEmitterTo(data, 35, 0)
+	sys.spawn(data, defaultDeltaTime)
+	moveEmitterTo(data, 100, 0)
+	sys.spawn(data, defaultDeltaTime)
+
+	if data.ActiveCount != 10 {
+		t.Fatalf("active count got %d, want 10", data.ActiveCount)
+	}
+	for i := 0; i < data.ActiveCount; i++ {
+		p := &data.ParticlePool[i]
+		if want := float32(10 * (i + 1)); math.Abs(float64(p.StartX-want)) > 1e-3 || p.StartY != 0 {
+			t.Fatalf("particle %d spawned at (%v, %v), want (%v, 0)", i, p.StartX, p.StartY, want)
+		}
+	}
+}
+
+func TestEmitterMotionOrbitsOriginWithoutMovingLocalParticles(t *testing.T) {
+	data := &SystemData{
+		ParticlePool:      make([]Instance, 1),
+		EmitterX:          100,
+		EmitterY:          50,
+		EmitterLocalSpace: true,
+		ActiveCount:       1,
+	}
+	data.ParticlePool[0].CurrentX = 100
+	setEmitterMotion(data, EmitterMotionParams{Type: EmitterMotionOrbit, Radius: 10, AngularSpeed: math.Pi})
+
+	if data.EmitterX != 110 || data.EmitterY != 50 {
+		t.Fatalf("emitter at t=0 got (%v, %v), want (110, 50)", data.EmitterX, data.EmitterY)
+	}
+	data.CurrentTime = 0.5
+	updateEmitterMotion(data)
+	if math.Abs(float64(data.EmitterX-100)) > 1e-3 || math.Abs(float64(data.EmitterY-60)) > 1e-3 {
+		t.Fatalf("emitter at t=0.5 got (%v, %v), want (100, 60)", data.EmitterX, data.EmitterY)
+	}
+	if data.ParticlePool[0].CurrentX != 100 {
+		t.Fatalf("motion moved a local-space particle to x=%v", data.ParticlePool[0].CurrentX)
+	}
+
+	// Moving the origin carries the orbit and local-space particles along.
+	world := donburi.NewWorld()
+	entity := world.Create(Component)
+	Component.SetValue(world.Entry(entity), *data)
+	SetEmitterPosition(world, entity, 200, 50)
+	moved := Component.Get(world.Entry(entity))
+	if math.Abs(float64(moved.EmitterX-200)) > 1e-3 || math.Abs(float64(moved.EmitterY-60)) > 1e-3 {
+		t.Fatalf("emitter after SetEmitterPosition got (%v, %v), want (200, 60)", moved.EmitterX, moved.EmitterY)
+	}
+	if moved.ParticlePool[0].CurrentX != 200 {
+		t.Fatalf("local-space particle x got %v, want 200", moved.ParticlePool[0].CurrentX)
+	}
+}
+
+func TestEmitterMotionPathLoopAndPingPong(t *testing.T) {
+	path := buildEmitterVectorPolylineParams(&EmitterVectorPolylineConfig{
+		Points: []EmitterVectorPoint{{X: 0, Y: 0}, {X: 100, Y: 0}},
+	})
+	loop := EmitterMotionParams{Type: EmitterMotionPath, Path: path, Speed: 100}
+	pingPong := loop
+	pingPong.PingPong = true
+
+	tests := []struct {
+		motion  EmitterMotionParams
+		t       float32
+		wantX   float32
+		wantLap int
+	}{
+		{loop, 0.25, 25, 0},
+		{loop, 1.25, 25, 1},
+		{pingPong, 0.25, 25, 0},
+		{pingPong, 1.25, 75, 0},
+	}
+	for _, tt := range tests {
+		x, y, lap := tt.motion.offset(tt.t)
+		if math.Abs(float64(x-tt.wantX)) > 1e-3 || y != 0 || lap != tt.wantLap {
+			t.Fatalf("ping_pong=%v t=%v: got (%v, %v) lap %d, want (%v, 0) lap %d",
+				tt.motion.PingPong, tt.t, x, y, lap, tt.wantX, tt.wantLap)
+		}
+	}
+
+	// Wrapping an open loop must not emit along the jump back to the start.
+	data := &SystemData{EmitterMotion: loop, emitterPathValid: true}
+	data.CurrentTime = 1.1
+	updateEmitterMotion(data)
+	if data.emitterPathValid {
+		t.Fatal("wrapping an open path kept the rate_over_distance path valid")
+	}
+}
@@ -0,0 +1,213 @@
+package chirashi
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/go-text/typesetting/font"
+	"github.com/go-text/typesetting/font/opentype"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+// FontRegistry maps EmitterVectorTextConfig.Font names to font sources for
+// text vector emitters. It is safe for concurrent use.
+type FontRegistry struct {
+	mutex   sync.RWMutex
+	sources map[string]*text.GoTextFaceSource
+}
+
+// NewFontRegistry creates an empty font registry.
+func NewFontRegistry() *FontRegistry {
+	return &FontRegistry{sources: make(map[string]*text.GoTextFaceSource)}
+}
+
+// Register stores source as font: name. name may be empty for presets that
+// do not set a font.
+func (r *FontRegistry) Register(name string, source *text.GoTextFaceSource) {
+	r.mutex.Lock()
+	r.sources[name] = source
+	r.mutex.Unlock()
+}
+
+// Lookup returns the font source registered as name.
+func (r *FontRegistry) Lookup(name string) (*text.GoTextFaceSource, bool) {
+	if r == nil {
+		return nil, false
+	}
+	r.mutex.RLock()
+	source, ok := r.sources[name]
+	r.mutex.RUnlock()
+	return source, ok && source != nil
+}
+
+// NewTextVector builds a polyline vector emitter from the glyph outlines of
+// value laid out with face. placement is "surface" for glyph outlines or
+// "fill" (the default) for glyph interiors; align is "left", "center" (the
+// default) or "right" relative to the emitter origin, and lines are centered
+// vertically on it. face must be a *text.GoTextFace, which has outlines.
+func NewTextVector(value string, face text.Face, placement string, align string) (*EmitterVectorConfig, error) {
+	polylines, err := layoutTextOutlines(value, face, parseTextAlign(align))
+	if err != nil {
+		return nil, err
+	}
+	if placement == "" {
+		placement = "fill"
+	}
+	vectorConfig := &EmitterVectorConfig{
+		Type:      "polyline",
+		Placement: placement,
+		Polyline:  &polylines[0],
+	}
+	if len(polylines) > 1 {
+		vectorConfig.Polylines = polylines[1:]
+	}
+	return vectorConfig, nil
+}
+
+// resolveTextVector turns a text vector config into the polyline vector it
+// samples, looking the font up in fonts.
+func resolveTextVector(config *EmitterVectorConfig, fonts *FontRegistry) (*EmitterVectorConfig, error) {
+	if config.Text == nil {
+		return nil, fmt.Errorf("emitter.vector.text is required")
+	}
+	source, ok := fonts.Lookup(config.Text.Font)
+	if !ok {
+		return nil, fmt.Errorf("emitter.vector.text.font %q is not registered", config.Text.Font)
+	}
+	face := &text.GoTextFace{Source: source, Size: float64(config.Text.Size)}
+	vectorConfig, err := NewTextVector(config.Text.Value, face, config.Placement, config.Text.Align)
+	if err != nil {
+		return nil, fmt.Errorf("emitter.vector.text: %w", err)
+	}
+	return vectorConfig, nil
+}
+
+func parseTextAlign(align string) text.Align {
+	switch align {
+	case "left":
+		return text.AlignStart
+	case "right":
+		return text.AlignEnd
+	default:
+		return text.AlignCenter
+	}
+}
+
+// textOutlineCurveSteps is the number of segments per glyph quadratic or
+// cubic curve. Glyph curves are short, so a few steps keep outlines smooth.
+const textOutlineCurveSteps = 4
+
+// layoutTextOutlines flattens the glyph contours of value into closed
+// polylines, with counters such as the inside of "O" as holes. Contours are
+// read from the font's glyph outlines and placed at the laid-out glyph
+// origins.
+func layoutTextOutlines(value string, face text.Face, align text.Align) ([]EmitterVectorPolylineConfig, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, fmt.Errorf("text is empty")
+	}
+	goFace, ok := face.(*text.GoTextFace)
+	if !ok || goFace.Source == nil {
+		return nil, fmt.Errorf("text face has no glyph outlines; use a GoTextFace")
+	}
+	fontFace, ok := goFace.Source.UnsafeInternal().(*font.Face)
+	if !ok {
+		return nil, fmt.Errorf("text face has no glyph outlines")
+	}
+	metrics := face.Metrics()
+	glyphs := text.AppendGlyphs(nil, value, face, &text.LayoutOptions{
+		LineSpacing:    metrics.HLineGap + metrics.HAscent + metrics.HDescent,
+		PrimaryAlign:   align,
+		SecondaryAlign: text.AlignCenter,
+	})
+
+	// Font units are y-up; the emitter is y-down like the screen.
+	scale := float32(goFace.Size) / float32(fontFace.Upem())
+	var subpaths []outlineSubpath
+	for _, glyph := range glyphs {
+		originX := float32(glyph.OriginX + glyph.OriginOffsetX)
+		originY := float32(glyph.OriginY + glyph.OriginOffsetY)
+		for _, points := range flattenGlyphSegments(glyphSegments(fontFace, glyph.GID)) {
+			for i, pt := range points {
+				points[i] = EmitterVectorPoint{X: pt.X*scale + originX, Y: -pt.Y*scale + originY}
+			}
+			points = dedupeOutlinePoints(points, true)
+			if len(points) < 3 {
+				continue
+			}
+			params := outlineRingParams(points)
+			if polygonSignedArea(params) == 0 {
+				continue
+			}
+			subpaths = append(subpaths, outlineSubpath{points: points, closed: true, params: params})
+		}
+	}
+	if len(subpaths) == 0 {
+		return nil, fmt.Errorf("text has no glyph outlines")
+	}
+	return nestOutlineSubpaths(subpaths), nil
+}
+
+// glyphSegments returns the outline of gid, including the fallback outline
+// of SVG and bitmap glyphs. Spaces and control characters have none.
+func glyphSegments(fontFace *font.Face, gid uint32) []font.Segment {
+	switch data := fontFace.GlyphData(font.GID(gid)).(type) {
+	case font.GlyphOutline:
+		return data.Segments
+	case font.GlyphSVG:
+		return data.Outline.Segments
+	case font.GlyphBitmap:
+		if data.Outline != nil {
+			return data.Outline.Segments
+		}
+	}
+	return nil
+}
+
+// flattenGlyphSegments splits glyph outline segments into contours of points
+// in font units. Every glyph contour is closed.
+func flattenGlyphSegments(segments []font.Segment) [][]EmitterVectorPoint {
+	var contours [][]EmitterVectorPoint
+	var current []EmitterVectorPoint
+	flush := func() {
+		if len(current) > 0 {
+			contours = append(contours, current)
+		}
+		current = nil
+	}
+	for _, seg := range segments {
+		var last EmitterVectorPoint
+		if len(current) > 0 {
+			last = current[len(current)-1]
+		}
+		switch seg.Op {
+		case opentype.SegmentOpMoveTo:
+			flush()
+			current = []EmitterVectorPoint{{X: seg.Args[0].X, Y: seg.Args[0].Y}}
+		case opentype.SegmentOpLineTo:
+			current = append(current, EmitterVectorPoint{X: seg.Args[0].X, Y: seg.Args[0].Y})
+		case opentype.SegmentOpQuadTo:
+			c, end := seg.Args[0], seg.Args[1]
+			for step := 1; step <= textOutlineCurveSteps; step++ {
+				t := float32(step) / textOutlineCurveSteps
+				u := 1 - t
+				current = append(current, EmitterVectorPoint{
+					X: u*u*last.X + 2*u*t*c.X + t*t*end.X,
+					Y: u*u*last.Y + 2*u*t*c.Y + t*t*end.Y,
+				})
+			}
+		case opentype.SegmentOpCubeTo:
+			c1, c2, end := seg.Args[0], seg.Args[1], seg.Args[2]
+			for step := 1; step <= textOutlineCurveSteps; step++ {
+				t := float32(step) / textOutlineCurveSteps
+				u := 1 - t
+				current = append(current, EmitterVectorPoint{
+					X: u*u*u*last.X + 3*u*u*t*c1.X + 3*u*t*t*c2.X + t*t*t*end.X,
+					Y: u*u*u*last.Y + 3*u*u*t*c1.Y + 3*u*t*t*c2.Y + t*t*t*end.Y,
+				})
+			}
+		}
+	}
+	flush()
+	return contours
+}
@@ -0,0 +1,183 @@
+package chirashi
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2/examples/resources/fonts"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/yohamta/donburi"
+	"github.com/yohamta/donburi/filter"
+)
+
+func newTestFontSource(t *testing.T) *text.GoTextFaceSource {
+	t.Helper()
+	source, err := text.NewGoTextFaceSource(bytes.NewReader(fonts.MPlus1pRegular_ttf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return source
+}
+
+func textVectorBounds(vectorConfig *EmitterVectorConfig) (minX, minY, maxX, maxY float32) {
+	minX, minY = float32(math.Inf(1)), float32(math.Inf(1))
+	maxX, maxY = float32(math.Inf(-1)), float32(math.Inf(-1))
+	polylines := append([]EmitterVectorPolylineConfig{*vectorConfig.Polyline}, vectorConfig.Polylines...)
+	for _, polyline := range polylines {
+		for _, pt := range polyline.Points {
+			minX, maxX = min(minX, pt.X), max(maxX, pt.X)
+			minY, maxY = min(minY, pt.Y), max(maxY, pt.Y)
+		}
+	}
+	return minX, minY, maxX, maxY
+}
+
+func TestNewTextVectorNestsGlyphCountersAsHoles(t *testing.T) {
+	face := &text.GoTextFace{Source: newTestFontSource(t), Size: 64}
+
+	vectorConfig, err := NewTextVector("O", face, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vectorConfig.Placement != "fill" || len(vectorConfig.Polylines) != 0 {
+		t.Fatalf("expected one filled outline, got placement %q and %d extra outlines", vectorConfig.Placement, len(vectorConfig.Polylines))
+	}
+	if !vectorConfig.Polyline.Closed || len(vectorConfig.Polyline.Holes) != 1 {
+		t.Fatalf("expected the counter of O as a hole, got %d holes", len(vectorConfig.Polyline.Holes))
+	}
+
+	params := buildEmitterVectorParams(vectorConfig)
+	outer := float32(math.Abs(float64(polygonSignedArea(outlineRingParams(vectorConfig.Polyline.Points)))))
+	hole := float32(math.Abs(float64(polygonSignedArea(outlineRingParams(vectorConfig.Polyline.Holes[0])))))
+	if !nearFloat(params.Polyline.TotalArea/(outer-hole), 1) {
+		t.Fatalf("expected fill area %v, got %v", outer-hole, params.Polyline.TotalArea)
+	}
+
+	surface, err := NewTextVector("x5", face, "surface", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := buildEmitterVectorParams(surface); len(got.Polylines) == 0 || got.Placement != EmitterVectorSurface {
+		t.Fatalf("expected each glyph as its own surface outline, got %+v", got)
+	}
+}
+
+func TestLayoutTextOutlinesReadsGlyphContours(t *testing.T) {
+	face := &text.GoTextFace{Source: newTestFontSource(t), Size: 64}
+	tests := []struct {
+		value    string
+		outlines int
+		holes    int
+	}{
+		{"O", 1, 1},
+		{"8", 1, 2},
+		{"i", 2, 0},
+		{"B", 1, 2},
+		{"Oi", 3, 1},
+		{"l l", 2, 0},
+	}
+	for _, tt := range tests {
+		polylines, err := layoutTextOutlines(tt.value, face, text.AlignCenter)
+		if err != nil {
+			t.Fatalf("%q: %v", tt.value, err)
+		}
+		holes := 0
+		for _, polyline := range polylines {
+			holes += len(polyline.Holes)
+		}
+		if len(polylines) != tt.outlines || holes != tt.holes {
+			t.Fatalf("%q: got %d outlines and %d holes, want %d and %d", tt.value, len(polylines), holes, tt.outlines, tt.holes)
+		}
+	}
+}
+
+func TestNewTextVectorAlignsAroundOrigin(t *testing.T) {
+	face := &text.GoTextFace{Source: newTestFontSource(t), Size: 48}
+
+	center, err := NewTextVector("LEVEL UP", face, "fill", "center")
+	if err != nil {
+		t.Fatal(err)
+	}
+	minX, minY, maxX, maxY := textVectorBounds(center)
+	if math.Abs(float64(minX+maxX)) > 8 {
+		t.Fatalf("expected centered text around x=0, got %v..%v", minX, maxX)
+	}
+	if minY >= 0 || maxY <= 0 {
+		t.Fatalf("expected text centered vertically on the origin, got %v..%v", minY, maxY)
+	}
+
+	left, err := NewTextVector("LEVEL UP", face, "fill", "left")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if minX, _, _, _ := textVectorBounds(left); minX < 0 {
+		t.Fatalf("expected left-aligned text to start at the origin, got minX %v", minX)
+	}
+	right, err := NewTextVector("LEVEL UP", face, "fill", "right")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, maxX, _ := textVectorBounds(right); maxX > 0 {
+		t.Fatalf("expected right-aligned text to end at the origin, got maxX %v", maxX)
+	}
+
+	if _, err := NewTextVector("   ", face, "fill", ""); err == nil {
+		t.Fatal("expected blank text to be rejected")
+	}
+}
+
+func TestParticleManagerResolvesTextVectorFont(t *testing.T) {
+	yaml := []byte(`
+name: combo
+emitter:
+  vector:
+    type: text
+    placement: fill
+    text:
+      value: "x5"
+      font: score
+      size: 40
+animation:
+  duration:
+    value: 1.0
+spawn:
+  interval: 1
+  particles_per_spawn: 4
+  max_particles: 16
+  is_loop: true
+`)
+	m := NewParticleManager(nil, nil)
+	if err := m.PreloadFromBytes("combo", yaml); err != nil {
+		t.Fatalf("PreloadFromBytes failed: %v", err)
+	}
+	world := donburi.NewWorld()
+	if _, err := m.SpawnLoop(world, "combo", 0, 0); err == nil || !strings.Contains(err.Error(), `"score" is not registered`) {
+		t.Fatalf("expected unregistered font error, got %v", err)
+	}
+
+	m.Fonts().Register("score", newTestFontSource(t))
+	entity, err := m.SpawnLoop(world, "combo", 0, 0)
+	if err != nil {
+		t.Fatalf("SpawnLoop failed: %v", err)
+	}
+	vector := Component.Get(world.Entry(entity)).EmitterVector
+	if vector.Type != EmitterVectorPolyline || vector.Placement != EmitterVectorFill || len(vector.Polylines) == 0 {
+		t.Fatalf("expected glyph outlines for fill placement, got %+v", vector)
+	}
+	count := 0
+	donburi.NewQuery(filter.Contains(Component)).Each(world, func(*donburi.Entry) { count++ })
+	if count != 1 {
+		t.Fatalf("expected only the successful spawn to create an entity, got %d", count)
+	}
+
+	// The manager-less helpers have no font registry.
+	cfg, err := NewConfigLoader().LoadConfigFromBytes(yaml, "combo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := NewParticlesFromConfig(world, nil, nil, cfg, 0, 0); err == nil || !strings.Contains(err.Error(), "needs a font registry") {
+		t.Fatalf("expected NewParticlesFromConfig to reject text vectors, got %v", err)
+	}
+}
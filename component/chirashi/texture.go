@@ -0,0 +1,83 @@
+package chirashi
+
+import (
+	"image"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+type textureKey struct {
+	from string
+	id   int
+}
+
+// TextureRegistry maps ImageConfig references (image_from + image_id) to
+// particle images. Registered images may be sub-images of a shared atlas.
+// It is safe for concurrent use.
+type TextureRegistry struct {
+	mutex  sync.RWMutex
+	images map[textureKey]*ebiten.Image
+}
+
+// NewTextureRegistry creates an empty texture registry.
+func NewTextureRegistry() *TextureRegistry {
+	return &TextureRegistry{images: make(map[textureKey]*ebiten.Image)}
+}
+
+// Register stores img as image_from: name with image_id 0.
+func (r *TextureRegistry) Register(name string, img *ebiten.Image) {
+	r.RegisterID(name, 0, img)
+}
+
+// RegisterID stores img as image_from: name, image_id: id. name may be empty
+// for presets that only set image_id.
+func (r *TextureRegistry) RegisterID(name string, id int, img *ebiten.Image) {
+	r.mutex.Lock()
+	r.images[textureKey{from: name, id: id}] = img
+	r.mutex.Unlock()
+}
+
+// RegisterAtlas splits atlas into cellWidth x cellHeight sub-images and
+// registers them under name with IDs 0, 1, 2, ... numbered left to right,
+// top to bottom. It returns the number of cells registered.
+func (r *TextureRegistry) RegisterAtlas(name string, atlas *ebiten.Image, cellWidth, cellHeight int) int {
+	if atlas == nil || cellWidth <= 0 || cellHeight <= 0 {
+		return 0
+	}
+	bounds := atlas.Bounds()
+	columns := bounds.Dx() / cellWidth
+	rows := bounds.Dy() / cellHeight
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for row := 0; row < rows; row++ {
+		for col := 0; col < columns; col++ {
+			x := bounds.Min.X + col*cellWidth
+			y := bounds.Min.Y + row*cellHeight
+			cell := atlas.SubImage(image.Rect(x, y, x+cellWidth, y+cellHeight)).(*ebiten.Image)
+			r.images[textureKey{from: name, id: row*columns + col}] = cell
+		}
+	}
+	return columns * rows
+}
+
+// Lookup returns the image registered for (name, id).
+func (r *TextureRegistry) Lookup(name string, id int) (*ebiten.Image, bool) {
+	r.mutex.RLock()
+	img, ok := r.images[textureKey{from: name, id: id}]
+	r.mutex.RUnlock()
+	return img, ok
+}
+
+// Resolve returns the image an ImageConfig refers to, or fallback when r is
+// nil or nothing is registered under that reference.
+func (r *TextureRegistry) Resolve(config ImageConfig, fallback *ebiten.Image) *ebiten.Image {
+	if r == nil {
+		return fallback
+	}
+	if img, ok := r.Lookup(config.ImageFrom, config.ImageID); ok && img != nil {
+		return img
+	}
+	return fallback
+}
@@ -0,0 +1,67 @@
+package chirashi
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/yohamta/donburi"
+)
+
+func TestTextureRegistryRegistersAtlasCells(t *testing.T) {
+	registry := NewTextureRegistry()
+	atlas := ebiten.NewImage(64, 32)
+	if n := registry.RegisterAtlas("ef1", atlas, 16, 16); n != 8 {
+		t.Fatalf("registered %d cells, want 8", n)
+	}
+
+	cell, ok := registry.Lookup("ef1", 5)
+	if !ok {
+		t.Fatal("expected cell 5 to be registered")
+	}
+	if b := cell.Bounds(); b.Min.X != 16 || b.Min.Y != 16 || b.Dx() != 16 || b.Dy() != 16 {
+		t.Fatalf("cell 5 bounds got %v, want (16,16)-(32,32)", b)
+	}
+	if _, ok := registry.Lookup("ef1", 8); ok {
+		t.Fatal("expected no cell past the atlas")
+	}
+}
+
+func TestTextureRegistryResolveFallsBack(t *testing.T) {
+	registry := NewTextureRegistry()
+	fallback := ebiten.NewImage(4, 4)
+	smoke := ebiten.NewImage(8, 8)
+	registry.Register("smoke", smoke)
+
+	if got := registry.Resolve(ImageConfig{ImageFrom: "smoke"}, fallback); got != smoke {
+		t.Fatal("expected registered image")
+	}
+	if got := registry.Resolve(ImageConfig{ImageFrom: "spark", ImageID: 3}, fallback); got != fallback {
+		t.Fatal("expected fallback for an unregistered reference")
+	}
+	var nilRegistry *TextureRegistry
+	if got := nilRegistry.Resolve(ImageConfig{ImageFrom: "smoke"}, fallback); got != fallback {
+		t.Fatal("expected nil registry to return the fallback")
+	}
+}
+
+func TestManagerResolvesPresetImageFromRegistry(t *testing.T) {
+	fallback := ebiten.NewImage(4, 4)
+	m := NewParticleManager(nil, fallback)
+	atlas := ebiten.NewImage(32, 16)
+	m.Textures().RegisterAtlas("ef1", atlas, 16, 16)
+
+	config := validParticleConfigForTest()
+	config.Image = ImageConfig{ImageFrom: "ef1", ImageID: 1}
+	world := donburi.NewWorld()
+	entity, err := m.createEntity(world, config, 0, 0)
+	if err != nil {
+		t.Fatalf("createEntity: %v", err)
+	}
+	data := Component.Get(world.Entry(entity))
+	if data.SourceImage == fallback {
+		t.Fatal("expected the atlas cell, got the manager fallback image")
+	}
+	if data.ImageX != 16 || data.ImageY != 0 || data.ImageWidth != 16 || data.ImageHeight != 16 {
+		t.Fatalf("cached bounds got origin (%v, %v) size (%v, %v)", data.ImageX, data.ImageY, data.ImageWidth, data.ImageHeight)
+	}
+}
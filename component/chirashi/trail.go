@@ -83,10 +83,32 @@ func buildTrailData(config *TrailConfig) TrailData {
 		trail.Params.ColorEndG = config.Color.EndG
 		trail.Params.ColorEndB = config.Color.EndB
 		trail.Params.ColorEasing = ParseEasing(config.Color.Easing)
+		trail.Params.ColorSpace = parseColorSpace(config.Color.Space)
+		trail.Params.ColorGradient = buildColorGradient(config.Color)
+		if trail.Params.ColorSpace != ColorSpaceRGB {
+			// Convert once here instead of for every trail vertex.
+			trail.Params.ColorStart = toColorSpace(trail.Params.ColorSpace, config.Color.StartR, config.Color.StartG, config.Color.StartB)
+			trail.Params.ColorEnd = toColorSpace(trail.Params.ColorSpace, config.Color.EndR, config.Color.EndG, config.Color.EndB)
+		}
 	}
 	return trail
 }
 
+// trailColor returns the trail color and gradient alpha factor at normalized
+// point age t.
+func trailColor(params *TrailParams, t float32) (float32, float32, float32, float32) {
+	colorT := ApplyEasing(t, params.ColorEasing)
+	switch {
+	case params.ColorGradient != nil:
+		return params.ColorGradient.Evaluate(colorT)
+	case params.ColorSpace == ColorSpaceRGB:
+		return lerp(params.ColorStartR, params.ColorEndR, colorT), lerp(params.ColorStartG, params.ColorEndG, colorT), lerp(params.ColorStartB, params.ColorEndB, colorT), 1
+	default:
+		r, g, b := fromColorSpace(params.ColorSpace, mixColorCoords(params.ColorSpace, params.ColorStart, params.ColorEnd, colorT))
+		return r, g, b, 1
+	}
+}
+
 func isParticleTrail(trail *TrailData) bool {
 	return trail.Params.Enabled && trail.Params.Mode == "particle"
 }
@@ -177,18 +199,18 @@ func trailHasVisiblePoints(data *SystemData) bool {
 	return len(data.Trail.Runtime.Points) >= 2
 }
 
-func drawTrail(screen *ebiten.Image, data *SystemData) {
+func drawTrail(screen *ebiten.Image, data *SystemData, view *viewTransform) {
 	trail := &data.Trail
 	if !trailHasVisiblePoints(data) {
 		return
 	}
 	trail.Runtime.DrawOptions.Blend = data.Blend
 	if isParticleTrail(trail) {
-		drawParticleTrails(screen, data)
+		drawParticleTrails(screen, data, view)
 		return
 	}
 
-	buildEmitterTrailMesh(data)
+	buildEmitterTrailMesh(data, view)
 	if len(trail.Runtime.Indices) == 0 {
 		return
 	}
@@ -196,7 +218,7 @@ func drawTrail(screen *ebiten.Image, data *SystemData) {
 	drawTrailBatch(screen, trail.Runtime.Vertices, trail.Runtime.Indices, &trail.Runtime.DrawOptions)
 }
 
-func buildEmitterTrailMesh(data *SystemData) {
+func buildEmitterTrailMesh(data *SystemData, view *viewTransform) {
 	trail := &data.Trail
 	trail.Runtime.Vertices = trail.Runtime.Vertices[:0]
 	trail.Runtime.Indices = trail.Runtime.Indices[:0]
@@ -208,7 +230,7 @@ func buildEmitterTrailMesh(data *SystemData) {
 		return
 	}
 
-	appendTrailMeshForPoints(data, trail.Runtime.Points)
+	appendTrailMeshForPoints(data, trail.Runtime.Points, currentEmitterTransform(data).scale, view)
 }
 
 func updateParticleTrails(data *SystemData) {
@@ -257,7 +279,9 @@ func updateSingleParticleTrail(data *SystemData, p *Instance, currentTime float3
 	p.TrailPoints = points
 }
 
-func appendTrailMeshForPoints(data *SystemData, points []TrailPoint) {
+// appendTrailMeshForPoints appends the ribbon for points, with trail widths
+// multiplied by widthScale, mapped to the screen through view.
+func appendTrailMeshForPoints(data *SystemData, points []TrailPoint, widthScale float32, view *viewTransform) {
 	trail := &data.Trail
 	if len(points) < 2 {
 		return
@@ -273,19 +297,23 @@ func appendTrailMeshForPoints(data *SystemData, points []TrailPoint) {
 		ageNorm := clamp01((data.CurrentTime - p.CapturedAt) / trail.Params.MaxPointAge)
 		width := lerp(trail.Params.WidthStart, trail.Params.WidthEnd, ApplyEasing(ageNorm, trail.Params.WidthEasing))
 		alpha := lerp(trail.Params.AlphaStart, trail.Params.AlphaEnd, ApplyEasing(ageNorm, trail.Params.AlphaEasing))
-		colorT := ApplyEasing(ageNorm, trail.Params.ColorEasing)
-		r := lerp(trail.Params.ColorStartR, trail.Params.ColorEndR, colorT)
-		g := lerp(trail.Params.ColorStartG, trail.Params.ColorEndG, colorT)
-		b := lerp(trail.Params.ColorStartB, trail.Params.ColorEndB, colorT)
+		r, g, b, a := trailColor(&trail.Params, ageNorm)
+		alpha *= a
 
-		halfWidth := width * 0.5
+		halfWidth := width * widthScale * 0.5
 		ox := nx * halfWidth
 		oy := ny * halfWidth
 		v := float32(i) / float32(lastIndex)
 
+		x0, y0 := p.X-ox, p.Y-oy
+		x1, y1 := p.X+ox, p.Y+oy
+		if view.enabled {
+			x0, y0 = view.apply(x0, y0)
+			x1, y1 = view.apply(x1, y1)
+		}
 		trail.Runtime.Vertices = append(trail.Runtime.Vertices,
-			ebiten.Vertex{DstX: p.X - ox, DstY: p.Y - oy, SrcX: 0, SrcY: v, ColorR: r, ColorG: g, ColorB: b, ColorA: alpha},
-			ebiten.Vertex{DstX: p.X + ox, DstY: p.Y + oy, SrcX: 1, SrcY: v, ColorR: r, ColorG: g, ColorB: b, ColorA: alpha},
+			ebiten.Vertex{DstX: x0, DstY: y0, SrcX: 0, SrcY: v, ColorR: r, ColorG: g, ColorB: b, ColorA: alpha},
+			ebiten.Vertex{DstX: x1, DstY: y1, SrcX: 1, SrcY: v, ColorR: r, ColorG: g, ColorB: b, ColorA: alpha},
 		)
 	}
 
@@ -351,20 +379,20 @@ func pruneTrailGhosts(trail *TrailRuntime, maxPointAge, currentTime float32) {
 			recycleTrailGhostPoints(trail, points)
 			continue
 		}
-		trail.Ghosts[writeIdx].Points = points
+		trail.Ghosts[writeIdx] = TrailGhost{Points: points, WidthScale: trail.Ghosts[i].WidthScale}
 		writeIdx++
 	}
 	clearTrailGhostTail(oldGhosts, writeIdx, oldLen)
 	trail.Ghosts = trail.Ghosts[:writeIdx]
 }
 
-func detachParticleTrail(trail *TrailData, points []TrailPoint) {
+func detachParticleTrail(trail *TrailData, points []TrailPoint, widthScale float32) {
 	if len(points) < 2 || trail.Params.MaxPointAge <= 0 {
 		return
 	}
 	copied := takeTrailGhostPoints(&trail.Runtime, len(points))
 	copy(copied, points)
-	trail.Runtime.Ghosts = append(trail.Runtime.Ghosts, TrailGhost{Points: copied})
+	trail.Runtime.Ghosts = append(trail.Runtime.Ghosts, TrailGhost{Points: copied, WidthScale: widthScale})
 }
 
 func drawTrailBatch(screen *ebiten.Image, vertices []ebiten.Vertex, indices []uint16, op *ebiten.DrawTrianglesOptions) {
@@ -374,15 +402,16 @@ func drawTrailBatch(screen *ebiten.Image, vertices []ebiten.Vertex, indices []ui
 	screen.DrawTriangles(vertices, indices, getTrailWhiteImage(), op)
 }
 
-func drawParticleTrails(screen *ebiten.Image, data *SystemData) {
+func drawParticleTrails(screen *ebiten.Image, data *SystemData, view *viewTransform) {
 	trail := &data.Trail
 	builder := newParticleTrailBatchBuilder(screen, &trail.Runtime)
 
 	for idx := 0; idx < data.ActiveCount; idx++ {
-		builder.Append(data, data.ParticlePool[idx].TrailPoints)
+		p := &data.ParticlePool[idx]
+		builder.Append(data, p.TrailPoints, particleTransformScale(p), view)
 	}
 	for _, ghost := range trail.Runtime.Ghosts {
-		builder.Append(data, ghost.Points)
+		builder.Append(data, ghost.Points, ghost.WidthScale, view)
 	}
 	builder.Flush()
 }
@@ -397,7 +426,7 @@ func newParticleTrailBatchBuilder(screen *ebiten.Image, runtime *TrailRuntime) p
 	}
 }
 
-func (b *particleTrailBatchBuilder) Append(data *SystemData, points []TrailPoint) {
+func (b *particleTrailBatchBuilder) Append(data *SystemData, points []TrailPoint, widthScale float32, view *viewTransform) {
 	if len(points) < 2 {
 		return
 	}
@@ -405,7 +434,7 @@ func (b *particleTrailBatchBuilder) Append(data *SystemData, points []TrailPoint
 	if len(b.trail.Vertices) > 0 && len(b.trail.Vertices)+neededVertices > maxTrailBatchVertices {
 		b.Flush()
 	}
-	appendTrailMeshForPoints(data, points)
+	appendTrailMeshForPoints(data, points, widthScale, view)
 }
 
 func (b *particleTrailBatchBuilder) Flush() {
@@ -0,0 +1,77 @@
+package chirashi
+
+import "github.com/yohamta/donburi"
+
+// emitterTransform is the emitter rotation and uniform scale applied to
+// particles as they spawn. apply is the identity for the default transform.
+type emitterTransform struct {
+	enabled         bool
+	rotation, scale float32
+	a, b            float32 // scale*cos(rotation), scale*sin(rotation)
+}
+
+func currentEmitterTransform(data *SystemData) emitterTransform {
+	scale := data.EmitterScale
+	if scale <= 0 {
+		scale = 1
+	}
+	rotation := data.EmitterRotation + data.attachmentRotation
+	if rotation == 0 && scale == 1 {
+		return emitterTransform{scale: 1, a: 1}
+	}
+	sin, cos := fastSincos(rotation)
+	return emitterTransform{
+		enabled:  true,
+		rotation: rotation,
+		scale:    scale,
+		a:        scale * cos,
+		b:        scale * sin,
+	}
+}
+
+// apply rotates and scales the offset or velocity (x, y).
+func (t emitterTransform) apply(x, y float32) (float32, float32) {
+	return x*t.a - y*t.b, x*t.b + y*t.a
+}
+
+// particleTransformScale returns the emitter scale p spawned with.
+func particleTransformScale(p *Instance) float32 {
+	if !p.HasTransform {
+		return 1
+	}
+	return p.TransformScale
+}
+
+// SetEmitterRotation rotates everything a particle entity spawns from now on
+// around its emitter origin by rotation radians, e.g. to aim a muzzle flash.
+// Live particles keep the transform they spawned with. The rotation
+// overrides spawn.rotation, also across ApplyConfigLive.
+func SetEmitterRotation(world donburi.World, entity donburi.Entity, rotation float32) {
+	if !world.Valid(entity) {
+		return
+	}
+	entry := world.Entry(entity)
+	if !entry.HasComponent(Component) {
+		return
+	}
+	data := Component.Get(entry)
+	data.EmitterRotation = rotation
+	data.rotationOverridden = true
+}
+
+// SetEmitterScale scales everything a particle entity spawns from now on
+// around its emitter origin, e.g. 0.5 for a smaller enemy. Values <= 0 reset
+// the scale to 1. Live particles keep the transform they spawned with. The
+// scale overrides spawn.scale, also across ApplyConfigLive.
+func SetEmitterScale(world donburi.World, entity donburi.Entity, scale float32) {
+	if !world.Valid(entity) {
+		return
+	}
+	entry := world.Entry(entity)
+	if !entry.HasComponent(Component) {
+		return
+	}
+	data := Component.Get(entry)
+	data.EmitterScale = scale
+	data.scaleOverridden = true
+}
@@ -0,0 +1,144 @@
+package chirashi
+
+import (
+	"math"
+	"testing"
+
+	"github.com/yohamta/donburi"
+)
+
+func transformSystemForTest(pos PositionParams) *SystemData {
+	return &SystemData{
+		ParticlePool:      make([]Instance, 1),
+		SpawnInterval:     1,
+		ParticlesPerSpawn: 1,
+		MaxParticles:      1,
+		EmissionScale:     1,
+		IsLoop:            true,
+		EmitterX:          100,
+		EmitterY:          100,
+		EmitterRotation:   math.Pi / 2,
+		EmitterScale:      2,
+		AnimParams: AnimationParams{
+			Duration: DurationParams{Base: 1},
+			Position: pos,
+		},
+	}
+}
+
+func nearPoint(x, y, wantX, wantY float32) bool {
+	return math.Abs(float64(x-wantX)) < 1e-2 && math.Abs(float64(y-wantY)) < 1e-2
+}
+
+func TestSpawnAppliesEmitterTransform(t *testing.T) {
+	sys := &System{}
+
+	cartesian := transformSystemForTest(PositionParams{StartXMin: 5, StartXMax: 5, EndXMin: 10, EndXMax: 10})
+	sys.spawn(cartesian, defaultDeltaTime)
+	p := &cartesian.ParticlePool[0]
+	if !nearPoint(p.StartX, p.StartY, 100, 110) || !nearPoint(p.EndX, p.EndY, 100, 120) {
+		t.Fatalf("cartesian offsets got start (%v, %v) end (%v, %v), want (100, 110) and (100, 120)", p.StartX, p.StartY, p.EndX, p.EndY)
+	}
+	if !p.HasTransform || p.TransformScale != 2 || !nearFloat(p.TransformRotation, math.Pi/2) {
+		t.Fatalf("expected the particle to keep the spawn transform, got %+v", p)
+	}
+
+	line := transformSystemForTest(PositionParams{})
+	line.EmitterShape = EmitterShapeParams{Type: EmitterShapeLine, Length: 20}
+	line.ParticlePool = make([]Instance, 8)
+	line.ParticlesPerSpawn, line.MaxParticles = 8, 8
+	sys.spawn(line, defaultDeltaTime)
+	for i := 0; i < line.ActiveCount; i++ {
+		if p := &line.ParticlePool[i]; math.Abs(float64(p.StartX-100)) > 1e-2 || p.StartY < 80-1e-2 || p.StartY > 120+1e-2 {
+			t.Fatalf("line shape offset got (%v, %v), want x=100 and y within [80, 120]", p.StartX, p.StartY)
+		}
+	}
+
+	polar := transformSystemForTest(PositionParams{UsePolar: true, DistMin: 10, DistMax: 10})
+	sys.spawn(polar, defaultDeltaTime)
+	if p := &polar.ParticlePool[0]; !nearPoint(p.EndX, p.EndY, 100, 120) {
+		t.Fatalf("polar burst got end (%v, %v), want the rotated and scaled (100, 120)", p.EndX, p.EndY)
+	}
+
+	physics := transformSystemForTest(PositionParams{UsePhysics: true, SpeedMin: 10, SpeedMax: 10, VelXMin: 3, VelXMax: 3})
+	sys.spawn(physics, defaultDeltaTime)
+	if p := &physics.ParticlePool[0]; !nearPoint(p.VelX, p.VelY, 0, 26) {
+		t.Fatalf("launch velocity got (%v, %v), want (0, 26)", p.VelX, p.VelY)
+	}
+
+	// Changing the transform only affects particles spawned afterwards.
+	polar.EmitterScale = 0
+	polar.EmitterRotation = 0
+	polar.ParticlePool = append(polar.ParticlePool, Instance{})
+	polar.MaxParticles = 2
+	sys.spawn(polar, defaultDeltaTime)
+	if p := &polar.ParticlePool[0]; !nearPoint(p.EndX, p.EndY, 100, 120) {
+		t.Fatalf("live particle moved after the transform changed: (%v, %v)", p.EndX, p.EndY)
+	}
+	if p := &polar.ParticlePool[1]; p.HasTransform || !nearPoint(p.EndX, p.EndY, 110, 100) {
+		t.Fatalf("expected scale 0 to reset to the identity transform, got end (%v, %v)", p.EndX, p.EndY)
+	}
+}
+
+func TestEmitterTransformScalesTrailWidth(t *testing.T) {
+	data := &SystemData{
+		CurrentTime:  1,
+		EmitterScale: 3,
+		Trail: TrailData{
+			Params: TrailParams{Enabled: true, MaxPointAge: 10, WidthStart: 4, WidthEnd: 4, AlphaStart: 1, AlphaEnd: 1},
+			Runtime: TrailRuntime{
+				Points: []TrailPoint{{X: 0, CapturedAt: 1}, {X: 10, CapturedAt: 1}},
+			},
+		},
+	}
+	buildEmitterTrailMesh(data, &viewTransform{})
+	v := data.Trail.Runtime.Vertices
+	if width := float32(math.Abs(float64(v[1].DstY - v[0].DstY))); !nearFloat(width, 12) {
+		t.Fatalf("emitter trail width got %v, want 12", width)
+	}
+
+	detachParticleTrail(&data.Trail, data.Trail.Runtime.Points, 0.5)
+	if ghost := data.Trail.Runtime.Ghosts[0]; ghost.WidthScale != 0.5 {
+		t.Fatalf("expected the ghost to keep its particle's scale, got %v", ghost.WidthScale)
+	}
+}
+
+func TestSetEmitterTransform(t *testing.T) {
+	world := donburi.NewWorld()
+	entity := world.Create(Component)
+	SetEmitterRotation(world, entity, 1.5)
+	SetEmitterScale(world, entity, 0.5)
+	data := Component.Get(world.Entry(entity))
+	if data.EmitterRotation != 1.5 || data.EmitterScale != 0.5 {
+		t.Fatalf("got rotation %v scale %v", data.EmitterRotation, data.EmitterScale)
+	}
+}
+
+func TestApplyConfigLiveKeepsEmitterTransformOverrides(t *testing.T) {
+	world := donburi.NewWorld()
+	cfg := validParticleConfigForTest()
+	entity, err := createParticleEntityFromConfig(world, nil, nil, nil, nil, cfg, 0, 0)
+	if err != nil {
+		t.Fatalf("createParticleEntityFromConfig failed: %v", err)
+	}
+	data := Component.Get(world.Entry(entity))
+
+	edited := copyConfig(cfg)
+	edited.Spawn.Rotation, edited.Spawn.Scale = 0.25, 2
+	ApplyConfigLive(world, entity, edited, 0, 0)
+	if data.EmitterRotation != 0.25 || data.EmitterScale != 2 {
+		t.Fatalf("expected the live edit to set the preset transform, got rotation %v scale %v", data.EmitterRotation, data.EmitterScale)
+	}
+
+	SetEmitterRotation(world, entity, 1.5)
+	ApplyConfigLive(world, entity, edited, 0, 0)
+	if data.EmitterRotation != 1.5 || data.EmitterScale != 2 {
+		t.Fatalf("expected the live edit to keep SetEmitterRotation only, got rotation %v scale %v", data.EmitterRotation, data.EmitterScale)
+	}
+	SetEmitterScale(world, entity, 0.5)
+	edited.Spawn.Scale = 3
+	ApplyConfigLive(world, entity, edited, 0, 0)
+	if data.EmitterScale != 0.5 {
+		t.Fatalf("expected the live edit to keep SetEmitterScale, got %v", data.EmitterScale)
+	}
+}
@@ -0,0 +1,265 @@
+package chirashi
+
+import (
+	"math"
+	"sort"
+)
+
+// EmitterVectorTriangleParams is one triangle of a filled polyline interior.
+type EmitterVectorTriangleParams struct {
+	A, B, C EmitterVectorPointParams
+}
+
+// triangulatePolygon splits a simple polygon with optional holes into
+// triangles by ear clipping. Holes are joined to the outline with bridge
+// edges first, so the result covers the outline minus the holes.
+func triangulatePolygon(outer []EmitterVectorPointParams, holes [][]EmitterVectorPointParams) []EmitterVectorTriangleParams {
+	ring := dedupeRing(outer)
+	if len(ring) < 3 {
+		return nil
+	}
+	if polygonSignedArea(ring) < 0 {
+		reverseRing(ring)
+	}
+
+	rings := make([][]EmitterVectorPointParams, 0, len(holes))
+	for _, hole := range holes {
+		h := dedupeRing(hole)
+		if len(h) < 3 {
+			continue
+		}
+		if polygonSignedArea(h) > 0 {
+			reverseRing(h)
+		}
+		rings = append(rings, h)
+	}
+	// Bridge holes right to left so each bridge sees the holes already merged.
+	sort.SliceStable(rings, func(i, j int) bool {
+		return rings[i][rightmostVertex(rings[i])].X > rings[j][rightmostVertex(rings[j])].X
+	})
+	for _, hole := range rings {
+		ring = bridgeHole(ring, hole)
+	}
+	return earClip(ring)
+}
+
+// bridgeHole splices hole into outer through a bridge from the hole's
+// rightmost vertex to a visible outline vertex.
+func bridgeHole(outer, hole []EmitterVectorPointParams) []EmitterVectorPointParams {
+	mi := rightmostVertex(hole)
+	m := hole[mi]
+
+	// Cast a ray toward +X and find the nearest outline edge it hits.
+	bestX := float32(math.Inf(1))
+	bridge := -1
+	for i := range outer {
+		a := outer[i]
+		b := outer[(i+1)%len(outer)]
+		if (a.Y > m.Y) == (b.Y > m.Y) {
+			continue
+		}
+		x := a.X + (m.Y-a.Y)*(b.X-a.X)/(b.Y-a.Y)
+		if x < m.X || x >= bestX {
+			continue
+		}
+		bestX = x
+		bridge = i
+		if b.X > a.X {
+			bridge = (i + 1) % len(outer)
+		}
+	}
+	if bridge < 0 {
+		return outer
+	}
+
+	// A reflex vertex inside the triangle (m, hit, candidate) would block the
+	// bridge; the one closest in angle to the ray is visible instead.
+	// Ties go to the nearer vertex so the bridge does not run along an edge.
+	hit := EmitterVectorPointParams{X: bestX, Y: m.Y}
+	p := outer[bridge]
+	bestTan, bestDist := bridgeSlope(m, p)
+	for i, v := range outer {
+		if i == bridge || v == p || v.X <= m.X || !pointInTriangle(v, m, hit, p) {
+			continue
+		}
+		tan, dist := bridgeSlope(m, v)
+		if tan < bestTan || (tan == bestTan && dist < bestDist) {
+			bestTan, bestDist = tan, dist
+			bridge = i
+		}
+	}
+
+	// Earlier bridges duplicate vertices; use the copy whose corner faces m.
+	for i, v := range outer {
+		if v == outer[bridge] && locallyInside(outer, i, m) {
+			bridge = i
+			break
+		}
+	}
+
+	merged := make([]EmitterVectorPointParams, 0, len(outer)+len(hole)+2)
+	merged = append(merged, outer[:bridge+1]...)
+	for k := 0; k <= len(hole); k++ {
+		merged = append(merged, hole[(mi+k)%len(hole)])
+	}
+	merged = append(merged, outer[bridge])
+	merged = append(merged, outer[bridge+1:]...)
+	return merged
+}
+
+// locallyInside reports whether p lies inside the polygon corner at ring[i].
+func locallyInside(ring []EmitterVectorPointParams, i int, p EmitterVectorPointParams) bool {
+	prev := ring[(i+len(ring)-1)%len(ring)]
+	a := ring[i]
+	next := ring[(i+1)%len(ring)]
+	if triangleCross(prev, a, next) >= 0 {
+		return triangleCross(prev, a, p) >= 0 && triangleCross(a, next, p) >= 0
+	}
+	return triangleCross(prev, a, p) >= 0 || triangleCross(a, next, p) >= 0
+}
+
+// bridgeSlope returns how far v is from the +X ray through m, as the slope
+// and squared distance.
+func bridgeSlope(m, v EmitterVectorPointParams) (float32, float32) {
+	dx := v.X - m.X
+	dy := v.Y - m.Y
+	if dx <= 0 {
+		return float32(math.Inf(1)), dx*dx + dy*dy
+	}
+	return float32(math.Abs(float64(dy))) / dx, dx*dx + dy*dy
+}
+
+// earClip triangulates a counter-clockwise (positive area) ring that may
+// contain bridge edges.
+func earClip(ring []EmitterVectorPointParams) []EmitterVectorTriangleParams {
+	idx := make([]int, len(ring))
+	for i := range idx {
+		idx[i] = i
+	}
+	triangles := make([]EmitterVectorTriangleParams, 0, len(ring)-2)
+	i := 0
+	stalled := 0
+	for len(idx) > 3 {
+		n := len(idx)
+		a := ring[idx[(i+n-1)%n]]
+		b := ring[idx[i%n]]
+		c := ring[idx[(i+1)%n]]
+		cross := triangleCross(a, b, c)
+		switch {
+		case cross > 0 && isEar(ring, idx, a, b, c):
+			triangles = append(triangles, EmitterVectorTriangleParams{A: a, B: b, C: c})
+			idx = append(idx[:i%n], idx[i%n+1:]...)
+			stalled = 0
+		case cross == 0 && stalled >= n:
+			// Only degenerate vertices are left in the way; drop one.
+			idx = append(idx[:i%n], idx[i%n+1:]...)
+			stalled = 0
+		default:
+			i++
+			stalled++
+			if stalled > 2*n {
+				return triangles
+			}
+		}
+		if len(idx) > 0 {
+			i %= len(idx)
+		}
+	}
+	if len(idx) == 3 {
+		a, b, c := ring[idx[0]], ring[idx[1]], ring[idx[2]]
+		if triangleCross(a, b, c) > 0 {
+			triangles = append(triangles, EmitterVectorTriangleParams{A: a, B: b, C: c})
+		}
+	}
+	return triangles
+}
+
+// isEar reports whether no reflex vertex lies inside triangle abc. Convex
+// vertices cannot be inside without a reflex one also being inside.
+func isEar(ring []EmitterVectorPointParams, idx []int, a, b, c EmitterVectorPointParams) bool {
+	n := len(idx)
+	for k, j := range idx {
+		p := ring[j]
+		if p == a || p == b || p == c {
+			continue
+		}
+		if triangleCross(ring[idx[(k+n-1)%n]], p, ring[idx[(k+1)%n]]) > 0 {
+			continue
+		}
+		if pointInTriangle(p, a, b, c) {
+			return false
+		}
+	}
+	return true
+}
+
+func pointInTriangle(p, a, b, c EmitterVectorPointParams) bool {
+	d1 := triangleCross(a, b, p)
+	d2 := triangleCross(b, c, p)
+	d3 := triangleCross(c, a, p)
+	hasNeg := d1 < 0 || d2 < 0 || d3 < 0
+	hasPos := d1 > 0 || d2 > 0 || d3 > 0
+	return !(hasNeg && hasPos)
+}
+
+func triangleCross(a, b, c EmitterVectorPointParams) float32 {
+	return (b.X-a.X)*(c.Y-a.Y) - (b.Y-a.Y)*(c.X-a.X)
+}
+
+func triangleArea(t EmitterVectorTriangleParams) float32 {
+	return float32(math.Abs(float64(triangleCross(t.A, t.B, t.C)))) / 2
+}
+
+func polygonSignedArea(ring []EmitterVectorPointParams) float32 {
+	area := float32(0)
+	for i := range ring {
+		a := ring[i]
+		b := ring[(i+1)%len(ring)]
+		area += a.X*b.Y - b.X*a.Y
+	}
+	return area / 2
+}
+
+// pointInPolygon reports whether p lies inside ring (even-odd rule).
+func pointInPolygon(p EmitterVectorPointParams, ring []EmitterVectorPointParams) bool {
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		a, b := ring[i], ring[j]
+		if (a.Y > p.Y) != (b.Y > p.Y) && p.X < (b.X-a.X)*(p.Y-a.Y)/(b.Y-a.Y)+a.X {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+func rightmostVertex(ring []EmitterVectorPointParams) int {
+	best := 0
+	for i, p := range ring {
+		if p.X > ring[best].X {
+			best = i
+		}
+	}
+	return best
+}
+
+func reverseRing(ring []EmitterVectorPointParams) {
+	for i, j := 0, len(ring)-1; i < j; i, j = i+1, j-1 {
+		ring[i], ring[j] = ring[j], ring[i]
+	}
+}
+
+// dedupeRing copies ring without consecutive duplicates or a repeated
+// closing point.
+func dedupeRing(ring []EmitterVectorPointParams) []EmitterVectorPointParams {
+	out := make([]EmitterVectorPointParams, 0, len(ring))
+	for _, p := range ring {
+		if len(out) > 0 && out[len(out)-1] == p {
+			continue
+		}
+		out = append(out, p)
+	}
+	for len(out) > 1 && out[0] == out[len(out)-1] {
+		out = out[:len(out)-1]
+	}
+	return out
+}
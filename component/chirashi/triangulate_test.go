@@ -0,0 +1,84 @@
+package chirashi
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+)
+
+func squareRing(x0, y0, x1, y1 float32) []EmitterVectorPointParams {
+	return []EmitterVectorPointParams{{X: x0, Y: y0}, {X: x1, Y: y0}, {X: x1, Y: y1}, {X: x0, Y: y1}}
+}
+
+func trianglesArea(triangles []EmitterVectorTriangleParams) float32 {
+	area := float32(0)
+	for _, t := range triangles {
+		area += triangleArea(t)
+	}
+	return area
+}
+
+func TestTriangulatePolygonCoversConcaveOutline(t *testing.T) {
+	// L shape, listed clockwise to check that orientation does not matter.
+	outline := []EmitterVectorPointParams{
+		{X: 0, Y: 0}, {X: 0, Y: 100}, {X: 100, Y: 100}, {X: 100, Y: 60}, {X: 40, Y: 60}, {X: 40, Y: 0},
+	}
+	triangles := triangulatePolygon(outline, nil)
+	if len(triangles) != len(outline)-2 {
+		t.Fatalf("triangle count got %d, want %d", len(triangles), len(outline)-2)
+	}
+	if got, want := trianglesArea(triangles), float32(40*100+60*40); math.Abs(float64(got-want)) > 1e-2 {
+		t.Fatalf("covered area got %v, want %v", got, want)
+	}
+}
+
+func TestTriangulatePolygonCutsHoles(t *testing.T) {
+	outer := squareRing(-50, -50, 50, 50)
+	holes := [][]EmitterVectorPointParams{
+		squareRing(-40, -20, -10, 20),
+		squareRing(10, -20, 40, 20),
+	}
+	triangles := triangulatePolygon(outer, holes)
+	if got, want := trianglesArea(triangles), float32(100*100-2*30*40); math.Abs(float64(got-want)) > 1e-2 {
+		t.Fatalf("covered area got %v, want %v", got, want)
+	}
+}
+
+func TestSamplePolylineFillPositionStaysInsideShape(t *testing.T) {
+	vector := buildEmitterVectorParams(&EmitterVectorConfig{
+		Type:      "polyline",
+		Placement: "fill",
+		Polyline: &EmitterVectorPolylineConfig{
+			Closed: true,
+			Points: []EmitterVectorPoint{{X: -50, Y: -50}, {X: 50, Y: -50}, {X: 50, Y: 50}, {X: -50, Y: 50}},
+			Holes:  [][]EmitterVectorPoint{{{X: -25, Y: -25}, {X: 25, Y: -25}, {X: 25, Y: 25}, {X: -25, Y: 25}}},
+		},
+	})
+	rng := rand.New(rand.NewPCG(1, 2))
+	const total = 400
+	quadrants := [4]int{}
+	for i := 0; i < total; i++ {
+		x, y := sampleEmitterVectorPosition(rng, 100, 100, vector, i, total)
+		x -= 100
+		y -= 100
+		if x < -50 || x > 50 || y < -50 || y > 50 {
+			t.Fatalf("sample %d at (%v, %v) is outside the outline", i, x, y)
+		}
+		if x > -25 && x < 25 && y > -25 && y < 25 {
+			t.Fatalf("sample %d at (%v, %v) is inside the hole", i, x, y)
+		}
+		q := 0
+		if x > 0 {
+			q++
+		}
+		if y > 0 {
+			q += 2
+		}
+		quadrants[q]++
+	}
+	for q, n := range quadrants {
+		if n < total/8 {
+			t.Fatalf("quadrant %d got %d of %d samples; fill is not area-uniform", q, n, total)
+		}
+	}
+}
@@ -1,17 +1,25 @@
 package editor
 
 import (
+	"bytes"
+	"encoding/xml"
 	"fmt"
 	"image"
 	"image/color"
+	"image/png"
+	"io"
+	"io/fs"
 	"log"
 	"math"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/ebitengine/debugui"
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/examples/resources/fonts"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
 	"github.com/hajimehoshi/ebiten/v2/vector"
 	"github.com/yohamta/donburi"
 	"github.com/yohamta/donburi/ecs"
@@ -56,6 +64,12 @@ type ParticleEditorScene struct {
 	dragVectorIndex          int
 	showEmitterVectorPreview bool
 	easingPicker             *easingPickerState
+	svgPathInput             string
+	svgImportScale           float32
+	svgImportCurveSteps      int
+	fontSource               *text.GoTextFaceSource
+	textPreview              *chirashi.EmitterVectorConfig
+	spriteImage              image.Image
 }
 
 type easingPickerState struct {
@@ -97,6 +111,11 @@ func NewParticleEditorScene() (*ParticleEditorScene, error) {
 		return nil, fmt.Errorf("load bloom effect shaders: %w", err)
 	}
 
+	fontSource, err := text.NewGoTextFaceSource(bytes.NewReader(fonts.MPlus1pRegular_ttf))
+	if err != nil {
+		return nil, fmt.Errorf("load text vector font: %w", err)
+	}
+
 	loader := chirashi.NewConfigLoader()
 
 	config, err := loader.LoadConfigFromBytes(assets.SampleParticleConfig, "sample.yaml")
@@ -104,11 +123,6 @@ func NewParticleEditorScene() (*ParticleEditorScene, error) {
 		return nil, fmt.Errorf("load config: %w", err)
 	}
 
-	// nil shader = plain DrawTriangles path with Ebiten's internal batching.
-	if err := chirashi.NewParticlesFromConfig(world, nil, img, config, editorCenterX, editorCenterY); err != nil {
-		return nil, fmt.Errorf("create particles: %w", err)
-	}
-
 	scene := &ParticleEditorScene{
 		world:                    world,
 		container:                container,
@@ -123,6 +137,13 @@ func NewParticleEditorScene() (*ParticleEditorScene, error) {
 		attractorY:               editorCenterY,
 		dragVectorIndex:          -1,
 		showEmitterVectorPreview: true,
+		svgImportScale:           1,
+		svgImportCurveSteps:      12,
+		fontSource:               fontSource,
+		spriteImage:              newEditorSprite(),
+	}
+	if err := scene.createParticles(); err != nil {
+		return nil, fmt.Errorf("create particles: %w", err)
 	}
 	scene.refreshFileList()
 	return scene, nil
@@ -148,6 +169,7 @@ func (s *ParticleEditorScene) Update() error {
 		s.applyAttractorTarget()
 	}
 	s.handleEmitterVectorEditing()
+	s.importDroppedFiles()
 	if s.dragEmitter && ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
 		s.updateEmitterFromCursor()
 	}
@@ -190,9 +212,11 @@ func (s *ParticleEditorScene) drawRightSidebarWindow(ctx *debugui.Context) {
 		})
 		ctx.Header("Scale", false, func() {
 			s.drawPropertyWindow(ctx, "Scale", &s.config.Animation.Scale, 0.0, 5.0, 0.1, "scale")
+			s.drawScaleAxesControls(ctx)
 		})
 		ctx.Header("Rotation", false, func() {
 			s.drawPropertyWindow(ctx, "Rotation", &s.config.Animation.Rotation, -6.28, 6.28, 0.1, "rotation")
+			s.drawAlignControls(ctx)
 		})
 		if s.config.Animation.Color != nil {
 			ctx.Header("Color", false, func() {
@@ -262,7 +286,7 @@ func (s *ParticleEditorScene) recreateParticles() {
 
 	// Create new particles
 	log.Printf("Creating particles with config: %s, PosType=%s", s.config.Name, s.config.Animation.Position.Type)
-	if err := chirashi.NewParticlesFromConfig(s.world, nil, s.img, s.config, editorCenterX, editorCenterY); err != nil {
+	if err := s.createParticles(); err != nil {
 		log.Println("Failed to recreate particles:", err)
 		return
 	}
@@ -279,6 +303,43 @@ func (s *ParticleEditorScene) recreateParticles() {
 	}
 }
 
+// createParticles spawns s.config at the canvas center. Text vectors are laid
+// out with the editor font whatever font name the preset uses, and sprites
+// use the editor sprite, since the editor has no font or texture registry.
+func (s *ParticleEditorScene) createParticles() error {
+	config := s.config
+	s.textPreview = nil
+	if v := config.Emitter.Vector; v != nil && v.Type == "text" && v.Text != nil {
+		face := &text.GoTextFace{Source: s.fontSource, Size: float64(v.Text.Size)}
+		textVector, err := chirashi.NewTextVector(v.Text.Value, face, v.Placement, v.Text.Align)
+		if err != nil {
+			return err
+		}
+		laidOut := *config
+		laidOut.Emitter.Vector = textVector
+		config = &laidOut
+		s.textPreview = textVector
+	}
+	if sp := config.Emitter.Sprite; sp != nil && (sp.ImageFrom != "" || sp.ImageID != 0) {
+		// The editor previews sprites with its own image instead.
+		sprite := *sp
+		sprite.ImageFrom, sprite.ImageID = "", 0
+		withoutRef := *config
+		withoutRef.Emitter.Sprite = &sprite
+		config = &withoutRef
+	}
+	// nil shader = plain DrawTriangles path with Ebiten's internal batching.
+	if err := chirashi.NewParticlesFromConfig(s.world, nil, s.img, config, editorCenterX, editorCenterY); err != nil {
+		return err
+	}
+	if config.Emitter.Sprite != nil {
+		s.forEachParticleSystem(func(entry *donburi.Entry) {
+			chirashi.SetEmitterSprite(s.world, entry.Entity(), s.spriteImage)
+		})
+	}
+	return nil
+}
+
 func (s *ParticleEditorScene) applyConfigLive() {
 	s.forEachParticleSystem(func(entry *donburi.Entry) {
 		chirashi.ApplyConfigLive(s.world, entry.Entity(), s.config, editorCenterX, editorCenterY)
@@ -342,6 +403,13 @@ func (s *ParticleEditorScene) drawSpawnControls(ctx *debugui.Context) {
 		s.applyChange(applyModeLive)
 	})
 
+	ctx.SetGridLayout([]int{-1}, nil)
+	s.sliderControl32(ctx, "Rate /s (0=interval)", &s.config.Spawn.Rate, 0, 2000, 10)
+	s.sliderControl32(ctx, "Rate /unit moved", &s.config.Spawn.RateOverDistance, 0, 5, 0.05)
+	s.sliderControl32(ctx, "Emitter Rotation", &s.config.Spawn.Rotation, -3.14, 3.14, 0.05)
+	s.sliderControl32(ctx, "Emitter Scale (0=1)", &s.config.Spawn.Scale, 0, 4, 0.05)
+	ctx.SetGridLayout([]int{140, 60, 60}, nil)
+
 	ctx.Text(fmt.Sprintf("Max Particles: %d", s.config.Spawn.MaxParticles))
 	ctx.Button("M+").On(func() { s.config.Spawn.MaxParticles += 1000; s.applyChange(applyModeRecreate) })
 	ctx.Button("M-").On(func() {
@@ -407,6 +475,294 @@ func (s *ParticleEditorScene) drawEmitterControls(ctx *debugui.Context) {
 	s.drawEmitterShapeControls(ctx)
 	ctx.Text("----------------")
 	s.drawEmitterVectorControls(ctx)
+	s.drawSVGImportControls(ctx)
+	ctx.Text("----------------")
+	s.drawEmitterMotionControls(ctx)
+	ctx.Text("----------------")
+	s.drawEmitterSpriteControls(ctx)
+}
+
+func (s *ParticleEditorScene) drawSVGImportControls(ctx *debugui.Context) {
+	ctx.Text("SVG Path Import (paste path data or drop an .svg file)")
+	ctx.TextField(&s.svgPathInput).On(func() {
+		s.importSVGPath(s.svgPathInput)
+	})
+	s.sliderControl32WithMode(ctx, "SVG Scale", &s.svgImportScale, 0.05, 10, 0.05, applyModeLive)
+	s.sliderIntControl(ctx, "SVG Curve Steps", &s.svgImportCurveSteps, 1, 32, 1, applyModeLive)
+	ctx.Button("Import SVG Path").On(func() {
+		s.importSVGPath(s.svgPathInput)
+	})
+}
+
+// importSVGPath replaces the emitter vector with polylines parsed from SVG
+// path data, centered on the emitter. Fill placement is kept when every
+// subpath is closed.
+func (s *ParticleEditorScene) importSVGPath(d string) {
+	placement := "surface"
+	if v := s.config.Emitter.Vector; v != nil && v.Placement == "fill" {
+		placement = "fill"
+	}
+	vectorConfig, err := chirashi.NewSVGPathVector(d, placement, chirashi.SVGPathOptions{
+		Scale:      s.svgImportScale,
+		Center:     true,
+		CurveSteps: s.svgImportCurveSteps,
+	})
+	if err != nil {
+		log.Println("SVG import error:", err)
+		return
+	}
+	if placement == "fill" {
+		for _, polyline := range append([]chirashi.EmitterVectorPolylineConfig{*vectorConfig.Polyline}, vectorConfig.Polylines...) {
+			if !polyline.Closed {
+				vectorConfig.Placement = "surface"
+				break
+			}
+		}
+	}
+	s.config.Emitter.Vector = vectorConfig
+	s.dragVectorPoint = false
+	s.dragVectorIndex = -1
+	s.showEmitterVectorPreview = true
+	s.applyChange(applyModeRecreate)
+}
+
+// importDroppedFiles imports the path data of .svg files and the pixels of
+// .png sprites dropped onto the window. SVG element transforms are not
+// applied.
+func (s *ParticleEditorScene) importDroppedFiles() {
+	files := ebiten.DroppedFiles()
+	if files == nil {
+		return
+	}
+	entries, err := fs.ReadDir(files, ".")
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		if strings.EqualFold(filepath.Ext(entry.Name()), ".png") {
+			s.importDroppedSprite(files, entry.Name())
+			return
+		}
+		if !strings.EqualFold(filepath.Ext(entry.Name()), ".svg") {
+			continue
+		}
+		data, err := fs.ReadFile(files, entry.Name())
+		if err != nil {
+			log.Println("SVG import error:", err)
+			continue
+		}
+		d, err := svgDocumentPathData(data)
+		if err != nil {
+			log.Println("SVG import error:", err)
+			continue
+		}
+		s.svgPathInput = d
+		s.importSVGPath(d)
+		return
+	}
+}
+
+// importDroppedSprite replaces the sprite emitter image with a dropped PNG
+// and turns the sprite emitter on.
+func (s *ParticleEditorScene) importDroppedSprite(files fs.FS, name string) {
+	f, err := files.Open(name)
+	if err != nil {
+		log.Println("Sprite import error:", err)
+		return
+	}
+	defer f.Close()
+	img, err := png.Decode(f)
+	if err != nil {
+		log.Println("Sprite import error:", err)
+		return
+	}
+	s.spriteImage = img
+	if s.config.Emitter.Sprite == nil {
+		s.config.Emitter.Sprite = defaultEditorSprite()
+	}
+	s.applyChange(applyModeRecreate)
+}
+
+// svgDocumentPathData joins the d attributes of every <path> element. Each
+// path after the first is preceded by "M0 0", so a leading relative m is
+// still measured from the origin, as it is in its own element; the lone
+// moveto draws nothing.
+func svgDocumentPathData(data []byte) (string, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	var paths []string
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "path" {
+			continue
+		}
+		for _, attr := range start.Attr {
+			if attr.Name.Local == "d" {
+				paths = append(paths, attr.Value)
+			}
+		}
+	}
+	if len(paths) == 0 {
+		return "", fmt.Errorf("no <path> elements with a d attribute")
+	}
+	return strings.Join(paths, " M0 0 "), nil
+}
+
+func (s *ParticleEditorScene) drawEmitterMotionControls(ctx *debugui.Context) {
+	motion := s.config.Emitter.Motion
+	label := "Emitter Motion: OFF"
+	if motion != nil {
+		label = "Emitter Motion: " + motion.Type
+	}
+	ctx.SetGridLayout([]int{180, 180}, nil)
+	ctx.Text(label)
+	ctx.Button("Cycle Motion").On(func() {
+		s.config.Emitter.Motion = nextEditorEmitterMotion(s.config.Emitter.Motion, s.config.Emitter.Vector)
+		s.applyChange(applyModeLive)
+	})
+	ctx.SetGridLayout([]int{-1}, nil)
+	if motion == nil {
+		return
+	}
+
+	switch motion.Type {
+	case "orbit":
+		s.sliderControl32(ctx, "Orbit Radius", &motion.Radius, 1, 300, 1)
+		s.sliderControl32(ctx, "Angular Speed", &motion.AngularSpeed, -12.0, 12.0, 0.1)
+		s.sliderControl32(ctx, "Phase", &motion.Phase, -3.14, 3.14, 0.05)
+	case "sway":
+		s.sliderControl32(ctx, "Amplitude X", &motion.AmplitudeX, -300, 300, 1)
+		s.sliderControl32(ctx, "Amplitude Y", &motion.AmplitudeY, -300, 300, 1)
+		s.sliderControl32(ctx, "Frequency", &motion.Frequency, 0.05, 5.0, 0.05)
+		s.sliderControl32(ctx, "Phase", &motion.Phase, -3.14, 3.14, 0.05)
+	case "path":
+		if motion.Path != nil {
+			ctx.Text(fmt.Sprintf("Path Points: %d", len(motion.Path.Points)))
+		}
+		s.sliderControl32(ctx, "Path Speed", &motion.Speed, 1, 1000, 5)
+		mode := motion.Mode
+		if mode == "" {
+			mode = "loop"
+		}
+		ctx.SetGridLayout([]int{180, 180}, nil)
+		ctx.Text("Path Mode: " + mode)
+		ctx.Button("Toggle Path Mode").On(func() {
+			if motion.Mode == "ping_pong" {
+				motion.Mode = "loop"
+			} else {
+				motion.Mode = "ping_pong"
+			}
+			s.applyChange(applyModeLive)
+		})
+		ctx.SetGridLayout([]int{-1}, nil)
+		if v := s.config.Emitter.Vector; v != nil && v.Type == "polyline" && v.Polyline != nil {
+			ctx.Button("Copy Vector Polyline to Path").On(func() {
+				motion.Path = copyEditorPolyline(v.Polyline)
+				s.applyChange(applyModeLive)
+			})
+		}
+	}
+}
+
+func (s *ParticleEditorScene) drawEmitterSpriteControls(ctx *debugui.Context) {
+	sprite := s.config.Emitter.Sprite
+	label := "Sprite Emitter: OFF"
+	if sprite != nil {
+		mode := sprite.Mode
+		if mode == "" {
+			mode = "disintegrate"
+		}
+		label = "Sprite Emitter: " + mode
+	}
+	ctx.SetGridLayout([]int{180, 180}, nil)
+	ctx.Text(label)
+	ctx.Button("Toggle Sprite").On(func() {
+		if s.config.Emitter.Sprite == nil {
+			s.config.Emitter.Sprite = defaultEditorSprite()
+		} else {
+			s.config.Emitter.Sprite = nil
+		}
+		s.applyChange(applyModeRecreate)
+	})
+	ctx.SetGridLayout([]int{-1}, nil)
+	if sprite == nil {
+		return
+	}
+
+	ctx.Text("Drop a .png file to use it as the sprite")
+	s.sliderIntControl(ctx, "Block Size", &sprite.BlockSize, 1, 16, 1, applyModeLive)
+	s.sliderControl32(ctx, "Sprite Scale", &sprite.Scale, 0.25, 8, 0.25)
+	s.sliderControl32(ctx, "Alpha Threshold", &sprite.AlphaThreshold, 0, 0.95, 0.05)
+	ctx.Button("Toggle Sprite Mode").On(func() {
+		if sprite.Mode == "assemble" {
+			sprite.Mode = "disintegrate"
+		} else {
+			sprite.Mode = "assemble"
+		}
+		s.applyChange(applyModeLive)
+	})
+}
+
+// defaultEditorSprite cuts the editor sprite into 2x2 blocks drawn 4x larger.
+func defaultEditorSprite() *chirashi.EmitterSpriteConfig {
+	return &chirashi.EmitterSpriteConfig{BlockSize: 2, Scale: 4, AlphaThreshold: 0.1}
+}
+
+// newEditorSprite draws a 32x32 slime with a vertical color ramp, used by
+// sprite emitters until a PNG is dropped.
+func newEditorSprite() *image.RGBA {
+	const size = 32
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			dx, dy := float64(x)-15.5, float64(y)-19.5
+			if dx*dx/(15*15)+dy*dy/(12*12) > 1 || y < 6 {
+				continue
+			}
+			t := float64(y-6) / (size - 6)
+			clr := color.NRGBA{R: uint8(80 + 100*t), G: uint8(220 - 120*t), B: 255, A: 255}
+			if (x == 10 || x == 11 || x == 20 || x == 21) && y >= 15 && y <= 18 {
+				clr = color.NRGBA{R: 20, G: 20, B: 40, A: 255}
+			}
+			img.Set(x, y, clr)
+		}
+	}
+	return img
+}
+
+// nextEditorEmitterMotion cycles OFF -> orbit -> sway -> path -> OFF. The path
+// starts from the vector polyline when one exists.
+func nextEditorEmitterMotion(motion *chirashi.EmitterMotionConfig, vector *chirashi.EmitterVectorConfig) *chirashi.EmitterMotionConfig {
+	if motion == nil {
+		return &chirashi.EmitterMotionConfig{Type: "orbit", Radius: 60, AngularSpeed: 3}
+	}
+	switch motion.Type {
+	case "orbit":
+		return &chirashi.EmitterMotionConfig{Type: "sway", AmplitudeX: 80, Frequency: 0.5}
+	case "sway":
+		path := defaultEditorPolylineVector().Polyline
+		if vector != nil && vector.Type == "polyline" && vector.Polyline != nil {
+			path = copyEditorPolyline(vector.Polyline)
+		}
+		return &chirashi.EmitterMotionConfig{Type: "path", Path: path, Speed: 200}
+	default:
+		return nil
+	}
+}
+
+func copyEditorPolyline(polyline *chirashi.EmitterVectorPolylineConfig) *chirashi.EmitterVectorPolylineConfig {
+	dst := *polyline
+	dst.Points = append([]chirashi.EmitterVectorPoint(nil), polyline.Points...)
+	return &dst
 }
 
 func (s *ParticleEditorScene) drawEmitterVectorControls(ctx *debugui.Context) {
@@ -425,6 +781,10 @@ func (s *ParticleEditorScene) drawEmitterVectorControls(ctx *debugui.Context) {
 			s.config.Emitter.Vector = defaultEditorPolylineVector()
 			s.applyChange(applyModeRecreate)
 		})
+		ctx.Button("Enable Text").On(func() {
+			s.config.Emitter.Vector = defaultEditorTextVector()
+			s.applyChange(applyModeRecreate)
+		})
 		ctx.SetGridLayout([]int{-1}, nil)
 		return
 	}
@@ -442,9 +802,9 @@ func (s *ParticleEditorScene) drawEmitterVectorControls(ctx *debugui.Context) {
 	ctx.SetGridLayout([]int{180, 180}, nil)
 	ctx.Text("Placement: " + s.vectorPlacementLabel())
 	ctx.Button("Toggle Placement").On(func() {
-		if vectorConfig.Type == "polyline" {
+		if vectorConfig.Type == "polyline" && (vectorConfig.Polyline == nil || !vectorConfig.Polyline.Closed) {
 			vectorConfig.Placement = "surface"
-		} else if vectorConfig.Placement == "surface" {
+		} else if vectorConfig.Placement == "surface" || vectorConfig.Placement == "" && vectorConfig.Type == "polyline" {
 			vectorConfig.Placement = "fill"
 		} else {
 			vectorConfig.Placement = "surface"
@@ -470,8 +830,16 @@ func (s *ParticleEditorScene) drawEmitterVectorControls(ctx *debugui.Context) {
 			}
 			ctx.Button(closedLabel).On(func() {
 				vectorConfig.Polyline.Closed = !vectorConfig.Polyline.Closed
+				if !vectorConfig.Polyline.Closed {
+					// Fill and holes need a closed outline.
+					vectorConfig.Placement = "surface"
+					vectorConfig.Polyline.Holes = nil
+				}
 				s.applyChange(applyModeRecreate)
 			})
+			if len(vectorConfig.Polyline.Holes) > 0 {
+				ctx.Text(fmt.Sprintf("Holes: %d (edit in YAML)", len(vectorConfig.Polyline.Holes)))
+			}
 			ctx.SetGridLayout([]int{180, 180}, nil)
 			ctx.Text("Interpolation: " + s.polylineInterpolationLabel())
 			ctx.Button("Toggle Curve").On(func() {
@@ -497,6 +865,33 @@ func (s *ParticleEditorScene) drawEmitterVectorControls(ctx *debugui.Context) {
 			ctx.Text("Shift+Click adds a point at the cursor")
 			ctx.Text("Right click removes the nearest linear point")
 		}
+	case "text":
+		if vectorConfig.Text == nil {
+			vectorConfig.Text = defaultEditorTextVector().Text
+		}
+		ctx.Text("Text (the editor previews every font name with M+ 1p)")
+		ctx.TextField(&vectorConfig.Text.Value).On(func() {
+			s.applyChange(applyModeRecreate)
+		})
+		s.sliderControl32WithMode(ctx, "Text Size", &vectorConfig.Text.Size, 8, 320, 1, applyModeRecreate)
+		align := vectorConfig.Text.Align
+		if align == "" {
+			align = "center"
+		}
+		ctx.SetGridLayout([]int{180, 180}, nil)
+		ctx.Text("Align: " + align)
+		ctx.Button("Cycle Align").On(func() {
+			switch align {
+			case "left":
+				vectorConfig.Text.Align = "center"
+			case "center":
+				vectorConfig.Text.Align = "right"
+			default:
+				vectorConfig.Text.Align = "left"
+			}
+			s.applyChange(applyModeRecreate)
+		})
+		ctx.SetGridLayout([]int{-1}, nil)
 	}
 
 	ctx.SetGridLayout([]int{180, 180}, nil)
@@ -515,6 +910,13 @@ func (s *ParticleEditorScene) drawEmitterVectorControls(ctx *debugui.Context) {
 		s.config.Emitter.Vector = defaultEditorPolylineVector()
 		s.applyChange(applyModeRecreate)
 	})
+	ctx.Button("Use Text").On(func() {
+		if s.config.Emitter.Vector != nil && s.config.Emitter.Vector.Type == "text" {
+			return
+		}
+		s.config.Emitter.Vector = defaultEditorTextVector()
+		s.applyChange(applyModeRecreate)
+	})
 	ctx.SetGridLayout([]int{-1}, nil)
 
 	ctx.Button("Disable Vector").On(func() {
@@ -618,6 +1020,25 @@ func (s *ParticleEditorScene) drawShaderControls(ctx *debugui.Context) {
 	})
 	ctx.SetGridLayout([]int{-1}, nil)
 
+	sortMode := s.config.Render.Sort
+	if sortMode == "" {
+		sortMode = "none"
+	}
+	ctx.SetGridLayout([]int{180, 180}, nil)
+	ctx.Text("Draw Sort: " + sortMode)
+	ctx.Button("Cycle Sort").On(func() {
+		modes := []string{"", "oldest_first", "youngest_first", "by_y", "by_scale"}
+		next := 0
+		for i, m := range modes {
+			if m == s.config.Render.Sort {
+				next = (i + 1) % len(modes)
+			}
+		}
+		s.config.Render.Sort = modes[next]
+		s.applyChange(applyModeLive)
+	})
+	ctx.SetGridLayout([]int{-1}, nil)
+
 	blendLabel := "Blend: Normal"
 	if s.config.Blend == "additive" {
 		blendLabel = "Blend: Additive"
@@ -753,10 +1174,10 @@ func (s *ParticleEditorScene) vectorPlacementLabel() string {
 	if s.config.Emitter.Vector == nil {
 		return "fill"
 	}
-	if s.config.Emitter.Vector.Type == "polyline" {
-		return "surface"
-	}
 	if s.config.Emitter.Vector.Placement == "" {
+		if s.config.Emitter.Vector.Type == "polyline" {
+			return "surface"
+		}
 		return "fill"
 	}
 	return s.config.Emitter.Vector.Placement
@@ -801,11 +1222,32 @@ func (s *ParticleEditorScene) drawEmitterVectorPreview(screen *ebiten.Image) {
 			b := rotatePreviewPoint(corners[(i+1)%len(corners)], rect.Rotation)
 			vector.StrokeLine(screen, originX+a.X, originY+a.Y, originX+b.X, originY+b.Y, 2, previewColor, true)
 		}
+	case "text":
+		if s.textPreview == nil {
+			return
+		}
+		for _, polyline := range append([]chirashi.EmitterVectorPolylineConfig{*s.textPreview.Polyline}, s.textPreview.Polylines...) {
+			strokePreviewOutline(screen, originX, originY, polyline.Points, true, previewColor)
+			for _, hole := range polyline.Holes {
+				strokePreviewOutline(screen, originX, originY, hole, true, previewColor)
+			}
+		}
 	case "polyline":
+		// Extra outlines and holes are previewed without editing handles.
+		for i := range s.config.Emitter.Vector.Polylines {
+			extra := &s.config.Emitter.Vector.Polylines[i]
+			strokePreviewOutline(screen, originX, originY, previewPolylinePoints(extra), extra.Closed, previewColor)
+			for _, hole := range extra.Holes {
+				strokePreviewOutline(screen, originX, originY, hole, true, previewColor)
+			}
+		}
 		polyline := s.config.Emitter.Vector.Polyline
 		if polyline == nil || len(polyline.Points) < 2 {
 			return
 		}
+		for _, hole := range polyline.Holes {
+			strokePreviewOutline(screen, originX, originY, hole, true, previewColor)
+		}
 		previewPoints := previewPolylinePoints(polyline)
 		for i := 0; i < len(previewPoints)-1; i++ {
 			a := previewPoints[i]
@@ -996,6 +1438,8 @@ func (s *ParticleEditorScene) togglePolylineInterpolation() {
 		polyline.Interpolation = "linear"
 		polyline.CurveSteps = 0
 		polyline.Closed = false
+		polyline.Holes = nil
+		s.config.Emitter.Vector.Placement = "surface"
 		return
 	}
 	if len(polyline.Points) < 2 {
@@ -1018,6 +1462,16 @@ func (s *ParticleEditorScene) togglePolylineInterpolation() {
 		polyline.CurveSteps = 12
 	}
 	polyline.Closed = false
+	polyline.Holes = nil
+	s.config.Emitter.Vector.Placement = "surface"
+}
+
+func defaultEditorTextVector() *chirashi.EmitterVectorConfig {
+	return &chirashi.EmitterVectorConfig{
+		Type:      "text",
+		Placement: "fill",
+		Text:      &chirashi.EmitterVectorTextConfig{Value: "x5", Size: 160},
+	}
 }
 
 func defaultEditorPolylineVector() *chirashi.EmitterVectorConfig {
@@ -1034,6 +1488,19 @@ func defaultEditorPolylineVector() *chirashi.EmitterVectorConfig {
 	}
 }
 
+func strokePreviewOutline(screen *ebiten.Image, originX, originY float32, points []chirashi.EmitterVectorPoint, closed bool, clr color.Color) {
+	for i := 0; i+1 < len(points); i++ {
+		a := points[i]
+		b := points[i+1]
+		vector.StrokeLine(screen, originX+a.X, originY+a.Y, originX+b.X, originY+b.Y, 2, clr, true)
+	}
+	if closed && len(points) > 2 {
+		a := points[len(points)-1]
+		b := points[0]
+		vector.StrokeLine(screen, originX+a.X, originY+a.Y, originX+b.X, originY+b.Y, 2, clr, true)
+	}
+}
+
 func previewPolylinePoints(polyline *chirashi.EmitterVectorPolylineConfig) []chirashi.EmitterVectorPoint {
 	return chirashi.CompileEmitterVectorPolylinePoints(polyline)
 }
@@ -1062,6 +1529,16 @@ func (s *ParticleEditorScene) setPositionMode(mode string) {
 		if s.config.Animation.Position.Distance == nil {
 			s.config.Animation.Position.Distance = &chirashi.RangeFloat{Min: 50, Max: 150}
 		}
+	case "physics":
+		if s.config.Animation.Position.Angle == nil {
+			s.config.Animation.Position.Angle = &chirashi.RangeFloat{Min: -2.0, Max: -1.1}
+		}
+		if s.config.Animation.Position.Speed == nil {
+			s.config.Animation.Position.Speed = &chirashi.RangeFloat{Min: 220, Max: 340}
+		}
+		if s.config.Animation.Position.AccelerationY == 0 {
+			s.config.Animation.Position.AccelerationY = 500
+		}
 	case "attractor":
 		if s.config.Animation.Position.ControlX == nil {
 			s.config.Animation.Position.ControlX = &chirashi.RangeFloat{Min: -100, Max: 100}
@@ -1133,17 +1610,28 @@ func (s *ParticleEditorScene) drawPositionSection(ctx *debugui.Context) {
 	if posType == "" {
 		posType = "cartesian"
 	}
-	ctx.SetGridLayout([]int{180, 110, 110, 110}, nil)
+	ctx.SetGridLayout([]int{180, 110, 110, 110, 110}, nil)
 	ctx.Text("Position: " + posType)
 	ctx.Button("Cartesian").On(func() { s.setPositionMode("cartesian") })
 	ctx.Button("Polar").On(func() { s.setPositionMode("polar") })
 	ctx.Button("Attractor").On(func() { s.setPositionMode("attractor") })
+	ctx.Button("Physics").On(func() { s.setPositionMode("physics") })
 	ctx.SetGridLayout([]int{-1}, nil)
 
 	pos := &s.config.Animation.Position
 	isPolarVelocity := pos.Type == "polar" && (pos.Speed != nil || pos.AngularSpeed != nil)
+	isPhysics := pos.Type == "physics"
 
 	switch s.config.Animation.Position.Type {
+	case "physics":
+		s.rangeControl(ctx, "Launch Angle", pos.Angle, -6.283185, 6.283185, 0.1)
+		s.rangeControl(ctx, "Launch Speed", pos.Speed, 0, 1000, 10)
+		s.rangeControl(ctx, "Spawn Offset", pos.Distance, 0, 500, 10)
+		s.sliderControl32(ctx, "Accel X", &pos.AccelerationX, -2000, 2000, 10)
+		s.sliderControl32(ctx, "Accel Y (gravity)", &pos.AccelerationY, -2000, 2000, 10)
+		s.sliderControl32(ctx, "Linear Drag", &pos.LinearDrag, 0, 10, 0.05)
+		s.sliderControl32(ctx, "Quadratic Drag", &pos.QuadraticDrag, 0, 0.1, 0.001)
+		s.sliderControl32(ctx, "Terminal Speed", &pos.TerminalSpeed, 0, 2000, 10)
 	case "polar":
 		s.rangeControl(ctx, "Angle", pos.Angle, 0, 6.283185, 0.1)
 		if !isPolarVelocity {
@@ -1217,7 +1705,7 @@ func (s *ParticleEditorScene) drawPositionSection(ctx *debugui.Context) {
 		s.rangeControl(ctx, "End Y", s.config.Animation.Position.EndY, -500, 500, 10)
 	}
 
-	if isPolarVelocity {
+	if isPolarVelocity || isPhysics {
 		ctx.SetGridLayout([]int{-1}, nil)
 		ctx.Text("Easing: N/A (velocity mode)")
 	} else {
@@ -1308,6 +1796,10 @@ func (s *ParticleEditorScene) drawPropertyWindow(ctx *debugui.Context, label str
 		s.sequenceControls(ctx, label, config, min, max, step)
 		return
 	}
+	if config.IsCurve() {
+		s.curveControls(ctx, label, config, min, max, step)
+		return
+	}
 
 	start := float64(config.Start)
 	end := float64(config.End)
@@ -1829,17 +2321,74 @@ func (s *ParticleEditorScene) sequenceControls(ctx *debugui.Context, label strin
 	})
 }
 
+func (s *ParticleEditorScene) drawScaleAxesControls(ctx *debugui.Context) {
+	anim := &s.config.Animation
+	separate := anim.ScaleX != nil || anim.ScaleY != nil
+	ctx.Button(fmt.Sprintf("Separate X/Y Scale: %v", separate)).On(func() {
+		if separate {
+			anim.ScaleX = nil
+			anim.ScaleY = nil
+		} else {
+			// Start both axes from the uniform scale so the look is unchanged.
+			scaleX := chirashi.PropertyConfig{Start: anim.Scale.Start, End: anim.Scale.End, Easing: anim.Scale.Easing}
+			scaleY := scaleX
+			anim.ScaleX = &scaleX
+			anim.ScaleY = &scaleY
+		}
+		s.applyChange(applyModeLive)
+	})
+	if anim.ScaleX != nil {
+		s.drawPropertyWindow(ctx, "Scale X", anim.ScaleX, 0.0, 5.0, 0.1, "scale_x")
+	}
+	if anim.ScaleY != nil {
+		s.drawPropertyWindow(ctx, "Scale Y", anim.ScaleY, 0.0, 5.0, 0.1, "scale_y")
+	}
+}
+
+func (s *ParticleEditorScene) drawAlignControls(ctx *debugui.Context) {
+	anim := &s.config.Animation
+	aligned := anim.Align == "velocity"
+	ctx.Button(fmt.Sprintf("Align To Velocity: %v", aligned)).On(func() {
+		if aligned {
+			anim.Align = ""
+			anim.Stretch = 0
+		} else {
+			anim.Align = "velocity"
+		}
+		s.applyChange(applyModeLive)
+	})
+	if aligned {
+		ctx.IDScope("align_stretch", func() {
+			stretch := float64(anim.Stretch)
+			s.numericControl(ctx, "Stretch", &stretch, 0, 0.1, 0.001, 3, applyModeLive)
+			anim.Stretch = float32(stretch)
+		})
+	}
+}
+
 func (s *ParticleEditorScene) propertyModeToggle(ctx *debugui.Context, label string, config *chirashi.PropertyConfig) {
+	// Cycles Simple -> Sequence -> Curve -> Simple.
 	isSeq := config.IsSequence()
+	isCurve := config.IsCurve()
 	modeLabel := "Simple"
 	if isSeq {
 		modeLabel = "Sequence"
+	} else if isCurve {
+		modeLabel = "Curve"
 	}
 	ctx.Button(fmt.Sprintf("%s Mode: %s", label, modeLabel)).On(func() {
-		if isSeq {
-			config.Type = ""
+		switch {
+		case isSeq:
+			config.Type = "curve"
 			config.Steps = nil
-		} else {
+			config.Keys = []chirashi.CurveKeyConfig{
+				{T: 0, Value: config.Start},
+				{T: 1, Value: config.End},
+			}
+		case isCurve:
+			config.Type = ""
+			config.Keys = nil
+		default:
 			config.Type = "sequence"
 			config.Steps = []chirashi.StepConfig{
 				{From: config.Start, To: config.End, Duration: 1.0, Easing: config.Easing},
@@ -1849,6 +2398,41 @@ func (s *ParticleEditorScene) propertyModeToggle(ctx *debugui.Context, label str
 	})
 }
 
+func (s *ParticleEditorScene) curveControls(ctx *debugui.Context, label string, config *chirashi.PropertyConfig, min, max, stepVal float64) {
+	ctx.IDScope(label+"_curve", func() {
+		ctx.SetGridLayout([]int{-1}, nil)
+
+		for i := range config.Keys {
+			ctx.IDScope(fmt.Sprintf("Key_%d", i), func() {
+				key := &config.Keys[i]
+
+				ctx.Text(fmt.Sprintf("Key %d", i+1))
+				s.sliderControl32(ctx, "Time", &key.T, 0, 1, 0.01)
+				s.sliderControl32(ctx, "Value", &key.Value, min, max, stepVal)
+
+				ctx.Button("  Remove Key").On(func() {
+					config.Keys = append(config.Keys[:i], config.Keys[i+1:]...)
+					if len(config.Keys) == 0 {
+						config.Type = ""
+					}
+					s.applyChange(applyModeLive)
+				})
+
+				ctx.Text("----------------")
+			})
+		}
+
+		ctx.Button("Add Key").On(func() {
+			newKey := chirashi.CurveKeyConfig{T: 1}
+			if len(config.Keys) > 0 {
+				newKey.Value = config.Keys[len(config.Keys)-1].Value
+			}
+			config.Keys = append(config.Keys, newKey)
+			s.applyChange(applyModeLive)
+		})
+	})
+}
+
 func (s *ParticleEditorScene) openEasingPicker(title string, current func() string, apply func(string)) {
 	s.easingPicker = &easingPickerState{
 		title:   title,